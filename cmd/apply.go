@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"groundcover.com/pkg/fleet"
+	"groundcover.com/pkg/segment"
+	"groundcover.com/pkg/ui"
+)
+
+const (
+	FLEET_FILE_FLAG  = "file"
+	APPLY_EVENT_NAME = "fleet_apply"
+)
+
+func init() {
+	RootCmd.AddCommand(ApplyCmd)
+
+	ApplyCmd.Flags().StringP(FLEET_FILE_FLAG, "f", "", "path to a fleet file declaring the clusters to deploy")
+	ApplyCmd.MarkFlagRequired(FLEET_FILE_FLAG)
+
+	ApplyCmd.Flags().Int(PARALLEL_FLAG, DEFAULT_PARALLEL, "maximal number of clusters deployed concurrently")
+	ApplyCmd.Flags().Bool(DRY_RUN_FLAG, false, "validate the clusters and print the rendered chart values and manifests without installing")
+}
+
+var ApplyCmd = &cobra.Command{
+	Use:     "apply",
+	Short:   "Deploy groundcover to every cluster declared in a fleet file",
+	Example: "groundcover apply -f fleet.yaml",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+
+		event := segment.NewEvent(APPLY_EVENT_NAME)
+		event.Start()
+		defer func() {
+			event.StatusByError(err)
+		}()
+
+		var fleetFile string
+		if fleetFile, err = cmd.Flags().GetString(FLEET_FILE_FLAG); err != nil {
+			return err
+		}
+
+		var parallel int
+		if parallel, err = cmd.Flags().GetInt(PARALLEL_FLAG); err != nil {
+			return err
+		}
+
+		if parallel < 1 {
+			err = fmt.Errorf("--%s must be at least 1", PARALLEL_FLAG)
+			return err
+		}
+
+		var clustersFleet *fleet.Fleet
+		if clustersFleet, err = fleet.LoadFleet(fleetFile); err != nil {
+			return err
+		}
+
+		clusters := clustersFleet.ResolvedClusters()
+		event.Set("clustersCount", len(clusters))
+
+		kubecontexts := make([]string, 0, len(clusters))
+		clusterDeployments := make([]*clusterDeployment, 0, len(clusters))
+		for _, cluster := range clusters {
+			kubecontexts = append(kubecontexts, cluster.Context)
+			clusterDeployments = append(clusterDeployments, &clusterDeployment{
+				kubecontext: cluster.Context,
+				args: clusterDeployArgs(cmd, cluster.Context, fleetClusterArgs(cluster),
+					FLEET_FILE_FLAG, PARALLEL_FLAG, NAMESPACE_FLAG, HELM_RELEASE_FLAG, CLUSTER_NAME_FLAG,
+				),
+			})
+		}
+
+		promptMessage := fmt.Sprintf("Apply fleet %s to %d clusters (contexts: %s)", fleetFile, len(clusters), strings.Join(kubecontexts, ", "))
+		if !ui.GlobalWriter.YesNoPrompt(promptMessage, true) {
+			return ErrExecutionAborted
+		}
+
+		err = runClusterDeployments(ctx, clusterDeployments, parallel)
+		return err
+	},
+}
+
+// fleetClusterArgs returns the deploy flags of a cluster, its values are built from the fleet file alone
+// so settings removed from the fleet file are reverted instead of being kept from the installed release
+func fleetClusterArgs(cluster *fleet.Cluster) []string {
+	args := []string{fmt.Sprintf("--%s", RESET_VALUES_FLAG)}
+
+	stringFlags := []struct {
+		name  string
+		value string
+	}{
+		{NAMESPACE_FLAG, cluster.Namespace},
+		{HELM_RELEASE_FLAG, cluster.ReleaseName},
+		{CLUSTER_NAME_FLAG, cluster.ClusterName},
+		{VERSION_FLAG, cluster.Version},
+		{MODE_FLAG, cluster.Mode},
+		{REGISTRY_FLAG, cluster.Registry},
+		{STORAGE_CLASS_FLAG, cluster.StorageClass},
+		{NODE_SELECTOR_FLAG, cluster.NodeSelector},
+		{EXCLUDE_NODE_SELECTOR_FLAG, cluster.ExcludeNodeSelector},
+	}
+
+	for _, flag := range stringFlags {
+		if flag.value != "" {
+			args = append(args, fmt.Sprintf("--%s=%s", flag.name, flag.value))
+		}
+	}
+
+	sliceFlags := []struct {
+		name   string
+		values []string
+	}{
+		{VALUES_FLAG, cluster.Values},
+		{TOLERATE_FLAG, cluster.Tolerate},
+		{EXCLUDE_NODES_FLAG, cluster.ExcludeNodes},
+	}
+
+	for _, flag := range sliceFlags {
+		for _, value := range flag.values {
+			args = append(args, fmt.Sprintf("--%s=%s", flag.name, value))
+		}
+	}
+
+	boolFlags := []struct {
+		name  string
+		value *bool
+	}{
+		{LOW_RESOURCES_FLAG, cluster.LowResources},
+		{ENABLE_CUSTOM_METRICS_FLAG, cluster.CustomMetrics},
+		{ENABLE_KUBE_STATE_METRICS_FLAG, cluster.KubeStateMetrics},
+		{STORE_ISSUES_LOGS_ONLY_FLAG, cluster.StoreIssuesLogsOnly},
+		{TOLERATE_ALL_TAINTS_FLAG, cluster.TolerateAllTaints},
+	}
+
+	for _, flag := range boolFlags {
+		if flag.value != nil {
+			args = append(args, fmt.Sprintf("--%s=%t", flag.name, *flag.value))
+		}
+	}
+
+	return args
+}
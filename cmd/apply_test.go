@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/fleet"
+)
+
+func TestFleetClusterArgs(t *testing.T) {
+	lowResources := true
+	customMetrics := false
+
+	cluster := &fleet.Cluster{
+		Context:     "prod-eu",
+		ClusterName: "prod",
+		Settings: fleet.Settings{
+			Namespace:     "observability",
+			Version:       "^1.0.0",
+			Values:        []string{"/fleet/common.yaml", "/fleet/prod.yaml"},
+			LowResources:  &lowResources,
+			CustomMetrics: &customMetrics,
+		},
+	}
+
+	assert.Equal(t, []string{
+		"--reset-values",
+		"--namespace=observability",
+		"--cluster-name=prod",
+		"--version=^1.0.0",
+		"--values=/fleet/common.yaml",
+		"--values=/fleet/prod.yaml",
+		"--low-resources=true",
+		"--custom-metrics=false",
+	}, fleetClusterArgs(cluster))
+}
+
+func TestFleetClusterArgsNodeTargeting(t *testing.T) {
+	tolerateAllTaints := true
+
+	cluster := &fleet.Cluster{
+		Context: "gpu",
+		Settings: fleet.Settings{
+			TolerateAllTaints:   &tolerateAllTaints,
+			NodeSelector:        "kubernetes.io/os=linux",
+			ExcludeNodeSelector: "node-role in (gpu,batch)",
+			ExcludeNodes:        []string{"node-1", "node-2"},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"--reset-values",
+		"--node-selector=kubernetes.io/os=linux",
+		"--exclude-node-selector=node-role in (gpu,batch)",
+		"--exclude-nodes=node-1",
+		"--exclude-nodes=node-2",
+		"--tolerate-all-taints=true",
+	}, fleetClusterArgs(cluster))
+
+	cluster.Settings = fleet.Settings{Tolerate: []string{"dedicated=monitoring:NoSchedule", "none"}}
+
+	assert.Equal(t, []string{
+		"--reset-values",
+		"--tolerate=dedicated=monitoring:NoSchedule",
+		"--tolerate=none",
+	}, fleetClusterArgs(cluster))
+}
+
+func TestFleetClusterDeployArgsParse(t *testing.T) {
+	cluster := &fleet.Cluster{
+		Context:  "prod",
+		Settings: fleet.Settings{Tolerate: []string{"dedicated=monitoring:NoSchedule"}, ExcludeNodes: []string{"node-1"}},
+	}
+
+	deployCmd := &cobra.Command{Use: DEPLOY_COMMAND_NAME}
+	addDeployFlags(deployCmd)
+
+	assert.NoError(t, deployCmd.ParseFlags(fleetClusterArgs(cluster)))
+
+	tolerate, err := deployCmd.Flags().GetStringSlice(TOLERATE_FLAG)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dedicated=monitoring:NoSchedule"}, tolerate)
+
+	excludeNodes, err := deployCmd.Flags().GetStringSlice(EXCLUDE_NODES_FLAG)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node-1"}, excludeNodes)
+}
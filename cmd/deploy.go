@@ -3,14 +3,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/blang/semver/v4"
 	"github.com/getsentry/sentry-go"
 	"github.com/imdario/mergo"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 	"groundcover.com/pkg/api"
 	"groundcover.com/pkg/auth"
 	"groundcover.com/pkg/helm"
@@ -19,6 +23,9 @@ import (
 	sentry_utils "groundcover.com/pkg/sentry"
 	"groundcover.com/pkg/ui"
 	"groundcover.com/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
@@ -28,12 +35,24 @@ const (
 	VALUES_FLAG                       = "values"
 	MODE_FLAG                         = "mode"
 	VERSION_FLAG                      = "version"
+	DRY_RUN_FLAG                      = "dry-run"
 	REGISTRY_FLAG                     = "registry"
 	STORAGE_CLASS_FLAG                = "storage-class"
 	LOW_RESOURCES_FLAG                = "low-resources"
 	ENABLE_CUSTOM_METRICS_FLAG        = "custom-metrics"
 	ENABLE_KUBE_STATE_METRICS_FLAG    = "kube-state-metrics"
 	STORE_ISSUES_LOGS_ONLY_FLAG       = "store-issues-logs-only"
+	TOLERATE_FLAG                     = "tolerate"
+	TOLERATE_ALL_TAINTS_FLAG          = "tolerate-all-taints"
+	TOLERATE_TAINT_FLAG               = "tolerate-taint"
+	RESET_VALUES_FLAG                 = "reset-values"
+	NODE_SELECTOR_FLAG                = "node-selector"
+	EXCLUDE_NODE_SELECTOR_FLAG        = "exclude-node-selector"
+	EXCLUDE_NODES_FLAG                = "exclude-nodes"
+	UNSUPPORTED_NODES_FORMAT          = "Excluding %d nodes from the agent, %s: %s"
+	INSUFFICIENT_NODES_FORMAT         = "%d nodes don't have enough free resources for the agent, their sensors will stay pending until resources are freed"
+	NO_TAINTS_VALUE                   = "none"
+	TAINTS_PROMPT_MESSAGE             = "Do you want set tolerations to allow scheduling groundcover on following taints:"
 	STORE_ISSUES_LOGS_ONLY_KEY        = "storeIssuesLogsOnly"
 	CHART_NAME                        = "groundcover/groundcover"
 	HELM_REPO_NAME                    = "groundcover"
@@ -58,48 +77,74 @@ const (
 	GET_CHART_POLLING_TIMEOUT         = time.Second * 10
 	LEGACY_MODE                       = "legacy"
 	STABLE_MODE                       = "stable"
+	RELEASE_DESCRIPTION_FORMAT        = "groundcover cli %s: %s"
+	RELEASE_PRESETS_FORMAT            = "%s (presets: %s)"
+	LOW_RESOURCES_PRESET_REASON       = "low resources flag or local cluster"
+	PREVIEW_API_KEY                   = "<ingestion-key>"
+	PREVIEW_KERNEL_PROBE_MESSAGE      = "Kernel probe is skipped in preview, it runs jobs in the cluster"
+	AGENT_AFFINITY_OVERRIDE_MESSAGE   = "agent.affinity in the values files overrides the agent affinity generated from the node targeting flags and the excluded nodes"
 
 	NODES_VALIDATION_EVENT_NAME     = "nodes_validation"
+	RESOURCES_VALIDATION_EVENT_NAME = "agent_resources_validation"
 	HELM_INSTALLATION_EVENT_NAME    = "helm_installation"
 	CLUSTER_VALIDATION_EVENT_NAME   = "cluster_validation"
 	CLUSTER_REGISTRATION_EVENT_NAME = "cluster_registration"
 )
 
+var (
+	HelmDeployPollingPolicy = ui.PollingPolicy{
+		Phase:    "helm",
+		Interval: HELM_DEPLOY_POLLING_INTERVAL,
+		Timeout:  HELM_DEPLOY_POLLING_TIMEOUT,
+		Retries:  HELM_DEPLOY_POLLING_RETRIES,
+	}
+	GetChartPollingPolicy = ui.PollingPolicy{
+		Phase:    "chart",
+		Interval: GET_CHART_POLLING_INTERVAL,
+		Timeout:  GET_CHART_POLLING_TIMEOUT,
+		Retries:  GET_CHART_POLLING_RETRIES,
+	}
+)
+
 func init() {
 	RootCmd.AddCommand(DeployCmd)
 
-	DeployCmd.PersistentFlags().StringSliceP(VALUES_FLAG, "f", []string{}, "specify values in a YAML file or a URL (can specify multiple)")
-	viper.BindPFlag(VALUES_FLAG, DeployCmd.PersistentFlags().Lookup(VALUES_FLAG))
-
-	DeployCmd.PersistentFlags().String(MODE_FLAG, "", "deployment mode [options: stable, legacy, experimental]")
-	viper.BindPFlag(MODE_FLAG, DeployCmd.PersistentFlags().Lookup(MODE_FLAG))
-
-	DeployCmd.PersistentFlags().String(REGISTRY_FLAG, "ecr", "image registry [options: ecr, quay]")
-	viper.BindPFlag(REGISTRY_FLAG, DeployCmd.PersistentFlags().Lookup(REGISTRY_FLAG))
-
-	DeployCmd.PersistentFlags().String(STORAGE_CLASS_FLAG, "", "override storage class")
-	viper.BindPFlag(STORAGE_CLASS_FLAG, DeployCmd.PersistentFlags().Lookup(STORAGE_CLASS_FLAG))
-
-	DeployCmd.PersistentFlags().Bool(LOW_RESOURCES_FLAG, false, "set low resources limits")
-	viper.BindPFlag(LOW_RESOURCES_FLAG, DeployCmd.PersistentFlags().Lookup(LOW_RESOURCES_FLAG))
-
-	DeployCmd.PersistentFlags().Bool(STORE_ISSUES_LOGS_ONLY_FLAG, false, "store issues logs only")
-	viper.BindPFlag(STORE_ISSUES_LOGS_ONLY_FLAG, DeployCmd.PersistentFlags().Lookup(STORE_ISSUES_LOGS_ONLY_FLAG))
-
-	DeployCmd.PersistentFlags().Bool(ENABLE_CUSTOM_METRICS_FLAG, false, "enable custom metrics scraping")
-	viper.BindPFlag(ENABLE_CUSTOM_METRICS_FLAG, DeployCmd.PersistentFlags().Lookup(ENABLE_CUSTOM_METRICS_FLAG))
+	addDeployFlags(DeployCmd)
+	viper.BindPFlags(DeployCmd.PersistentFlags())
 
-	DeployCmd.PersistentFlags().Bool(ENABLE_KUBE_STATE_METRICS_FLAG, false, "enable kube state metrics deployment")
-	viper.BindPFlag(ENABLE_KUBE_STATE_METRICS_FLAG, DeployCmd.PersistentFlags().Lookup(ENABLE_KUBE_STATE_METRICS_FLAG))
+	DeployCmd.Flags().Bool(DRY_RUN_FLAG, false, "validate the cluster and print the rendered chart values and manifests without installing")
+	viper.BindPFlag(DRY_RUN_FLAG, DeployCmd.Flags().Lookup(DRY_RUN_FLAG))
 
-	DeployCmd.PersistentFlags().String(COMMIT_HASH_KEY_NAME_FLAG, "", "the annotation/label key name that contains the app git commit hash")
-	viper.BindPFlag(COMMIT_HASH_KEY_NAME_FLAG, DeployCmd.PersistentFlags().Lookup(COMMIT_HASH_KEY_NAME_FLAG))
+	DeployCmd.Flags().StringSlice(CONTEXTS_FLAG, []string{}, "deploy to multiple kubeconfig contexts, supports glob patterns (e.g. prod-*)")
+	viper.BindPFlag(CONTEXTS_FLAG, DeployCmd.Flags().Lookup(CONTEXTS_FLAG))
 
-	DeployCmd.PersistentFlags().String(REPOSITORY_URL_KEY_NAME_FLAG, "", "the annotation key name that contains the app git repository url")
-	viper.BindPFlag(REPOSITORY_URL_KEY_NAME_FLAG, DeployCmd.PersistentFlags().Lookup(REPOSITORY_URL_KEY_NAME_FLAG))
+	DeployCmd.Flags().Int(PARALLEL_FLAG, DEFAULT_PARALLEL, "maximal number of clusters deployed concurrently when using --contexts")
+	viper.BindPFlag(PARALLEL_FLAG, DeployCmd.Flags().Lookup(PARALLEL_FLAG))
+}
 
-	DeployCmd.PersistentFlags().String(VERSION_FLAG, "", "specify a version constraint for the chart version to use. This constraint can be a specific tag (e.g. 1.1.1) or it may reference a valid range (e.g. ^2.0.0). If this is not specified, the latest version is used")
-	viper.BindPFlag(VERSION_FLAG, DeployCmd.PersistentFlags().Lookup(VERSION_FLAG))
+func addDeployFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSliceP(VALUES_FLAG, "f", []string{}, "specify values in a YAML file or a URL (can specify multiple)")
+	cmd.PersistentFlags().String(MODE_FLAG, "", "deployment mode [options: stable, legacy, experimental]")
+	cmd.PersistentFlags().String(REGISTRY_FLAG, "ecr", "image registry [options: ecr, quay]")
+	cmd.PersistentFlags().String(STORAGE_CLASS_FLAG, "", "override storage class")
+	cmd.PersistentFlags().Bool(LOW_RESOURCES_FLAG, false, "set low resources limits")
+	cmd.PersistentFlags().Bool(STORE_ISSUES_LOGS_ONLY_FLAG, false, "store issues logs only")
+	cmd.PersistentFlags().Bool(ENABLE_CUSTOM_METRICS_FLAG, false, "enable custom metrics scraping")
+	cmd.PersistentFlags().Bool(ENABLE_KUBE_STATE_METRICS_FLAG, false, "enable kube state metrics deployment")
+	cmd.PersistentFlags().Bool(RESET_VALUES_FLAG, false, "build the values from the flags and values files alone instead of upgrading the installed release values")
+	cmd.PersistentFlags().StringSlice(TOLERATE_FLAG, []string{}, "tolerate node taints matching key[=value][:effect] instead of prompting, \"none\" tolerates no taints (can specify multiple)")
+	cmd.PersistentFlags().Bool(TOLERATE_ALL_TAINTS_FLAG, false, "tolerate all node taints instead of prompting")
+	cmd.PersistentFlags().StringSlice(TOLERATE_TAINT_FLAG, []string{}, "tolerate node taints matching key[=value][:effect] instead of prompting (can specify multiple)")
+	cmd.PersistentFlags().MarkDeprecated(TOLERATE_TAINT_FLAG, fmt.Sprintf("use --%s instead", TOLERATE_FLAG))
+	cmd.PersistentFlags().String(NODE_SELECTOR_FLAG, "", "deploy the agent only on nodes matching this label selector (e.g. kubernetes.io/os=linux)")
+	cmd.PersistentFlags().String(EXCLUDE_NODE_SELECTOR_FLAG, "", "don't deploy the agent on nodes matching this label selector (e.g. node-role=gpu)")
+	cmd.PersistentFlags().StringSlice(EXCLUDE_NODES_FLAG, []string{}, "don't deploy the agent on these nodes (can specify multiple)")
+	cmd.MarkFlagsMutuallyExclusive(TOLERATE_FLAG, TOLERATE_ALL_TAINTS_FLAG)
+	cmd.MarkFlagsMutuallyExclusive(TOLERATE_TAINT_FLAG, TOLERATE_ALL_TAINTS_FLAG)
+	addKernelProbeFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().String(COMMIT_HASH_KEY_NAME_FLAG, "", "the annotation/label key name that contains the app git commit hash")
+	cmd.PersistentFlags().String(REPOSITORY_URL_KEY_NAME_FLAG, "", "the annotation key name that contains the app git repository url")
+	cmd.PersistentFlags().String(VERSION_FLAG, "", "specify a version constraint for the chart version to use. This constraint can be a specific tag (e.g. 1.1.1) or it may reference a valid range (e.g. ^2.0.0). If this is not specified, the latest version is used")
 }
 
 var DeployCmd = &cobra.Command{
@@ -108,10 +153,87 @@ var DeployCmd = &cobra.Command{
 	RunE:  runDeployCmd,
 }
 
+type deployment struct {
+	isUpgrade         bool
+	isIncloud         bool
+	isAuthenticated   bool
+	agentEnabled      bool
+	namespace         string
+	tenantUUID        string
+	releaseName       string
+	clusterName       string
+	backendName       string
+	chart             *helm.Chart
+	release           *helm.Release
+	kubeClient        *k8s.Client
+	helmClient        *helm.Client
+	nodesReport       *k8s.NodesReport
+	deployableNodes   []*k8s.NodeSummary
+	unsupportedNodes  []*k8s.IncompatibleNode
+	chartValues       map[string]interface{}
+	sentryHelmContext *sentry_utils.HelmContext
+}
+
 func runDeployCmd(cmd *cobra.Command, args []string) error {
 	var err error
 
 	ctx := cmd.Context()
+
+	if contextPatterns := viper.GetStringSlice(CONTEXTS_FLAG); len(contextPatterns) > 0 {
+		return runMultiClusterDeploy(cmd, contextPatterns)
+	}
+
+	isDryRun := viper.GetBool(DRY_RUN_FLAG)
+
+	var deployment *deployment
+	if deployment, err = prepareDeployment(ctx, isDryRun); err != nil {
+		return err
+	}
+
+	if isDryRun {
+		return renderHelmRelease(ctx, deployment.helmClient, deployment.releaseName, deployment.chart, deployment.chartValues)
+	}
+
+	if deployment.isUpgrade {
+		printReleaseDiff(ctx, deployment)
+	}
+
+	var shouldInstall bool
+	if shouldInstall, err = promptInstallSummary(deployment.isUpgrade, deployment.isIncloud, deployment.releaseName, deployment.clusterName, deployment.namespace, deployment.release, deployment.chart, len(deployment.deployableNodes), deployment.nodesReport.NodesCount(), deployment.unsupportedNodes, deployment.sentryHelmContext); err != nil {
+		return err
+	}
+
+	if !shouldInstall {
+		return ErrExecutionAborted
+	}
+
+	description := generateReleaseDescription(cmd, deployment.sentryHelmContext.ResourcesPresets)
+	if err = installHelmRelease(ctx, deployment.helmClient, deployment.releaseName, description, deployment.chart, deployment.chartValues); err != nil {
+		return err
+	}
+
+	// the installed release manifest lists the workloads to wait for
+	var installedRelease *helm.Release
+	if installedRelease, err = deployment.helmClient.GetCurrentRelease(deployment.releaseName); err != nil {
+		return err
+	}
+
+	if err = validateInstall(ctx, deployment.kubeClient, installedRelease, deployment.tenantUUID, deployment.backendName, deployment.clusterName, len(deployment.deployableNodes), deployment.isAuthenticated, deployment.agentEnabled, deployment.sentryHelmContext); err != nil {
+		return err
+	}
+
+	printOrOpenClusterUrl(deployment.clusterName, deployment.namespace, deployment.isAuthenticated)
+
+	ui.GlobalWriter.PrintlnWithPrefixln(JOIN_SLACK_MESSAGE)
+
+	return nil
+}
+
+// prepareDeployment validates the cluster and computes the chart values, a preview (dry-run or diff) doesn't change anything
+// in the cluster or the backend: the kernel probe is skipped and a missing ingestion key isn't created
+func prepareDeployment(ctx context.Context, isPreview bool) (*deployment, error) {
+	var err error
+
 	isAuthenticated := !viper.IsSet(TOKEN_FLAG)
 	namespace := viper.GetString(NAMESPACE_FLAG)
 	kubeconfig := viper.GetString(KUBECONFIG_FLAG)
@@ -122,15 +244,20 @@ func runDeployCmd(cmd *cobra.Command, args []string) error {
 	sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
 	sentryKubeContext.SetOnCurrentScope()
 
+	var nodeTargeting *k8s.NodeTargeting
+	if nodeTargeting, err = getNodeTargeting(); err != nil {
+		return nil, err
+	}
+
 	var tenantUUID string
 	if tenantUUID = viper.GetString(TENANT_UUID_FLAG); isAuthenticated && tenantUUID == "" {
 		var tenant *api.TenantInfo
 		if tenant, err = fetchTenant(); err != nil {
-			return err
+			return nil, err
 		}
 
 		if tenant == nil {
-			return errors.New("tenant not found")
+			return nil, errors.New("tenant not found")
 		}
 
 		tenantUUID = tenant.UUID
@@ -138,21 +265,39 @@ func runDeployCmd(cmd *cobra.Command, args []string) error {
 
 	var kubeClient *k8s.Client
 	if kubeClient, err = k8s.NewKubeClient(kubeconfig, kubecontext); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err = validateCluster(ctx, kubeClient, namespace, sentryKubeContext); err != nil {
-		return err
+		return nil, err
 	}
 
 	var nodesReport *k8s.NodesReport
 	if nodesReport, err = validateNodes(ctx, kubeClient, sentryKubeContext); err != nil {
-		return err
+		return nil, err
+	}
+
+	if viper.GetBool(PROBE_KERNEL_FLAG) {
+		if isPreview {
+			ui.GlobalWriter.PrintWarningMessageln(PREVIEW_KERNEL_PROBE_MESSAGE)
+		} else {
+			probeNodesKernel(ctx, kubeClient, namespace, true, nodesReport)
+		}
+	}
+
+	var nodeRequirements *k8s.NodeMinimumRequirements
+	if nodeRequirements, err = getNodeRequirements(); err != nil {
+		return nil, err
+	}
+
+	var unsupportedNodes []*k8s.IncompatibleNode
+	if unsupportedNodes, err = nodeTargeting.ExcludeUnsupportedNodes(nodeRequirements, nodesReport); err != nil {
+		return nil, err
 	}
 
 	var clusterName string
 	if clusterName, err = getClusterName(kubeClient); err != nil {
-		return err
+		return nil, err
 	}
 
 	sentryHelmContext := sentry_utils.NewHelmContext(releaseName, CHART_NAME, HELM_REPO_URL)
@@ -162,36 +307,39 @@ func runDeployCmd(cmd *cobra.Command, args []string) error {
 
 	var helmClient *helm.Client
 	if helmClient, err = helm.NewHelmClient(namespace, kubecontext); err != nil {
-		return err
+		return nil, err
 	}
 
 	var chart *helm.Chart
 	if chart, err = pollGetChart(ctx, helmClient, sentryHelmContext); err != nil {
-		return err
+		return nil, err
 	}
 
-	deployableNodes, tolerations, err := getDeployableNodesAndTolerations(nodesReport, sentryKubeContext)
+	deployableNodes, tolerations, err := getDeployableNodesAndTolerations(nodesReport, nodeTargeting, sentryKubeContext)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	sentry_utils.SetTagOnCurrentScope(sentry_utils.EXPECTED_NODES_COUNT_TAG, fmt.Sprintf("%d", len(deployableNodes)))
 
 	var isUpgrade bool
 	var release *helm.Release
 	if release, isUpgrade, err = helmClient.IsReleaseInstalled(releaseName); err != nil {
-		return err
+		return nil, err
 	}
 
 	var chartValues map[string]interface{}
 	if isUpgrade {
-		chartValues = release.Config
+		// copy the installed values, they are later compared with the computed ones
+		if chartValues, err = helm.CopyValues(release.Config); err != nil {
+			return nil, err
+		}
 	}
 
 	var backendName string
 	var isIncloud bool
 	if isAuthenticated {
 		if backendName, isIncloud, err = selectBackendName(tenantUUID, true); err != nil && err != ErrNoActiveBackends {
-			return err
+			return nil, err
 		}
 	} else {
 		// When using --token, skip backend selection and assume non-incloud deployment
@@ -199,40 +347,49 @@ func runDeployCmd(cmd *cobra.Command, args []string) error {
 		isIncloud = false
 	}
 
-	apiKey, err := getApiKey(chartValues, tenantUUID, backendName, isIncloud, isAuthenticated)
+	apiKey, err := getApiKey(chartValues, tenantUUID, backendName, isIncloud, isAuthenticated, isPreview)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if chartValues, err = generateChartValues(chartValues, apiKey, installationId, clusterName, deployableNodes, tolerations, nodesReport, sentryHelmContext); err != nil {
-		return err
+	// the installed ingestion key is kept, but settings removed from the flags and values files are reverted
+	if viper.GetBool(RESET_VALUES_FLAG) {
+		chartValues = nil
 	}
 
-	agentEnabled := getAgentComponentsConfiguration(chartValues, isIncloud)
-	backendEnabled, backendName := getBackendComponentsConfiguration(chartValues, backendName, clusterName, isIncloud)
-
-	var shouldInstall bool
-	if shouldInstall, err = promptInstallSummary(isUpgrade, isIncloud, releaseName, clusterName, namespace, release, chart, len(deployableNodes), nodesReport.NodesCount(), sentryHelmContext); err != nil {
-		return err
-	}
-
-	if !shouldInstall {
-		return ErrExecutionAborted
+	if chartValues, err = generateChartValues(chartValues, apiKey, installationId, clusterName, deployableNodes, tolerations, nodeTargeting, nodesReport, sentryHelmContext); err != nil {
+		return nil, err
 	}
 
-	if err = installHelmRelease(ctx, helmClient, releaseName, chart, chartValues); err != nil {
-		return err
-	}
+	agentEnabled := getAgentComponentsConfiguration(chartValues, isIncloud)
+	_, backendName = getBackendComponentsConfiguration(chartValues, backendName, clusterName, isIncloud)
 
-	if err = validateInstall(ctx, kubeClient, releaseName, namespace, chart.AppVersion(), tenantUUID, backendName, clusterName, len(deployableNodes), isAuthenticated, agentEnabled, backendEnabled, sentryHelmContext); err != nil {
-		return err
+	if agentEnabled {
+		if deployableNodes, err = validateAgentResources(ctx, kubeClient, nodesReport, deployableNodes, chart, chartValues); err != nil {
+			return nil, err
+		}
 	}
 
-	printOrOpenClusterUrl(clusterName, namespace, isAuthenticated)
-
-	ui.GlobalWriter.PrintlnWithPrefixln(JOIN_SLACK_MESSAGE)
-
-	return nil
+	return &deployment{
+		isUpgrade:         isUpgrade,
+		isIncloud:         isIncloud,
+		isAuthenticated:   isAuthenticated,
+		agentEnabled:      agentEnabled,
+		namespace:         namespace,
+		tenantUUID:        tenantUUID,
+		releaseName:       releaseName,
+		clusterName:       clusterName,
+		backendName:       backendName,
+		chart:             chart,
+		release:           release,
+		kubeClient:        kubeClient,
+		helmClient:        helmClient,
+		nodesReport:       nodesReport,
+		deployableNodes:   deployableNodes,
+		unsupportedNodes:  unsupportedNodes,
+		chartValues:       chartValues,
+		sentryHelmContext: sentryHelmContext,
+	}, nil
 }
 
 func validateCluster(ctx context.Context, kubeClient *k8s.Client, namespace string, sentryKubeContext *sentry_utils.KubeContext) error {
@@ -254,7 +411,12 @@ func validateCluster(ctx context.Context, kubeClient *k8s.Client, namespace stri
 		return err
 	}
 
-	clusterReport := k8s.DefaultClusterRequirements.Validate(ctx, kubeClient, clusterSummary)
+	var clusterRequirements *k8s.ClusterRequirements
+	if clusterRequirements, err = getClusterRequirements(); err != nil {
+		return err
+	}
+
+	clusterReport := clusterRequirements.Validate(ctx, kubeClient, clusterSummary)
 
 	sentryKubeContext.ClusterReport = clusterReport
 	sentryKubeContext.SetOnCurrentScope()
@@ -291,7 +453,12 @@ func validateNodes(ctx context.Context, kubeClient *k8s.Client, sentryKubeContex
 	sentryKubeContext.NodesCount = len(nodesSummaries)
 	sentryKubeContext.SetOnCurrentScope()
 
-	nodesReport := k8s.DefaultNodeRequirements.GenerateNodeReport(nodesSummaries)
+	var nodeRequirements *k8s.NodeMinimumRequirements
+	if nodeRequirements, err = getNodeRequirements(); err != nil {
+		return nil, err
+	}
+
+	nodesReport := nodeRequirements.GenerateNodeReport(nodesSummaries)
 
 	event.
 		Set("nodesCount", len(nodesSummaries)).
@@ -311,15 +478,78 @@ func validateNodes(ctx context.Context, kubeClient *k8s.Client, sentryKubeContex
 	return nodesReport, nil
 }
 
-func getDeployableNodesAndTolerations(nodesReport *k8s.NodesReport, sentryKubeContext *sentry_utils.KubeContext) ([]*k8s.NodeSummary, []map[string]interface{}, error) {
+// validateAgentResources flags the deployable nodes without enough free cpu or memory for the agent sensor requests,
+// their sensors would stay pending so only the other deployable nodes are returned
+func validateAgentResources(ctx context.Context, kubeClient *k8s.Client, nodesReport *k8s.NodesReport, deployableNodes []*k8s.NodeSummary, chart *helm.Chart, chartValues map[string]interface{}) ([]*k8s.NodeSummary, error) {
+	var err error
+
+	event := segment.NewEvent(RESOURCES_VALIDATION_EVENT_NAME)
+	event.Start()
+	defer func() {
+		event.StatusByError(err)
+	}()
+
+	var sensorRequests v1.ResourceList
+	if sensorRequests, err = chart.AgentSensorRequests(chartValues); err != nil {
+		return nil, err
+	}
+
+	if len(sensorRequests) == 0 {
+		return deployableNodes, nil
+	}
+
+	var ignoredPods labels.Selector
+	if ignoredPods, err = labels.Parse(SENSOR_LABEL_SELECTOR); err != nil {
+		return nil, err
+	}
+
+	ui.GlobalWriter.PrintlnWithPrefixln("Validating nodes free resources:")
+
+	// the installed sensors are replaced, so their requests are available to the new ones
+	if err = kubeClient.CalcNodesFreeResources(ctx, deployableNodes, ignoredPods); err != nil {
+		return nil, err
+	}
+
+	insufficientNodes := nodesReport.ValidateAgentResources(deployableNodes, sensorRequests)
+	nodesReport.AgentCPUFits.PrintStatus()
+	nodesReport.AgentMemoryFits.PrintStatus()
+
+	event.
+		Set("deployableNodesCount", len(deployableNodes)).
+		Set("insufficientNodesCount", len(insufficientNodes))
+
+	if len(insufficientNodes) == 0 {
+		return deployableNodes, nil
+	}
+
+	var sufficientNodes []*k8s.NodeSummary
+	for _, node := range deployableNodes {
+		if !slices.Contains(insufficientNodes, node) {
+			sufficientNodes = append(sufficientNodes, node)
+		}
+	}
+
+	ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(INSUFFICIENT_NODES_FORMAT, len(insufficientNodes)))
+	sentry_utils.SetTagOnCurrentScope(sentry_utils.EXPECTED_NODES_COUNT_TAG, fmt.Sprintf("%d", len(sufficientNodes)))
+
+	return sufficientNodes, nil
+}
+
+func getDeployableNodesAndTolerations(nodesReport *k8s.NodesReport, nodeTargeting *k8s.NodeTargeting, sentryKubeContext *sentry_utils.KubeContext) ([]*k8s.NodeSummary, []map[string]interface{}, error) {
 	var err error
 
 	tolerations := make([]map[string]interface{}, 0)
 	deployableNodes := nodesReport.CompatibleNodes
+	taintedNodes := nodesReport.TaintedNodes
+
+	if !nodeTargeting.IsEmpty() {
+		deployableNodes = nodeTargeting.FilterNodes(deployableNodes)
+		taintedNodes = nodeTargeting.FilterTaintedNodes(taintedNodes)
+	}
 
-	if len(nodesReport.TaintedNodes) > 0 {
+	if len(taintedNodes) > 0 {
 		tolerationManager := &k8s.TolerationManager{
-			TaintedNodes: nodesReport.TaintedNodes,
+			TaintedNodes: taintedNodes,
 		}
 
 		var allowedTaints []string
@@ -350,7 +580,10 @@ func promptTaints(tolerationManager *k8s.TolerationManager, sentryKubeContext *s
 		return nil, err
 	}
 
-	allowedTaints := ui.GlobalWriter.MultiSelectPrompt("Do you want set tolerations to allow scheduling groundcover on following taints:", taints, taints)
+	var allowedTaints []string
+	if allowedTaints, err = selectAllowedTaints(tolerationManager, taints); err != nil {
+		return nil, err
+	}
 
 	sentryKubeContext.TolerationsAndTaintsRatio = fmt.Sprintf("%d/%d", len(allowedTaints), len(taints))
 	sentryKubeContext.SetOnCurrentScope()
@@ -359,8 +592,9 @@ func promptTaints(tolerationManager *k8s.TolerationManager, sentryKubeContext *s
 	return allowedTaints, nil
 }
 
-func promptInstallSummary(isUpgrade bool, isIncloud bool, releaseName string, clusterName string, namespace string, release *helm.Release, chart *helm.Chart, deployableNodesCount, nodesCount int, sentryHelmContext *sentry_utils.HelmContext) (bool, error) {
+func promptInstallSummary(isUpgrade bool, isIncloud bool, releaseName string, clusterName string, namespace string, release *helm.Release, chart *helm.Chart, deployableNodesCount, nodesCount int, unsupportedNodes []*k8s.IncompatibleNode, sentryHelmContext *sentry_utils.HelmContext) (bool, error) {
 	ui.GlobalWriter.PrintlnWithPrefixln("Installing groundcover:")
+	printUnsupportedNodes(unsupportedNodes)
 
 	var promptMessage string
 	if isUpgrade {
@@ -371,18 +605,18 @@ func promptInstallSummary(isUpgrade bool, isIncloud bool, releaseName string, cl
 
 		if chart.Version().GT(release.Version()) {
 			promptMessage = fmt.Sprintf(
-				"Your groundcover version is out of date (cluster: %s, namespace: %s, version: %s), The latest version is %s.\nDo you want to upgrade?",
-				clusterName, namespace, release.Version(), chart.Version(),
+				"Your groundcover version is out of date (cluster: %s, namespace: %s, deployable nodes: %d/%d, version: %s), The latest version is %s.\nDo you want to upgrade?",
+				clusterName, namespace, deployableNodesCount, nodesCount, release.Version(), chart.Version(),
 			)
 		} else {
 			promptMessage = fmt.Sprintf(
-				"Latest version of groundcover is already installed in your cluster! (cluster: %s, namespace: %s, version: %s).\nDo you want to redeploy?",
-				clusterName, namespace, chart.Version(),
+				"Latest version of groundcover is already installed in your cluster! (cluster: %s, namespace: %s, deployable nodes: %d/%d, version: %s).\nDo you want to redeploy?",
+				clusterName, namespace, deployableNodesCount, nodesCount, chart.Version(),
 			)
 		}
 	} else {
 		promptMessage = fmt.Sprintf(
-			"Deploy groundcover (cluster: %s, namespace: %s, compatible nodes: %d/%d, version: %s, incloud: %t)",
+			"Deploy groundcover (cluster: %s, namespace: %s, deployable nodes: %d/%d, version: %s, incloud: %t)",
 			clusterName, namespace, deployableNodesCount, nodesCount, chart.Version(), isIncloud,
 		)
 	}
@@ -390,7 +624,26 @@ func promptInstallSummary(isUpgrade bool, isIncloud bool, releaseName string, cl
 	return ui.GlobalWriter.YesNoPrompt(promptMessage, !isUpgrade), nil
 }
 
-func installHelmRelease(ctx context.Context, helmClient *helm.Client, releaseName string, chart *helm.Chart, chartValues map[string]interface{}) error {
+// printUnsupportedNodes prints the nodes excluded from the agent, grouped by the reasons they aren't supported
+func printUnsupportedNodes(unsupportedNodes []*k8s.IncompatibleNode) {
+	var reasons []string
+	nodesByReason := make(map[string][]string)
+
+	for _, node := range unsupportedNodes {
+		reason := strings.Join(node.RequirementErrors, ", ")
+		if _, exists := nodesByReason[reason]; !exists {
+			reasons = append(reasons, reason)
+		}
+
+		nodesByReason[reason] = append(nodesByReason[reason], node.Name)
+	}
+
+	for _, reason := range reasons {
+		ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(UNSUPPORTED_NODES_FORMAT, len(nodesByReason[reason]), reason, strings.Join(nodesByReason[reason], ", ")))
+	}
+}
+
+func installHelmRelease(ctx context.Context, helmClient *helm.Client, releaseName, description string, chart *helm.Chart, chartValues map[string]interface{}) error {
 	var err error
 
 	event := segment.NewEvent(HELM_INSTALLATION_EVENT_NAME)
@@ -407,14 +660,14 @@ func installHelmRelease(ctx context.Context, helmClient *helm.Client, releaseNam
 	defer spinner.WriteStop()
 
 	helmUpgradeFunc := func() error {
-		if _, err = helmClient.Upgrade(ctx, releaseName, chart, chartValues); err != nil {
+		if _, err = helmClient.Upgrade(ctx, releaseName, description, chart, chartValues); err != nil {
 			return ui.RetryableError(err)
 		}
 
 		return nil
 	}
 
-	err = spinner.Poll(ctx, helmUpgradeFunc, HELM_DEPLOY_POLLING_INTERVAL, HELM_DEPLOY_POLLING_TIMEOUT, HELM_DEPLOY_POLLING_RETRIES)
+	err = spinner.PollWithPolicy(ctx, helmUpgradeFunc, HelmDeployPollingPolicy)
 
 	if err == nil {
 		return nil
@@ -432,31 +685,178 @@ func installHelmRelease(ctx context.Context, helmClient *helm.Client, releaseNam
 	return err
 }
 
-func validateInstall(ctx context.Context, kubeClient *k8s.Client, releaseName, namespace, appVersion, tenantUUID, backendName, clusterName string, deployableNodesCount int, isAuthenticated, agentEnabled, backendEnabled bool, sentryHelmContext *sentry_utils.HelmContext) error {
+// generateReleaseDescription records the cli version, flags and presets which produced a release revision,
+// credentials and local kubeconfig flags are left out as the description is stored in the cluster
+func generateReleaseDescription(cmd *cobra.Command, presets []string) string {
+	excludedFlags := []string{TOKEN_FLAG, API_KEY_FLAG, TENANT_UUID_FLAG, KUBECONFIG_FLAG, KUBECONTEXT_FLAG, CONFIG_FLAG, ui.ASSUME_YES_FLAG, SKIP_CLI_UPDATE_FLAG}
+	commandLine := strings.Join(append([]string{cmd.Name()}, changedFlagsArgs(cmd.Flags(), excludedFlags)...), " ")
+
+	description := fmt.Sprintf(RELEASE_DESCRIPTION_FORMAT, BinaryVersion, commandLine)
+	if len(presets) > 0 {
+		description = fmt.Sprintf(RELEASE_PRESETS_FORMAT, description, strings.Join(presets, ", "))
+	}
+
+	return description
+}
+
+func renderHelmRelease(ctx context.Context, helmClient *helm.Client, releaseName string, chart *helm.Chart, chartValues map[string]interface{}) error {
 	var err error
 
-	defer reportPodsStatus(ctx, kubeClient, namespace, sentryHelmContext)
+	spinner := ui.GlobalWriter.NewSpinner("Rendering groundcover helm release")
+	spinner.Start()
+	spinner.SetStopMessage("groundcover helm release is rendered, nothing was installed")
+	spinner.SetStopFailMessage("groundcover helm release rendering failed")
 
-	ui.GlobalWriter.PrintlnWithPrefixln("Validating groundcover installation:")
+	var release *helm.Release
+	if release, err = helmClient.Template(ctx, releaseName, chart, chartValues); err != nil {
+		spinner.WriteStopFail()
+		return err
+	}
 
-	if err = waitForPvcs(ctx, kubeClient, releaseName, namespace, sentryHelmContext); err != nil {
+	var redactedValues map[string]interface{}
+	if redactedValues, err = helm.RedactValues(chartValues); err != nil {
+		spinner.WriteStopFail()
 		return err
 	}
 
-	if backendEnabled {
-		if err = waitForPortal(ctx, kubeClient, namespace, appVersion, sentryHelmContext); err != nil {
-			return err
+	var valuesData []byte
+	if valuesData, err = yaml.Marshal(redactedValues); err != nil {
+		spinner.WriteStopFail()
+		return err
+	}
+
+	spinner.WriteStop()
+
+	ui.QuietWriter.Println("---\n# Source: values.yaml")
+	ui.QuietWriter.Println(string(valuesData))
+	ui.QuietWriter.Println(helm.RedactManifest(release.RenderedManifest(), chartValues))
+
+	return nil
+}
+
+func printReleaseDiff(ctx context.Context, deployment *deployment) {
+	var err error
+
+	var releaseDiff *helm.ReleaseDiff
+	if releaseDiff, err = generateReleaseDiff(ctx, deployment); err != nil {
+		ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf("Failed to compare with installed release: %s", err))
+		return
+	}
+
+	ui.GlobalWriter.PrintlnWithPrefixln("Changes compared to installed release:")
+	releaseDiff.PrintStatus()
+}
+
+func generateReleaseDiff(ctx context.Context, deployment *deployment) (*helm.ReleaseDiff, error) {
+	var err error
+
+	var renderedRelease *helm.Release
+	if renderedRelease, err = deployment.helmClient.Template(ctx, deployment.releaseName, deployment.chart, deployment.chartValues); err != nil {
+		return nil, err
+	}
+
+	return helm.NewReleaseDiff(
+		deployment.release.Config,
+		deployment.chartValues,
+		deployment.release.RenderedManifest(),
+		renderedRelease.RenderedManifest(),
+	)
+}
+
+func selectAllowedTaints(tolerationManager *k8s.TolerationManager, taints []string) ([]string, error) {
+	var err error
+
+	// --tolerate-taint is the deprecated name of --tolerate, kept for existing pipelines
+	tolerateTaints := append(viper.GetStringSlice(TOLERATE_FLAG), viper.GetStringSlice(TOLERATE_TAINT_FLAG)...)
+
+	switch {
+	case len(taints) == 0:
+		return []string{}, nil
+	case viper.GetBool(TOLERATE_ALL_TAINTS_FLAG):
+		return taints, nil
+	case len(tolerateTaints) == 1 && tolerateTaints[0] == NO_TAINTS_VALUE:
+		return []string{}, nil
+	case len(tolerateTaints) > 0:
+		tolerations := make([]v1.Toleration, 0, len(tolerateTaints))
+		for _, spec := range tolerateTaints {
+			var toleration v1.Toleration
+			if toleration, err = k8s.ParseToleration(spec); err != nil {
+				return nil, err
+			}
+
+			tolerations = append(tolerations, toleration)
 		}
+
+		return tolerationManager.GetTaintsToleratedBy(tolerations)
+	case ui.IsNonInteractive():
+		return nil, ui.MissingAnswerError(TAINTS_PROMPT_MESSAGE, TOLERATE_FLAG)
+	default:
+		return ui.GlobalWriter.MultiSelectPrompt(TAINTS_PROMPT_MESSAGE, taints, taints), nil
 	}
+}
 
-	if isAuthenticated {
-		if err = validateClusterRegistered(ctx, tenantUUID, backendName, clusterName); err != nil {
-			return err
+func getNodeTargeting() (*k8s.NodeTargeting, error) {
+	var err error
+
+	nodeTargeting := &k8s.NodeTargeting{
+		ExcludedNodes: viper.GetStringSlice(EXCLUDE_NODES_FLAG),
+	}
+
+	if nodeSelector := viper.GetString(NODE_SELECTOR_FLAG); nodeSelector != "" {
+		if nodeTargeting.Selector, err = labels.Parse(nodeSelector); err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", NODE_SELECTOR_FLAG, err)
+		}
+	}
+
+	if excludeNodeSelector := viper.GetString(EXCLUDE_NODE_SELECTOR_FLAG); excludeNodeSelector != "" {
+		if nodeTargeting.ExcludeSelector, err = labels.Parse(excludeNodeSelector); err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", EXCLUDE_NODE_SELECTOR_FLAG, err)
 		}
 	}
 
+	return nodeTargeting, nil
+}
+
+func getAgentAffinity(nodeTargeting *k8s.NodeTargeting) (map[string]interface{}, error) {
+	var err error
+
+	var affinity *v1.Affinity
+	if affinity, err = nodeTargeting.Affinity(); err != nil || affinity == nil {
+		return nil, err
+	}
+
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(affinity)
+}
+
+func validateInstall(ctx context.Context, kubeClient *k8s.Client, release *helm.Release, tenantUUID, backendName, clusterName string, deployableNodesCount int, isAuthenticated, agentEnabled bool, sentryHelmContext *sentry_utils.HelmContext) error {
+	var err error
+
+	defer reportPodsStatus(ctx, kubeClient, release.Namespace, sentryHelmContext)
+	defer func() {
+		if err != nil {
+			reportNamespaceDiagnoses(ctx, kubeClient, release.Namespace, release.LastDeployed())
+		}
+	}()
+
+	ui.GlobalWriter.PrintlnWithPrefixln("Validating groundcover installation:")
+
+	if err = waitForPvcs(ctx, kubeClient, release.Name, release.Namespace, sentryHelmContext); err != nil {
+		return err
+	}
+
+	if err = waitForWorkloads(ctx, kubeClient, release, agentEnabled); err != nil {
+		return err
+	}
+
+	// a ready sensors daemonset may still be scheduled on fewer nodes than the deployable ones
 	if agentEnabled {
-		if err = waitForSensors(ctx, kubeClient, namespace, appVersion, deployableNodesCount, sentryHelmContext); err != nil {
+		if err = waitForSensors(ctx, kubeClient, release.Namespace, release.Chart.AppVersion(), deployableNodesCount, sentryHelmContext); err != nil {
+			return err
+		}
+	}
+
+	if isAuthenticated {
+		if err = validateClusterRegistered(ctx, tenantUUID, backendName, clusterName); err != nil {
 			return err
 		}
 	}
@@ -539,7 +939,7 @@ func pollGetChart(ctx context.Context, helmClient *helm.Client, sentryHelmContex
 		return nil
 	}
 
-	err = spinner.Poll(ctx, getChartFunc, GET_CHART_POLLING_INTERVAL, GET_CHART_POLLING_TIMEOUT, GET_CHART_POLLING_RETRIES)
+	err = spinner.PollWithPolicy(ctx, getChartFunc, GetChartPollingPolicy)
 
 	if err == nil {
 		sentryHelmContext.ChartVersion = chart.Version().String()
@@ -558,7 +958,48 @@ func pollGetChart(ctx context.Context, helmClient *helm.Client, sentryHelmContex
 	return nil, err
 }
 
-func generateChartValues(chartValues map[string]interface{}, apiKey, installationId, clusterName string, deployableNodes []*k8s.NodeSummary, tolerations []map[string]interface{}, nodesReport *k8s.NodesReport, sentryHelmContext *sentry_utils.HelmContext) (map[string]interface{}, error) {
+// getResourcesPresets returns the agent and backend resources presets, chosen by the nodes allocatable resources
+// and kernel versions unless the low resources flag is set
+func getResourcesPresets(allocatableResources *helm.AllocatableResources, maxKernelVersion semver.Version) []*helm.PresetChoice {
+	if viper.GetBool(LOW_RESOURCES_FLAG) {
+		return []*helm.PresetChoice{
+			{Component: helm.AGENT_COMPONENT, Path: helm.AGENT_LOW_RESOURCES_PATH, Reason: LOW_RESOURCES_PRESET_REASON},
+			{Component: helm.BACKEND_COMPONENT, Path: helm.BACKEND_LOW_RESOURCES_PATH, Reason: LOW_RESOURCES_PRESET_REASON},
+		}
+	}
+
+	return []*helm.PresetChoice{
+		helm.ChooseAgentResourcePreset(allocatableResources, maxKernelVersion),
+		helm.ChooseBackendResourcePreset(allocatableResources),
+	}
+}
+
+// getPresetsPaths returns the values override paths of the non default resources presets and of the presets enabled by flags
+func getPresetsPaths(resourcesPresets []*helm.PresetChoice) []string {
+	var overridePaths []string
+
+	for _, resourcesPreset := range resourcesPresets {
+		if resourcesPreset.Path != helm.DEFAULT_PRESET {
+			overridePaths = append(overridePaths, resourcesPreset.Path)
+		}
+	}
+
+	if viper.GetString(REGISTRY_FLAG) == "quay" {
+		overridePaths = append(overridePaths, QUAY_REGISTRY_PRESET_PATH)
+	}
+
+	if viper.GetBool(ENABLE_CUSTOM_METRICS_FLAG) {
+		overridePaths = append(overridePaths, CUSTOM_METRICS_PRESET_PATH)
+	}
+
+	if viper.GetBool(ENABLE_KUBE_STATE_METRICS_FLAG) {
+		overridePaths = append(overridePaths, KUBE_STATE_METRICS_PRESET_PATH)
+	}
+
+	return overridePaths
+}
+
+func generateChartValues(chartValues map[string]interface{}, apiKey, installationId, clusterName string, deployableNodes []*k8s.NodeSummary, tolerations []map[string]interface{}, nodeTargeting *k8s.NodeTargeting, nodesReport *k8s.NodesReport, sentryHelmContext *sentry_utils.HelmContext) (map[string]interface{}, error) {
 	var err error
 
 	defaultChartValues := map[string]interface{}{
@@ -583,39 +1024,9 @@ func generateChartValues(chartValues map[string]interface{}, apiKey, installatio
 		return nil, err
 	}
 
-	var overridePaths []string
 	allocatableResources := helm.CalcAllocatableResources(deployableNodes)
 	sentryHelmContext.AllocatableResources = allocatableResources
-	if viper.GetBool(LOW_RESOURCES_FLAG) {
-		overridePaths = []string{
-			helm.AGENT_LOW_RESOURCES_PATH,
-			helm.BACKEND_LOW_RESOURCES_PATH,
-		}
-	} else {
-		agentPresetPath := helm.GetAgentResourcePresetPath(allocatableResources, nodesReport.MaximalKernelVersion())
-		if agentPresetPath != helm.DEFAULT_PRESET {
-			overridePaths = append(overridePaths, agentPresetPath)
-		}
-
-		backendPresetPath := helm.GetBackendResourcePresetPath(allocatableResources)
-		if backendPresetPath != helm.DEFAULT_PRESET {
-			overridePaths = append(overridePaths, backendPresetPath)
-		}
-	}
-
-	if viper.GetString(REGISTRY_FLAG) == "quay" {
-		overridePaths = append(overridePaths, QUAY_REGISTRY_PRESET_PATH)
-	}
-
-	enableCustomMetrics := viper.GetBool(ENABLE_CUSTOM_METRICS_FLAG)
-	if enableCustomMetrics {
-		overridePaths = append(overridePaths, CUSTOM_METRICS_PRESET_PATH)
-	}
-
-	enableKubeStateMetrics := viper.GetBool(ENABLE_KUBE_STATE_METRICS_FLAG)
-	if enableKubeStateMetrics {
-		overridePaths = append(overridePaths, KUBE_STATE_METRICS_PRESET_PATH)
-	}
+	overridePaths := getPresetsPaths(getResourcesPresets(allocatableResources, nodesReport.MaximalKernelVersion()))
 
 	if len(overridePaths) > 0 {
 		sentryHelmContext.ResourcesPresets = overridePaths
@@ -641,18 +1052,12 @@ func generateChartValues(chartValues map[string]interface{}, apiKey, installatio
 
 	valuesOverride[STORE_ISSUES_LOGS_ONLY_KEY] = viper.GetBool(STORE_ISSUES_LOGS_ONLY_FLAG)
 
-	// we always want to override tolerations
-	if agentValues, exist := valuesOverride["agent"].(map[string]interface{}); exist {
-		if _, exist := agentValues["tolerations"]; exist {
-			tolerations = []map[string]interface{}{}
-		}
+	var agentAffinity map[string]interface{}
+	if agentAffinity, err = getAgentAffinity(nodeTargeting); err != nil {
+		return nil, err
 	}
 
-	if agentValues, ok := chartValues["agent"].(map[string]interface{}); ok {
-		agentValues["tolerations"] = tolerations
-	} else {
-		chartValues["agent"] = map[string]interface{}{"tolerations": tolerations}
-	}
+	setAgentPlacementValues(chartValues, valuesOverride, agentAffinity, tolerations)
 
 	if err = mergo.Merge(&chartValues, valuesOverride, mergo.WithOverride); err != nil {
 		return nil, err
@@ -664,6 +1069,34 @@ func generateChartValues(chartValues map[string]interface{}, apiKey, installatio
 	return chartValues, nil
 }
 
+// setAgentPlacementValues regenerates the agent affinity and tolerations on every deploy,
+// so placement left in the installed release values by earlier flags or values files doesn't linger
+func setAgentPlacementValues(chartValues, valuesOverride map[string]interface{}, agentAffinity map[string]interface{}, tolerations []map[string]interface{}) {
+	agentValues, ok := chartValues["agent"].(map[string]interface{})
+	if !ok {
+		agentValues = map[string]interface{}{}
+		chartValues["agent"] = agentValues
+	}
+
+	delete(agentValues, "affinity")
+	if agentAffinity != nil {
+		agentValues["affinity"] = agentAffinity
+	}
+
+	if overrideAgentValues, exist := valuesOverride["agent"].(map[string]interface{}); exist {
+		// we always want to override tolerations
+		if _, exist := overrideAgentValues["tolerations"]; exist {
+			tolerations = []map[string]interface{}{}
+		}
+
+		if _, exist := overrideAgentValues["affinity"]; exist && agentAffinity != nil {
+			ui.GlobalWriter.PrintWarningMessageln(AGENT_AFFINITY_OVERRIDE_MESSAGE)
+		}
+	}
+
+	agentValues["tolerations"] = tolerations
+}
+
 func fetchIngestionKey(tenantUUID, backendName string) (string, error) {
 	var err error
 
@@ -738,7 +1171,7 @@ func getAgentComponentsConfiguration(chartValues map[string]interface{}, isInclo
 	return agentEnabled
 }
 
-func getApiKey(chartValues map[string]interface{}, tenantUUID, backendName string, isIncloud, isAuthenticated bool) (string, error) {
+func getApiKey(chartValues map[string]interface{}, tenantUUID, backendName string, isIncloud, isAuthenticated, isPreview bool) (string, error) {
 	apiKey := viper.GetString(API_KEY_FLAG)
 
 	if apiKey != "" {
@@ -764,5 +1197,10 @@ func getApiKey(chartValues map[string]interface{}, tenantUUID, backendName strin
 		return "", errors.New("no API key found and --token flag was provided")
 	}
 
+	// fetching the ingestion key creates it when missing
+	if isPreview {
+		return PREVIEW_API_KEY, nil
+	}
+
 	return fetchIngestionKey(tenantUUID, backendName)
 }
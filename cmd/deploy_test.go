@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetApiKeyPreviewDoesNotCreateIngestionKey(t *testing.T) {
+	viper.Set(API_KEY_FLAG, "")
+	defer viper.Set(API_KEY_FLAG, "")
+
+	apiKey, err := getApiKey(nil, "tenant", "backend", true, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, PREVIEW_API_KEY, apiKey)
+}
+
+func TestGetApiKeyPreviewKeepsInstalledKey(t *testing.T) {
+	viper.Set(API_KEY_FLAG, "")
+	defer viper.Set(API_KEY_FLAG, "")
+
+	chartValues := map[string]interface{}{
+		"global": map[string]interface{}{"groundcover_token": "installed-key"},
+	}
+
+	apiKey, err := getApiKey(chartValues, "tenant", "backend", true, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "installed-key", apiKey)
+}
+
+func TestSelectAllowedTaintsDeprecatedTolerateTaint(t *testing.T) {
+	viper.Set(TOLERATE_TAINT_FLAG, []string{"dedicated"})
+	defer viper.Set(TOLERATE_TAINT_FLAG, []string{})
+
+	tolerationManager := &k8s.TolerationManager{
+		TaintedNodes: []*k8s.IncompatibleNode{
+			{NodeSummary: &k8s.NodeSummary{Taints: []v1.Taint{{Key: "dedicated", Value: "monitoring", Effect: v1.TaintEffectNoSchedule}}}},
+			{NodeSummary: &k8s.NodeSummary{Taints: []v1.Taint{{Key: "gpu", Effect: v1.TaintEffectNoSchedule}}}},
+		},
+	}
+
+	taints, err := tolerationManager.GetTaints()
+	assert.NoError(t, err)
+
+	allowedTaints, err := selectAllowedTaints(tolerationManager, taints)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"key\":\"dedicated\",\"value\":\"monitoring\",\"effect\":\"NoSchedule\"}"}, allowedTaints)
+}
+
+func TestSetAgentPlacementValuesClearsInstalledAffinity(t *testing.T) {
+	chartValues := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"affinity":    map[string]interface{}{"nodeAffinity": "stale"},
+			"resources":   "installed",
+			"tolerations": []interface{}{"stale"},
+		},
+	}
+
+	setAgentPlacementValues(chartValues, map[string]interface{}{}, nil, []map[string]interface{}{})
+
+	expected := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"resources":   "installed",
+			"tolerations": []map[string]interface{}{},
+		},
+	}
+	assert.Equal(t, expected, chartValues)
+}
+
+func TestSetAgentPlacementValuesReplacesInstalledAffinity(t *testing.T) {
+	chartValues := map[string]interface{}{
+		"agent": map[string]interface{}{"affinity": map[string]interface{}{"nodeAffinity": "stale"}},
+	}
+	agentAffinity := map[string]interface{}{"nodeAffinity": "generated"}
+	tolerations := []map[string]interface{}{{"key": "dedicated"}}
+
+	setAgentPlacementValues(chartValues, map[string]interface{}{}, agentAffinity, tolerations)
+
+	expected := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"affinity":    agentAffinity,
+			"tolerations": tolerations,
+		},
+	}
+	assert.Equal(t, expected, chartValues)
+}
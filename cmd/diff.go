@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/ui"
+)
+
+func init() {
+	RootCmd.AddCommand(DiffCmd)
+
+	addDeployFlags(DiffCmd)
+}
+
+var DiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what deploying groundcover would change in the installed release",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+
+		// deploy flags are shared with the deploy command, bind them to this command instance
+		if err = viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+
+		var deployment *deployment
+		if deployment, err = prepareDeployment(ctx, true); err != nil {
+			return err
+		}
+
+		if !deployment.isUpgrade {
+			ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(
+				"could not find release %s in namespace %s, nothing to compare with (use --%s, --%s flags)",
+				deployment.releaseName, deployment.namespace, HELM_RELEASE_FLAG, NAMESPACE_FLAG),
+			)
+			return ErrSilentExecutionAbort
+		}
+
+		var releaseDiff *helm.ReleaseDiff
+		if releaseDiff, err = generateReleaseDiff(ctx, deployment); err != nil {
+			return err
+		}
+
+		ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("Changes compared to installed release (version: %s, new version: %s):", deployment.release.Version(), deployment.chart.Version()))
+		releaseDiff.PrintStatus()
+
+		return nil
+	},
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+	"groundcover.com/pkg/helm"
+	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/ui"
+)
+
+const (
+	REVISION_FLAG = "revision"
+)
+
+func init() {
+	RootCmd.AddCommand(HistoryCmd)
+
+	HistoryCmd.Flags().Int(REVISION_FLAG, 0, "show the details and changes of a specific release revision")
+	HistoryCmd.Flags().Bool(VALUES_FLAG, false, "print the chart values of the release revision (latest revision unless --revision is set)")
+}
+
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show groundcover release revisions history",
+	Example: `groundcover history
+groundcover history --revision 3
+groundcover history --revision 3 --values`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		namespace := viper.GetString(NAMESPACE_FLAG)
+		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
+		releaseName := viper.GetString(HELM_RELEASE_FLAG)
+
+		sentryHelmContext := sentry_utils.NewHelmContext(releaseName, CHART_NAME, HELM_REPO_URL)
+		sentryHelmContext.SetOnCurrentScope()
+
+		// flags are specific to this command, they are read directly to avoid clashing with deploy --values
+		var revision int
+		if revision, err = cmd.Flags().GetInt(REVISION_FLAG); err != nil {
+			return err
+		}
+
+		var showValues bool
+		if showValues, err = cmd.Flags().GetBool(VALUES_FLAG); err != nil {
+			return err
+		}
+
+		var helmClient *helm.Client
+		if helmClient, err = helm.NewHelmClient(namespace, kubecontext); err != nil {
+			return err
+		}
+
+		var releases []*helm.Release
+		if releases, err = helmClient.History(releaseName); err != nil {
+			return err
+		}
+
+		if revision == 0 && !showValues {
+			printReleaseHistory(releases)
+			return nil
+		}
+
+		index := len(releases) - 1
+		if revision != 0 {
+			if index, err = findRevisionIndex(releases, revision); err != nil {
+				return err
+			}
+		}
+
+		if showValues {
+			return printReleaseValues(releases[index])
+		}
+
+		return printRevisionDetails(releases, index)
+	},
+}
+
+func findRevisionIndex(releases []*helm.Release, revision int) (int, error) {
+	for index, release := range releases {
+		if release.Revision() == revision {
+			return index, nil
+		}
+	}
+
+	return 0, fmt.Errorf("revision %d not found in release history", revision)
+}
+
+// printReleaseValues prints the revision values with credentials, e.g. global.groundcover_token, redacted
+func printReleaseValues(release *helm.Release) error {
+	var err error
+
+	var redactedValues map[string]interface{}
+	if redactedValues, err = helm.RedactValues(release.Config); err != nil {
+		return err
+	}
+
+	var valuesData []byte
+	if valuesData, err = yaml.Marshal(redactedValues); err != nil {
+		return err
+	}
+
+	ui.QuietWriter.Println(fmt.Sprintf("# Revision: %d, chart version: %s", release.Revision(), release.Version()))
+	ui.QuietWriter.Println(string(valuesData))
+
+	return nil
+}
+
+func printRevisionDetails(releases []*helm.Release, index int) error {
+	var err error
+
+	release := releases[index]
+
+	printReleaseHistory(releases[index : index+1])
+
+	if index == 0 {
+		ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("Revision %d is the first revision of the release", release.Revision()))
+		return nil
+	}
+
+	previousRelease := releases[index-1]
+
+	var releaseDiff *helm.ReleaseDiff
+	if releaseDiff, err = helm.NewReleaseDiff(previousRelease.Config, release.Config, previousRelease.RenderedManifest(), release.RenderedManifest()); err != nil {
+		return err
+	}
+
+	ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("Changes compared to revision %d (version: %s):", previousRelease.Revision(), previousRelease.Version()))
+	releaseDiff.PrintStatus()
+
+	return nil
+}
+
+func printReleaseHistory(releases []*helm.Release) {
+	var historyBuffer strings.Builder
+
+	tableWriter := tabwriter.NewWriter(&historyBuffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "REVISION\tUPDATED\tSTATUS\tCHART VERSION\tAPP VERSION\tDESCRIPTION")
+
+	for _, release := range releases {
+		fmt.Fprintf(tableWriter, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			release.Revision(),
+			release.Info.LastDeployed.Format(time.RFC3339),
+			release.Info.Status,
+			release.Version(),
+			release.Chart.AppVersion(),
+			release.Info.Description,
+		)
+	}
+	tableWriter.Flush()
+
+	ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("groundcover release history (%d revisions):", len(releases)))
+	ui.GlobalWriter.Printf("%s", historyBuffer.String())
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/ui"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestGenerateReleaseDescription(t *testing.T) {
+	cmd := &cobra.Command{Use: DEPLOY_COMMAND_NAME}
+	cmd.Flags().StringSlice(VALUES_FLAG, []string{}, "")
+	cmd.Flags().Bool(LOW_RESOURCES_FLAG, false, "")
+	cmd.Flags().String(API_KEY_FLAG, "", "")
+	cmd.Flags().Bool(ui.ASSUME_YES_FLAG, false, "")
+
+	assert.NoError(t, cmd.Flags().Parse([]string{"--values=a.yaml", "--values=b.yaml", "--low-resources", "--api-key=secret", "--yes"}))
+
+	assert.Equal(t,
+		"groundcover cli 0.0.0-dev: deploy --low-resources=true --values=a.yaml --values=b.yaml (presets: presets/quay.yaml)",
+		generateReleaseDescription(cmd, []string{QUAY_REGISTRY_PRESET_PATH}),
+	)
+}
+
+func TestFindRevisionIndex(t *testing.T) {
+	releases := []*helm.Release{
+		{Release: &release.Release{Version: 2}},
+		{Release: &release.Release{Version: 5}},
+	}
+
+	index, err := findRevisionIndex(releases, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	_, err = findRevisionIndex(releases, 3)
+	assert.EqualError(t, err, "revision 3 not found in release history")
+}
+
+func TestPrintReleaseValuesRedactsToken(t *testing.T) {
+	var buffer bytes.Buffer
+	ui.QuietWriter.SetOutput(&buffer)
+	defer ui.QuietWriter.SetOutput(nil)
+
+	helmRelease := &helm.Release{
+		Release: &release.Release{
+			Version: 3,
+			Chart:   &chart.Chart{Metadata: &chart.Metadata{Name: "groundcover", Version: "1.2.3"}},
+			Config: map[string]interface{}{
+				"clusterId": "cluster",
+				"global":    map[string]interface{}{"groundcover_token": "secret-token"},
+			},
+		},
+	}
+
+	assert.NoError(t, printReleaseValues(helmRelease))
+	assert.NotContains(t, buffer.String(), "secret-token")
+	assert.Contains(t, buffer.String(), "groundcover_token: "+helm.REDACTED_VALUE)
+	assert.Contains(t, buffer.String(), "clusterId: cluster")
+}
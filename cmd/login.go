@@ -102,10 +102,10 @@ func fetchTenant() (*api.TenantInfo, error) {
 		return nil, errors.Wrap(err, "failed to load api key")
 	}
 
-	switch len(tenants) {
-	case 0:
+	switch {
+	case len(tenants) == 0:
 		return nil, errors.New("no active tenants")
-	case 1:
+	case len(tenants) == 1 && viper.GetString(TENANT_NAME_FLAG) == "":
 		return tenants[0], nil
 	default:
 		tenantsByName := make(map[string]*api.TenantInfo, len(tenants))
@@ -114,7 +114,11 @@ func fetchTenant() (*api.TenantInfo, error) {
 			tenantsByName[tenant.TenantName] = tenant
 		}
 
-		tenantName := ui.GlobalWriter.SelectPrompt("Select tenant:", maps.Keys(tenantsByName))
+		var tenantName string
+		if tenantName, err = ui.GlobalWriter.SelectFlagPrompt("Select tenant:", maps.Keys(tenantsByName), TENANT_NAME_FLAG); err != nil {
+			return nil, err
+		}
+
 		if tenantName == "" {
 			return nil, errors.New("tenant selection cancelled")
 		}
@@ -188,13 +192,15 @@ func selectBackendName(tenantUUID string, deployFlow bool) (string, bool, error)
 	}
 
 	backendId := ""
-	switch len(backendsList) {
-	case 0:
+	switch {
+	case len(backendsList) == 0:
 		return "", false, ErrNoActiveBackends
-	case 1:
+	case len(backendsList) == 1 && viper.GetString(BACKEND_FLAG) == "":
 		backendId = backendsList[0].Name
 	default:
-		backendId = ui.GlobalWriter.SelectPrompt("Select backend:", maps.Keys(backendNames))
+		if backendId, err = ui.GlobalWriter.SelectFlagPrompt("Select backend:", maps.Keys(backendNames), BACKEND_FLAG); err != nil {
+			return "", false, err
+		}
 	}
 
 	return backendId, backendNames[backendId], nil
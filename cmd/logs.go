@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/k8s"
+	sentry_utils "groundcover.com/pkg/sentry"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	FOLLOW_FLAG    = "follow"
+	NODE_FLAG      = "node"
+	PREVIOUS_FLAG  = "previous"
+	CONTAINER_FLAG = "container"
+
+	COMPONENT_LABEL_KEY        = "app"
+	NODE_NAME_FIELD_FORMAT     = "spec.nodeName=%s"
+	POD_LOG_PREFIX_FORMAT      = "%s/%s %s"
+	NO_COMPONENT_PODS_FORMAT   = "no %s pods found in namespace %s"
+	AVAILABLE_COMPONENT_FORMAT = "%s, available components: %s"
+)
+
+func init() {
+	RootCmd.AddCommand(LogsCmd)
+
+	LogsCmd.Flags().BoolP(FOLLOW_FLAG, "f", false, "stream new logs as they are written")
+	LogsCmd.Flags().Duration(SINCE_FLAG, 0, "only show logs newer than this duration (e.g. 10m)")
+	LogsCmd.Flags().Int64(TAIL_FLAG, 0, "number of recent log lines to show from each container (0 shows all lines)")
+	LogsCmd.Flags().String(NODE_FLAG, "", "only show logs of pods running on this node")
+	LogsCmd.Flags().Bool(PREVIOUS_FLAG, false, "show the logs of the previous terminated containers")
+	LogsCmd.Flags().StringP(CONTAINER_FLAG, "c", "", "only show logs of this container, all containers by default")
+}
+
+var LogsCmd = &cobra.Command{
+	Use:   "logs <component>",
+	Short: "Show the logs of every pod of a groundcover component",
+	Example: `groundcover logs sensor --node ip-10-0-1-12 --previous
+groundcover logs portal -f --since 10m`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+		namespace := viper.GetString(NAMESPACE_FLAG)
+		kubeconfig := viper.GetString(KUBECONFIG_FLAG)
+		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
+		component := args[0]
+
+		var logOptions *v1.PodLogOptions
+		if logOptions, err = getLogsOptions(cmd); err != nil {
+			return err
+		}
+
+		var nodeName, container string
+		if nodeName, err = cmd.Flags().GetString(NODE_FLAG); err != nil {
+			return err
+		}
+
+		if container, err = cmd.Flags().GetString(CONTAINER_FLAG); err != nil {
+			return err
+		}
+
+		var listOptions metav1.ListOptions
+		if listOptions, err = componentListOptions(component, nodeName); err != nil {
+			return err
+		}
+
+		sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
+		sentryKubeContext.SetOnCurrentScope()
+
+		var kubeClient *k8s.Client
+		if kubeClient, err = k8s.NewKubeClient(kubeconfig, kubecontext); err != nil {
+			return err
+		}
+
+		var podList *v1.PodList
+		if podList, err = kubeClient.CoreV1().Pods(namespace).List(ctx, listOptions); err != nil {
+			return err
+		}
+
+		if len(podList.Items) == 0 {
+			return noComponentPodsError(ctx, kubeClient, namespace, component)
+		}
+
+		return streamPodsLogs(ctx, kubeClient, namespace, podList.Items, container, logOptions, cmd.OutOrStdout())
+	},
+}
+
+func getLogsOptions(cmd *cobra.Command) (*v1.PodLogOptions, error) {
+	var err error
+
+	var since time.Duration
+	if since, err = cmd.Flags().GetDuration(SINCE_FLAG); err != nil {
+		return nil, err
+	}
+
+	var tail int64
+	if tail, err = cmd.Flags().GetInt64(TAIL_FLAG); err != nil {
+		return nil, err
+	}
+
+	logOptions := newPodLogOptions(since, tail)
+
+	if logOptions.Follow, err = cmd.Flags().GetBool(FOLLOW_FLAG); err != nil {
+		return nil, err
+	}
+
+	if logOptions.Previous, err = cmd.Flags().GetBool(PREVIOUS_FLAG); err != nil {
+		return nil, err
+	}
+
+	if logOptions.Follow && logOptions.Previous {
+		return nil, fmt.Errorf("--%s can't be used with --%s", FOLLOW_FLAG, PREVIOUS_FLAG)
+	}
+
+	return logOptions, nil
+}
+
+// componentListOptions selects the component pods by the same app label selectors status uses,
+// the agent and backend names select all the agent and backend pods
+func componentListOptions(component, nodeName string) (metav1.ListOptions, error) {
+	var labelSelector string
+	switch component {
+	case AGENT_COMPONENT:
+		labelSelector = SENSOR_LABEL_SELECTOR
+	case BACKEND_COMPONENT:
+		labelSelector = BACKEND_LABEL_SELECTOR
+	default:
+		if errs := validation.IsValidLabelValue(component); len(errs) > 0 {
+			return metav1.ListOptions{}, fmt.Errorf("invalid component %q: %s", component, strings.Join(errs, ", "))
+		}
+		labelSelector = labels.Set{COMPONENT_LABEL_KEY: component}.String()
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+	if nodeName != "" {
+		listOptions.FieldSelector = fmt.Sprintf(NODE_NAME_FIELD_FORMAT, nodeName)
+	}
+
+	return listOptions, nil
+}
+
+func noComponentPodsError(ctx context.Context, kubeClient *k8s.Client, namespace, component string) error {
+	var err error
+
+	message := fmt.Sprintf(NO_COMPONENT_PODS_FORMAT, component, namespace)
+
+	var podList *v1.PodList
+	if podList, err = kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		return errors.New(message)
+	}
+
+	if components := listComponents(podList.Items); len(components) > 0 {
+		message = fmt.Sprintf(AVAILABLE_COMPONENT_FORMAT, message, strings.Join(components, ", "))
+	}
+
+	return errors.New(message)
+}
+
+// listComponents returns the sorted distinct component names of the pods
+func listComponents(pods []v1.Pod) []string {
+	componentsSet := make(map[string]bool)
+	for _, pod := range pods {
+		if component, exists := pod.Labels[COMPONENT_LABEL_KEY]; exists {
+			componentsSet[component] = true
+		}
+	}
+
+	components := make([]string, 0, len(componentsSet))
+	for component := range componentsSet {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	return components
+}
+
+// streamPodsLogs streams the logs of every container of the pods concurrently, prefixing each line with its pod, container and node.
+// A container whose logs can't be streamed doesn't stop the others, the failures are returned together
+func streamPodsLogs(ctx context.Context, kubeClient *k8s.Client, namespace string, pods []v1.Pod, container string, logOptions *v1.PodLogOptions, out io.Writer) error {
+	outputLock := &sync.Mutex{}
+
+	var failuresLock sync.Mutex
+	var failures []string
+
+	var waitGroup sync.WaitGroup
+	for _, pod := range pods {
+		for _, podContainer := range pod.Spec.Containers {
+			if container != "" && podContainer.Name != container {
+				continue
+			}
+
+			containerLogOptions := logOptions.DeepCopy()
+			containerLogOptions.Container = podContainer.Name
+
+			writer := &prefixWriter{
+				prefix: fmt.Sprintf(POD_LOG_PREFIX_FORMAT, pod.Name, podContainer.Name, pod.Spec.NodeName),
+				out:    out,
+				lock:   outputLock,
+			}
+
+			waitGroup.Add(1)
+			go func(podName string, writer *prefixWriter, containerLogOptions *v1.PodLogOptions) {
+				defer waitGroup.Done()
+
+				if err := streamContainerLogs(ctx, kubeClient, namespace, podName, containerLogOptions, writer); err != nil {
+					failuresLock.Lock()
+					defer failuresLock.Unlock()
+					failures = append(failures, fmt.Sprintf("%s/%s: %s", podName, containerLogOptions.Container, err))
+				}
+			}(pod.Name, writer, containerLogOptions)
+		}
+	}
+	waitGroup.Wait()
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("failed to stream %d containers logs: %s", len(failures), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+func streamContainerLogs(ctx context.Context, kubeClient *k8s.Client, namespace, podName string, logOptions *v1.PodLogOptions, writer *prefixWriter) error {
+	var err error
+
+	var stream io.ReadCloser
+	if stream, err = kubeClient.StreamPodLogs(ctx, namespace, podName, logOptions); err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(writer, stream)
+	writer.Flush()
+
+	// following logs ends by interrupting the command
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
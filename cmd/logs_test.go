@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestComponentListOptions(t *testing.T) {
+	listOptions, err := componentListOptions("portal", "")
+	assert.NoError(t, err)
+	assert.Equal(t, metav1.ListOptions{LabelSelector: "app=portal"}, listOptions)
+
+	listOptions, err = componentListOptions(AGENT_COMPONENT, "node-1")
+	assert.NoError(t, err)
+	assert.Equal(t, metav1.ListOptions{LabelSelector: SENSOR_LABEL_SELECTOR, FieldSelector: "spec.nodeName=node-1"}, listOptions)
+
+	listOptions, err = componentListOptions(BACKEND_COMPONENT, "")
+	assert.NoError(t, err)
+	assert.Equal(t, BACKEND_LABEL_SELECTOR, listOptions.LabelSelector)
+
+	_, err = componentListOptions("portal,app=sensor", "")
+	assert.ErrorContains(t, err, "invalid component")
+}
+
+func TestListComponents(t *testing.T) {
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "sensor"}}},
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "portal"}}},
+		{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "sensor"}}},
+		{ObjectMeta: metav1.ObjectMeta{}},
+	}
+
+	assert.Equal(t, []string{"portal", "sensor"}, listComponents(pods))
+}
+
+func TestStreamPodsLogs(t *testing.T) {
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-1", Namespace: "groundcover"},
+			Spec: v1.PodSpec{
+				NodeName:   "node-1",
+				Containers: []v1.Container{{Name: "sensor"}, {Name: "sidecar"}},
+			},
+		},
+	}
+
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(&pods[0])}
+
+	var out bytes.Buffer
+	err := streamPodsLogs(context.Background(), kubeClient, "groundcover", pods, "", &v1.PodLogOptions{}, &out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "[sensor-1/sensor node-1] fake logs\n")
+	assert.Contains(t, out.String(), "[sensor-1/sidecar node-1] fake logs\n")
+
+	out.Reset()
+	err = streamPodsLogs(context.Background(), kubeClient, "groundcover", pods, "sensor", &v1.PodLogOptions{}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "[sensor-1/sensor node-1] fake logs\n", out.String())
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"groundcover.com/pkg/segment"
+	"groundcover.com/pkg/ui"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	DEPLOY_COMMAND_NAME      = "deploy"
+	CONTEXTS_FLAG            = "contexts"
+	PARALLEL_FLAG            = "parallel"
+	DEFAULT_PARALLEL         = 4
+	CLUSTER_OUTPUT_FORMAT    = "[%s] %s\n"
+	CLUSTER_LOG_PATH_FORMAT  = "%s.%s"
+	ENV_VARIABLE_FORMAT      = "%s=%s"
+	HELM_HOMES_PATTERN       = "groundcover-helm-"
+	MULTI_CLUSTER_EVENT_NAME = "multi_cluster_deploy"
+)
+
+var (
+	ansiEscapeRegex      = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]")
+	unsafeFileNameRegex  = regexp.MustCompile("[^A-Za-z0-9._-]")
+	clusterExcludedFlags = []string{KUBECONTEXT_FLAG, ui.ASSUME_YES_FLAG, ui.NON_INTERACTIVE_FLAG, SKIP_CLI_UPDATE_FLAG, TOKEN_FLAG, API_KEY_FLAG, TELEMETRY_LOG_FLAG}
+)
+
+type clusterDeployResult struct {
+	kubecontext string
+	took        time.Duration
+	lastMessage string
+	err         error
+}
+
+func runMultiClusterDeploy(cmd *cobra.Command, contextPatterns []string) error {
+	var err error
+
+	ctx := cmd.Context()
+	kubeconfig := viper.GetString(KUBECONFIG_FLAG)
+
+	event := segment.NewEvent(MULTI_CLUSTER_EVENT_NAME)
+	event.Start()
+	defer func() {
+		event.StatusByError(err)
+	}()
+
+	if cmd.Flags().Changed(KUBECONTEXT_FLAG) || cmd.Flags().Changed(CLUSTER_NAME_FLAG) {
+		return fmt.Errorf("--%s and --%s can't be used together with --%s", KUBECONTEXT_FLAG, CLUSTER_NAME_FLAG, CONTEXTS_FLAG)
+	}
+
+	var kubecontexts []string
+	if kubecontexts, err = k8s.MatchContextNames(kubeconfig, contextPatterns); err != nil {
+		return err
+	}
+
+	parallel := viper.GetInt(PARALLEL_FLAG)
+	if parallel < 1 {
+		return fmt.Errorf("--%s must be at least 1", PARALLEL_FLAG)
+	}
+
+	promptMessage := fmt.Sprintf("Deploy groundcover to %d clusters (contexts: %s)", len(kubecontexts), strings.Join(kubecontexts, ", "))
+	if !ui.GlobalWriter.YesNoPrompt(promptMessage, true) {
+		return ErrExecutionAborted
+	}
+
+	clusterDeployments := make([]*clusterDeployment, 0, len(kubecontexts))
+	for _, kubecontext := range kubecontexts {
+		clusterDeployments = append(clusterDeployments, &clusterDeployment{
+			kubecontext: kubecontext,
+			args:        clusterDeployArgs(cmd, kubecontext, nil, CONTEXTS_FLAG, PARALLEL_FLAG),
+		})
+	}
+
+	event.Set("clustersCount", len(kubecontexts))
+
+	err = runClusterDeployments(ctx, clusterDeployments, parallel)
+	return err
+}
+
+type clusterDeployment struct {
+	kubecontext string
+	args        []string
+}
+
+func runClusterDeployments(ctx context.Context, clusterDeployments []*clusterDeployment, parallel int) error {
+	var err error
+
+	var executable string
+	if executable, err = os.Executable(); err != nil {
+		return err
+	}
+
+	var helmHomes string
+	if helmHomes, err = os.MkdirTemp("", HELM_HOMES_PATTERN); err != nil {
+		return err
+	}
+	defer os.RemoveAll(helmHomes)
+
+	var telemetrySettings *TelemetrySettings
+	if telemetrySettings, err = GetTelemetrySettings(os.Args[1:]); err != nil {
+		return err
+	}
+
+	outputLock := &sync.Mutex{}
+	semaphore := make(chan struct{}, parallel)
+	results := make([]*clusterDeployResult, len(clusterDeployments))
+
+	var waitGroup sync.WaitGroup
+	for index, deployment := range clusterDeployments {
+		waitGroup.Add(1)
+		go func(index int, deployment *clusterDeployment) {
+			defer waitGroup.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			env := clusterDeployEnv(deployment.kubecontext, filepath.Join(helmHomes, strconv.Itoa(index)), telemetrySettings.LogPath)
+			results[index] = deployCluster(ctx, executable, deployment.args, env, deployment.kubecontext, outputLock)
+		}(index, deployment)
+	}
+	waitGroup.Wait()
+
+	return printMultiClusterSummary(results)
+}
+
+// each cluster is deployed by a separate cli process, keeping the global
+// flags, sentry scope and spinners of every deployment isolated
+func deployCluster(ctx context.Context, executable string, args, env []string, kubecontext string, outputLock *sync.Mutex) *clusterDeployResult {
+	start := time.Now()
+	output := &prefixWriter{prefix: kubecontext, out: os.Stdout, lock: outputLock}
+
+	command := exec.CommandContext(ctx, executable, args...)
+	command.Stdout = output
+	command.Stderr = output
+	command.Env = env
+
+	err := command.Run()
+	output.Flush()
+
+	return &clusterDeployResult{
+		kubecontext: kubecontext,
+		took:        time.Since(start).Round(time.Second),
+		lastMessage: output.lastLine,
+		err:         err,
+	}
+}
+
+// clusterDeployArgs builds the deploy command line of a single cluster, flags set on
+// the current invocation are passed through unless excluded or overridden by clusterArgs.
+// Clusters are deployed non-interactively, so tainted nodes are only tolerated as chosen by the --tolerate flags,
+// and secrets aren't passed on the command line, where they are visible to other processes
+func clusterDeployArgs(cmd *cobra.Command, kubecontext string, clusterArgs []string, excludedFlags ...string) []string {
+	args := []string{DEPLOY_COMMAND_NAME}
+
+	excludedFlags = append(excludedFlags, clusterExcludedFlags...)
+	args = append(args, changedFlagsArgs(cmd.Flags(), excludedFlags)...)
+	args = append(args, clusterArgs...)
+
+	return append(args,
+		fmt.Sprintf("--%s=%s", KUBECONTEXT_FLAG, kubecontext),
+		fmt.Sprintf("--%s", ui.NON_INTERACTIVE_FLAG),
+		fmt.Sprintf("--%s", SKIP_CLI_UPDATE_FLAG),
+	)
+}
+
+// clusterDeployEnv passes the secrets through the environment, and gives each cluster its own helm home and telemetry log
+func clusterDeployEnv(kubecontext, helmHome, telemetryLogPath string) []string {
+	env := os.Environ()
+
+	for _, secretFlag := range []string{TOKEN_FLAG, API_KEY_FLAG} {
+		if value := viper.GetString(secretFlag); value != "" {
+			env = append(env, fmt.Sprintf(ENV_VARIABLE_FORMAT, ui.EnvName(secretFlag), value))
+		}
+	}
+
+	env = append(env, fmt.Sprintf(ENV_VARIABLE_FORMAT, helm.HELM_HOME_ENV, helmHome))
+
+	if telemetryLogPath != "" {
+		clusterLogPath := fmt.Sprintf(CLUSTER_LOG_PATH_FORMAT, telemetryLogPath, unsafeFileNameRegex.ReplaceAllString(kubecontext, "_"))
+		env = append(env, fmt.Sprintf(ENV_VARIABLE_FORMAT, ui.EnvName(TELEMETRY_LOG_FLAG), clusterLogPath))
+	}
+
+	return env
+}
+
+// changedFlagsArgs returns the flags set on the command line as arguments, slice flags are repeated per value
+func changedFlagsArgs(flags *pflag.FlagSet, excludedFlags []string) []string {
+	var args []string
+
+	flags.Visit(func(flag *pflag.Flag) {
+		if slices.Contains(excludedFlags, flag.Name) {
+			return
+		}
+
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range sliceValue.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", flag.Name, value))
+			}
+			return
+		}
+
+		args = append(args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+
+	return args
+}
+
+func printMultiClusterSummary(results []*clusterDeployResult) error {
+	var failedClusters []string
+	var summaryBuffer strings.Builder
+
+	tableWriter := tabwriter.NewWriter(&summaryBuffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "CONTEXT\tSTATUS\tTOOK\tLAST MESSAGE")
+
+	for _, result := range results {
+		status := "success"
+		if result.err != nil {
+			status = "failed"
+			failedClusters = append(failedClusters, result.kubecontext)
+		}
+
+		fmt.Fprintf(tableWriter, "%s\t%s\t%s\t%s\n", result.kubecontext, status, result.took, result.lastMessage)
+	}
+	tableWriter.Flush()
+
+	ui.GlobalWriter.PrintlnWithPrefixln("Multi cluster deployment summary:")
+	ui.GlobalWriter.Printf("%s", summaryBuffer.String())
+
+	if len(failedClusters) > 0 {
+		return fmt.Errorf("groundcover deployment failed on %d/%d clusters: %s", len(failedClusters), len(results), strings.Join(failedClusters, ", "))
+	}
+
+	return nil
+}
+
+type prefixWriter struct {
+	prefix   string
+	out      io.Writer
+	lock     *sync.Mutex
+	buffer   bytes.Buffer
+	lastLine string
+}
+
+func (writer *prefixWriter) Write(data []byte) (int, error) {
+	writer.buffer.Write(data)
+
+	for {
+		line, err := writer.buffer.ReadString('\n')
+		if err != nil {
+			// keep the partial line until its end arrives
+			writer.buffer.WriteString(line)
+			return len(data), nil
+		}
+
+		writer.writeLine(line)
+	}
+}
+
+func (writer *prefixWriter) Flush() {
+	if writer.buffer.Len() > 0 {
+		writer.writeLine(writer.buffer.String())
+		writer.buffer.Reset()
+	}
+}
+
+func (writer *prefixWriter) writeLine(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	writer.lastLine = ansiEscapeRegex.ReplaceAllString(line, "")
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	fmt.Fprintf(writer.out, CLUSTER_OUTPUT_FORMAT, writer.prefix, line)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/ui"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer := &prefixWriter{prefix: "prod", out: &out, lock: &sync.Mutex{}}
+
+	writer.Write([]byte("first line\nsecond "))
+	writer.Write([]byte("line\n\n\x1b[31mfailed\x1b[0m"))
+	writer.Flush()
+
+	assert.Equal(t, "[prod] first line\n[prod] second line\n[prod] \x1b[31mfailed\x1b[0m\n", out.String())
+	assert.Equal(t, "failed", writer.lastLine)
+}
+
+func TestClusterDeployArgs(t *testing.T) {
+	cmd := &cobra.Command{Use: "deploy"}
+	cmd.Flags().StringSlice(VALUES_FLAG, []string{}, "")
+	cmd.Flags().StringSlice(CONTEXTS_FLAG, []string{}, "")
+	cmd.Flags().String(MODE_FLAG, "", "")
+	cmd.Flags().Bool(LOW_RESOURCES_FLAG, false, "")
+	cmd.Flags().String(TOKEN_FLAG, "", "")
+	cmd.Flags().String(TELEMETRY_LOG_FLAG, "", "")
+	cmd.Flags().Bool(ui.ASSUME_YES_FLAG, false, "")
+
+	assert.NoError(t, cmd.Flags().Parse([]string{"--values=a.yaml", "--values=b.yaml", "--contexts=prod-*", "--mode=legacy", "--token=secret", "--telemetry-log=telemetry.log", "--yes"}))
+
+	args := clusterDeployArgs(cmd, "prod-eu", []string{"--namespace=observability"}, CONTEXTS_FLAG)
+
+	assert.Equal(t, []string{
+		"deploy",
+		"--mode=legacy",
+		"--values=a.yaml",
+		"--values=b.yaml",
+		"--namespace=observability",
+		"--kube-context=prod-eu",
+		"--non-interactive",
+		"--skip-cli-update",
+	}, args)
+}
+
+func TestClusterDeployEnv(t *testing.T) {
+	viper.Set(TOKEN_FLAG, "secret")
+	defer viper.Set(TOKEN_FLAG, "")
+
+	env := clusterDeployEnv("arn:aws:eks:us-east-1:1234:cluster/prod", "/tmp/helm/0", "telemetry.log")
+
+	assert.Contains(t, env, "GROUNDCOVER_TOKEN=secret")
+	assert.Contains(t, env, "GROUNDCOVER_HELM_HOME=/tmp/helm/0")
+	assert.Contains(t, env, "GROUNDCOVER_TELEMETRY_LOG=telemetry.log.arn_aws_eks_us-east-1_1234_cluster_prod")
+	assert.NotContains(t, env, "GROUNDCOVER_API_KEY=")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"groundcover.com/pkg/ui"
+)
+
+const (
+	OUTPUT_FLAG = "output"
+	JSON_OUTPUT = "json"
+	YAML_OUTPUT = "yaml"
+)
+
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP(OUTPUT_FLAG, "o", "", "output format [options: json, yaml]")
+}
+
+func getOutputFormat(cmd *cobra.Command) (string, error) {
+	// commands without an output flag always print human readable output
+	outputFormat, _ := cmd.Flags().GetString(OUTPUT_FLAG)
+
+	switch outputFormat {
+	case "", JSON_OUTPUT, YAML_OUTPUT:
+		return outputFormat, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, supported formats: %s, %s", outputFormat, JSON_OUTPUT, YAML_OUTPUT)
+	}
+}
+
+func isMachineOutput(cmd *cobra.Command) bool {
+	outputFormat, err := getOutputFormat(cmd)
+	return err == nil && outputFormat != ""
+}
+
+func printOutput(outputFormat string, report interface{}) error {
+	var err error
+
+	var data []byte
+	if data, err = json.MarshalIndent(report, "", "  "); err != nil {
+		return err
+	}
+
+	if outputFormat == YAML_OUTPUT {
+		// marshal through json so the yaml output respects the json field names
+		var document interface{}
+		if err = json.Unmarshal(data, &document); err != nil {
+			return err
+		}
+
+		if data, err = yaml.Marshal(document); err != nil {
+			return err
+		}
+	}
+
+	ui.QuietWriter.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOutputFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		outputFlag     string
+		expectedFormat string
+		expectedError  bool
+	}{
+		{
+			name:           "human readable output",
+			outputFlag:     "",
+			expectedFormat: "",
+		},
+		{
+			name:           "json output",
+			outputFlag:     JSON_OUTPUT,
+			expectedFormat: JSON_OUTPUT,
+		},
+		{
+			name:           "yaml output",
+			outputFlag:     YAML_OUTPUT,
+			expectedFormat: YAML_OUTPUT,
+		},
+		{
+			name:          "unknown output",
+			outputFlag:    "xml",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addOutputFlag(cmd)
+			assert.NoError(t, cmd.Flags().Set(OUTPUT_FLAG, tt.outputFlag))
+
+			outputFormat, err := getOutputFormat(cmd)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.False(t, isMachineOutput(cmd))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFormat, outputFormat)
+		})
+	}
+}
+
+func TestGetOutputFormatWithoutFlag(t *testing.T) {
+	outputFormat, err := getOutputFormat(&cobra.Command{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", outputFormat)
+}
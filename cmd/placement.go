@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+
+	"groundcover.com/pkg/k8s"
+	sentry_utils "groundcover.com/pkg/sentry"
+	v1 "k8s.io/api/core/v1"
+)
+
+func getReleaseDeployableNodesCount(ctx context.Context, kubeClient *k8s.Client, chartValues map[string]interface{}, sentryKubeContext *sentry_utils.KubeContext) (int, error) {
+	var err error
+
+	var nodesReport *k8s.NodesReport
+	if nodesReport, err = validateNodes(ctx, kubeClient, sentryKubeContext); err != nil {
+		return 0, err
+	}
+
+	return countReleaseDeployableNodes(nodesReport, chartValues)
+}
+
+// countReleaseDeployableNodes counts the compatible nodes the agent can be scheduled on with the release tolerations, node selector and affinity
+func countReleaseDeployableNodes(nodesReport *k8s.NodesReport, chartValues map[string]interface{}) (int, error) {
+	var err error
+
+	var placement *k8s.AgentPlacement
+	if placement, err = getAgentPlacement(chartValues); err != nil {
+		return 0, err
+	}
+
+	tolerationManager := &k8s.TolerationManager{
+		TaintedNodes: nodesReport.TaintedNodes,
+	}
+
+	tolerableNodes := tolerationManager.GetTolerableNodesByTolerations(placement.Tolerations)
+
+	var deployableNodesCount int
+	for _, node := range append(append([]*k8s.NodeSummary{}, nodesReport.CompatibleNodes...), tolerableNodes...) {
+		var matches bool
+		if matches, err = placement.Matches(node); err != nil {
+			return 0, err
+		}
+
+		if matches {
+			deployableNodesCount++
+		}
+	}
+
+	return deployableNodesCount, nil
+}
+
+func getAgentPlacement(chartValues map[string]interface{}) (*k8s.AgentPlacement, error) {
+	var err error
+
+	placement := &k8s.AgentPlacement{}
+	if placement.Tolerations, err = getAgentTolerations(chartValues); err != nil {
+		return nil, err
+	}
+
+	if err = decodeAgentValue(chartValues, "nodeSelector", &placement.NodeSelector); err != nil {
+		return nil, err
+	}
+
+	if err = decodeAgentValue(chartValues, "affinity", &placement.Affinity); err != nil {
+		return nil, err
+	}
+
+	return placement, nil
+}
+
+func getAgentTolerations(chartValues map[string]interface{}) ([]v1.Toleration, error) {
+	var err error
+
+	var tolerations []v1.Toleration
+	if err = decodeAgentValue(chartValues, "tolerations", &tolerations); err != nil {
+		return nil, err
+	}
+
+	return tolerations, nil
+}
+
+// decodeAgentValue decodes the agent value with the given key into out, leaving it untouched when there's no such value
+func decodeAgentValue(chartValues map[string]interface{}, key string, out interface{}) error {
+	var err error
+
+	agentValues, ok := chartValues["agent"].(map[string]interface{})
+	if !ok || agentValues[key] == nil {
+		return nil
+	}
+
+	var data []byte
+	if data, err = json.Marshal(agentValues[key]); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetAgentTolerations(t *testing.T) {
+	chartValues := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "dedicated", "operator": "Exists", "effect": "NoSchedule"},
+			},
+		},
+	}
+
+	tolerations, err := getAgentTolerations(chartValues)
+	assert.NoError(t, err)
+	assert.Len(t, tolerations, 1)
+	assert.Equal(t, "dedicated", tolerations[0].Key)
+	assert.EqualValues(t, "NoSchedule", tolerations[0].Effect)
+
+	tolerations, err = getAgentTolerations(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Empty(t, tolerations)
+}
+
+func TestCountReleaseDeployableNodes(t *testing.T) {
+	nodesReport := &k8s.NodesReport{
+		CompatibleNodes: []*k8s.NodeSummary{
+			{Name: "general", Labels: map[string]string{"pool": "general"}},
+			{Name: "gpu", Labels: map[string]string{"pool": "gpu"}},
+		},
+		TaintedNodes: []*k8s.IncompatibleNode{
+			{
+				NodeSummary: &k8s.NodeSummary{
+					Name:   "dedicated",
+					Labels: map[string]string{"pool": "dedicated"},
+					Taints: []v1.Taint{{Key: "dedicated", Effect: v1.TaintEffectNoSchedule}},
+				},
+			},
+		},
+	}
+
+	chartValues := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "dedicated", "operator": "Exists"},
+			},
+			"affinity": map[string]interface{}{
+				"nodeAffinity": map[string]interface{}{
+					"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+						"nodeSelectorTerms": []interface{}{
+							map[string]interface{}{
+								"matchExpressions": []interface{}{
+									map[string]interface{}{"key": "pool", "operator": "NotIn", "values": []interface{}{"gpu"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	count, err := countReleaseDeployableNodes(nodesReport, chartValues)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = countReleaseDeployableNodes(nodesReport, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
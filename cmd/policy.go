@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/k8s"
+)
+
+const (
+	POLICY_FLAG = "policy"
+)
+
+// loadPolicy returns the requirements policy set by the policy flag, or nil when it isn't set
+func loadPolicy() (*k8s.Policy, error) {
+	path := viper.GetString(POLICY_FLAG)
+	if path == "" {
+		return nil, nil
+	}
+
+	return k8s.LoadPolicy(path)
+}
+
+// getNodeRequirements returns the default nodes requirements with the requirements policy applied, if set
+func getNodeRequirements() (*k8s.NodeMinimumRequirements, error) {
+	var err error
+
+	var policy *k8s.Policy
+	if policy, err = loadPolicy(); err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return k8s.DefaultNodeRequirements, nil
+	}
+
+	return policy.NodeRequirements(k8s.DefaultNodeRequirements), nil
+}
+
+// getClusterRequirements returns the default cluster requirements with the requirements policy applied, if set
+func getClusterRequirements() (*k8s.ClusterRequirements, error) {
+	var err error
+
+	var policy *k8s.Policy
+	if policy, err = loadPolicy(); err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return k8s.DefaultClusterRequirements, nil
+	}
+
+	return policy.ClusterRequirements(k8s.DefaultClusterRequirements), nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/k8s"
+	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/ui"
+)
+
+func init() {
+	RootCmd.AddCommand(PreflightCmd)
+
+	PreflightCmd.Flags().String(STORAGE_CLASS_FLAG, "", "override storage class")
+	addKernelProbeFlags(PreflightCmd.Flags())
+}
+
+var PreflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Validate cluster and nodes compatibility without deploying groundcover",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+		namespace := viper.GetString(NAMESPACE_FLAG)
+		kubeconfig := viper.GetString(KUBECONFIG_FLAG)
+		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
+
+		// these flags are shared with the deploy command, bind them to this command instance
+		for _, flag := range []string{STORAGE_CLASS_FLAG, PROBE_KERNEL_FLAG, KERNEL_PROBE_IMAGE_FLAG} {
+			if err = viper.BindPFlag(flag, cmd.Flags().Lookup(flag)); err != nil {
+				return err
+			}
+		}
+
+		sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
+		sentryKubeContext.SetOnCurrentScope()
+
+		var kubeClient *k8s.Client
+		if kubeClient, err = k8s.NewKubeClient(kubeconfig, kubecontext); err != nil {
+			return err
+		}
+
+		// run all checks before failing, so a single run reports every issue
+		clusterErr := validateCluster(ctx, kubeClient, namespace, sentryKubeContext)
+		nodesReport, nodesErr := validateNodes(ctx, kubeClient, sentryKubeContext)
+
+		// preflight leaves the cluster unchanged, the probe jobs are deleted and the namespace isn't created
+		if nodesErr == nil && viper.GetBool(PROBE_KERNEL_FLAG) {
+			probeNodesKernel(ctx, kubeClient, namespace, false, nodesReport)
+		}
+
+		if nodesErr == nil && nodesReport.IsLegacyKernel() {
+			ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(LEGACY_KERNEL_MODE_MESSAGE_FORMAT, k8s.StableKernelVersionRange))
+		}
+
+		if err = errors.Join(clusterErr, nodesErr); err != nil {
+			return err
+		}
+
+		ui.GlobalWriter.PrintlnWithPrefixln("Cluster is ready for groundcover installation")
+
+		return nil
+	},
+}
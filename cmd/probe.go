@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/k8s"
+	"groundcover.com/pkg/segment"
+	"groundcover.com/pkg/ui"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	PROBE_KERNEL_FLAG          = "probe-kernel"
+	KERNEL_PROBE_IMAGE_FLAG    = "kernel-probe-image"
+	DEFAULT_KERNEL_PROBE_IMAGE = "busybox:1.36"
+
+	KERNEL_PROBE_POLLING_INTERVAL = time.Second * 5
+	KERNEL_PROBE_POLLING_RETRIES  = 36
+	KERNEL_PROBE_POLLING_TIMEOUT  = time.Minute * 3
+
+	WAIT_FOR_KERNEL_PROBES_FORMAT = "Probing nodes kernel capabilities (%d/%d node pools)"
+	KERNEL_PROBE_FALLBACK_FORMAT  = "Kernel probe failed, falling back to kernel versions: %s"
+	KERNEL_CAPABILITIES_FORMAT    = "Kernel %s (%d nodes): BTF %t, cgroup v%d, lockdown %s, eBPF program types: %s"
+	UNKNOWN_PROGRAM_TYPES_FORMAT  = "Kernel %s (%d nodes): eBPF program types are unknown, the node doesn't expose its kernel config, falling back to kernel versions"
+	NAMESPACE_NOT_FOUND_FORMAT    = "Namespace %s doesn't exist, probing from the %s namespace"
+
+	KERNEL_PROBE_EVENT_NAME = "kernel_probe"
+)
+
+var KernelProbePollingPolicy = ui.PollingPolicy{
+	Phase:    "probe",
+	Interval: KERNEL_PROBE_POLLING_INTERVAL,
+	Timeout:  KERNEL_PROBE_POLLING_TIMEOUT,
+	Retries:  KERNEL_PROBE_POLLING_RETRIES,
+}
+
+func addKernelProbeFlags(flags *pflag.FlagSet) {
+	flags.Bool(PROBE_KERNEL_FLAG, false, "probe each node pool kernel eBPF capabilities with a short lived privileged job, instead of relying on kernel versions only")
+	flags.String(KERNEL_PROBE_IMAGE_FLAG, DEFAULT_KERNEL_PROBE_IMAGE, "image of the kernel probe job, any image with busybox utilities")
+}
+
+// probeNodesKernel sets the probed kernel capabilities on the report nodes, one probe runs per node pool.
+// The probe is best effort, on failure the nodes are left without capabilities and kernel versions are used instead.
+// The namespace is only created when createNamespace is set, otherwise a missing namespace is replaced by the default one
+func probeNodesKernel(ctx context.Context, kubeClient *k8s.Client, namespace string, createNamespace bool, nodesReport *k8s.NodesReport) {
+	var err error
+
+	event := segment.NewEvent(KERNEL_PROBE_EVENT_NAME)
+	event.Start()
+	defer func() {
+		event.StatusByError(err)
+	}()
+
+	pools := nodesReport.KernelProbePools()
+	if len(pools) == 0 {
+		return
+	}
+
+	if namespace, err = getKernelProbeNamespace(ctx, kubeClient, namespace, createNamespace); err != nil {
+		ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(KERNEL_PROBE_FALLBACK_FORMAT, err))
+		return
+	}
+
+	jobs := make([]*batchv1.Job, 0, len(pools))
+	defer func() {
+		for _, job := range jobs {
+			kubeClient.DeleteKernelProbeJob(ctx, job)
+		}
+	}()
+
+	image := viper.GetString(KERNEL_PROBE_IMAGE_FLAG)
+	for _, pool := range pools {
+		var job *batchv1.Job
+		if job, err = kubeClient.CreateKernelProbeJob(ctx, namespace, image, pool[0]); err != nil {
+			ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(KERNEL_PROBE_FALLBACK_FORMAT, err))
+			return
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	spinner := ui.GlobalWriter.NewSpinner(fmt.Sprintf(WAIT_FOR_KERNEL_PROBES_FORMAT, 0, len(pools)))
+	spinner.SetStopMessage(fmt.Sprintf("Nodes kernel capabilities probed (%d/%d node pools)", len(pools), len(pools)))
+	spinner.SetStopFailMessage("Kernel probe didn't complete, falling back to kernel versions")
+
+	spinner.Start()
+
+	capabilities := make([]*k8s.KernelCapabilities, len(pools))
+	failures := make([]error, len(pools))
+
+	probeFunc := func() error {
+		completed := 0
+
+		for index, job := range jobs {
+			if capabilities[index] == nil && failures[index] == nil {
+				capabilities[index], failures[index] = kubeClient.GetKernelProbeResult(ctx, job)
+				if errors.Is(failures[index], k8s.ErrKernelProbeNotCompleted) {
+					failures[index] = nil
+					continue
+				}
+			}
+
+			completed++
+		}
+
+		spinner.WriteMessage(fmt.Sprintf(WAIT_FOR_KERNEL_PROBES_FORMAT, completed, len(pools)))
+
+		if completed == len(pools) {
+			return nil
+		}
+
+		return ui.RetryableError(errors.New("not all kernel probes completed"))
+	}
+
+	if err = spinner.PollWithPolicy(ctx, probeFunc, KernelProbePollingPolicy); err != nil {
+		spinner.WriteStopFail()
+	} else {
+		spinner.WriteStop()
+	}
+
+	for index, pool := range pools {
+		if failures[index] != nil {
+			ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(KERNEL_PROBE_FALLBACK_FORMAT, failures[index]))
+			continue
+		}
+
+		if capabilities[index] == nil {
+			continue
+		}
+
+		event.Set(fmt.Sprintf("pool%dCapabilities", index), capabilities[index])
+
+		if capabilities[index].ProgramTypesUnknown {
+			ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(UNKNOWN_PROGRAM_TYPES_FORMAT, capabilities[index].Kernel, len(pool)))
+			continue
+		}
+
+		for _, node := range pool {
+			node.KernelCapabilities = capabilities[index]
+		}
+
+		ui.GlobalWriter.PrintSuccessMessageln(fmt.Sprintf(KERNEL_CAPABILITIES_FORMAT,
+			capabilities[index].Kernel,
+			len(pool),
+			capabilities[index].BTF,
+			capabilities[index].CgroupVersion,
+			capabilities[index].Lockdown,
+			strings.Join(capabilities[index].ProgramTypes, ", "),
+		))
+	}
+}
+
+func getKernelProbeNamespace(ctx context.Context, kubeClient *k8s.Client, namespace string, createNamespace bool) (string, error) {
+	var err error
+
+	if createNamespace {
+		return namespace, kubeClient.EnsureNamespace(ctx, namespace)
+	}
+
+	var exists bool
+	if exists, err = kubeClient.NamespaceExists(ctx, namespace); err != nil {
+		return "", err
+	}
+
+	if !exists {
+		ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(NAMESPACE_NOT_FOUND_FORMAT, namespace, metav1.NamespaceDefault))
+		return metav1.NamespaceDefault, nil
+	}
+
+	return namespace, nil
+}
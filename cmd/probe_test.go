@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetKernelProbeNamespaceDoesntCreateNamespace(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset()}
+
+	namespace, err := getKernelProbeNamespace(ctx, kubeClient, "groundcover", false)
+	assert.NoError(t, err)
+	assert.Equal(t, metav1.NamespaceDefault, namespace)
+
+	exists, err := kubeClient.NamespaceExists(ctx, "groundcover")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestGetKernelProbeNamespaceExisting(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "groundcover"}})}
+
+	namespace, err := getKernelProbeNamespace(ctx, kubeClient, "groundcover", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "groundcover", namespace)
+}
+
+func TestGetKernelProbeNamespaceCreatesNamespace(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset()}
+
+	namespace, err := getKernelProbeNamespace(ctx, kubeClient, "groundcover", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "groundcover", namespace)
+
+	exists, err := kubeClient.NamespaceExists(ctx, "groundcover")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/ui"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	NO_RESOURCE_VALUE = "-"
+)
+
+func init() {
+	RootCmd.AddCommand(RecommendCmd)
+
+	addOutputFlag(RecommendCmd)
+	RecommendCmd.Flags().StringSliceP(VALUES_FLAG, "f", []string{}, "specify values in a YAML file or a URL (can specify multiple)")
+	RecommendCmd.Flags().Bool(LOW_RESOURCES_FLAG, false, "set low resources limits")
+	RecommendCmd.Flags().Bool(ENABLE_CUSTOM_METRICS_FLAG, false, "enable custom metrics scraping")
+	RecommendCmd.Flags().Bool(ENABLE_KUBE_STATE_METRICS_FLAG, false, "enable kube state metrics deployment")
+	RecommendCmd.Flags().String(VERSION_FLAG, "", "specify a version constraint for the chart version to use, the latest version is used by default")
+	RecommendCmd.Flags().StringSlice(TOLERATE_FLAG, []string{}, "tolerate node taints matching key[=value][:effect] instead of prompting, \"none\" tolerates no taints (can specify multiple)")
+	RecommendCmd.Flags().Bool(TOLERATE_ALL_TAINTS_FLAG, false, "tolerate all node taints instead of prompting")
+	RecommendCmd.Flags().String(NODE_SELECTOR_FLAG, "", "deploy the agent only on nodes matching this label selector (e.g. kubernetes.io/os=linux)")
+	RecommendCmd.Flags().String(EXCLUDE_NODE_SELECTOR_FLAG, "", "don't deploy the agent on nodes matching this label selector (e.g. node-role=gpu)")
+	RecommendCmd.Flags().StringSlice(EXCLUDE_NODES_FLAG, []string{}, "don't deploy the agent on these nodes (can specify multiple)")
+	RecommendCmd.Flags().Bool(RESET_VALUES_FLAG, false, "build the values from the flags and values files alone instead of the installed release values")
+	RecommendCmd.MarkFlagsMutuallyExclusive(TOLERATE_FLAG, TOLERATE_ALL_TAINTS_FLAG)
+}
+
+type Recommendation struct {
+	ChartVersion           string                     `json:"chartVersion"`
+	NodesCount             int                        `json:"nodesCount"`
+	DeployableNodesCount   int                        `json:"deployableNodesCount"`
+	CompatibleNodesCount   int                        `json:"compatibleNodesCount"`
+	TaintedNodesCount      int                        `json:"taintedNodesCount"`
+	IncompatibleNodesCount int                        `json:"incompatibleNodesCount"`
+	AllocatableResources   *helm.AllocatableResources `json:"allocatableResources"`
+	MinimalKernelVersion   string                     `json:"minimalKernelVersion"`
+	MaximalKernelVersion   string                     `json:"maximalKernelVersion"`
+	IsLegacyKernel         bool                       `json:"isLegacyKernel"`
+	Presets                []*helm.PresetChoice       `json:"presets"`
+	Components             []*helm.ComponentResources `json:"components"`
+}
+
+var RecommendCmd = &cobra.Command{
+	Use:   "recommend",
+	Short: "Explain the resources presets and the components resources groundcover would be deployed with",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+		namespace := viper.GetString(NAMESPACE_FLAG)
+		kubeconfig := viper.GetString(KUBECONFIG_FLAG)
+		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
+		releaseName := viper.GetString(HELM_RELEASE_FLAG)
+
+		// these flags are shared with the deploy command, bind them to this command instance
+		for _, flag := range []string{
+			VALUES_FLAG, LOW_RESOURCES_FLAG, ENABLE_CUSTOM_METRICS_FLAG, ENABLE_KUBE_STATE_METRICS_FLAG, VERSION_FLAG, RESET_VALUES_FLAG,
+			TOLERATE_FLAG, TOLERATE_ALL_TAINTS_FLAG, NODE_SELECTOR_FLAG, EXCLUDE_NODE_SELECTOR_FLAG, EXCLUDE_NODES_FLAG,
+		} {
+			if err = viper.BindPFlag(flag, cmd.Flags().Lookup(flag)); err != nil {
+				return err
+			}
+		}
+
+		var outputFormat string
+		if outputFormat, err = getOutputFormat(cmd); err != nil {
+			return err
+		}
+
+		sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
+		sentryKubeContext.SetOnCurrentScope()
+
+		var kubeClient *k8s.Client
+		if kubeClient, err = k8s.NewKubeClient(kubeconfig, kubecontext); err != nil {
+			return err
+		}
+
+		var clusterName string
+		if clusterName, err = kubeClient.GetClusterName(); err != nil {
+			return err
+		}
+
+		if k8s.IsLocalCluster(clusterName) {
+			viper.Set(LOW_RESOURCES_FLAG, true)
+		}
+
+		var nodeTargeting *k8s.NodeTargeting
+		if nodeTargeting, err = getNodeTargeting(); err != nil {
+			return err
+		}
+
+		var nodeRequirements *k8s.NodeMinimumRequirements
+		if nodeRequirements, err = getNodeRequirements(); err != nil {
+			return err
+		}
+
+		var nodesSummaries []*k8s.NodeSummary
+		if nodesSummaries, err = kubeClient.GetNodesSummaries(ctx); err != nil {
+			return err
+		}
+
+		nodesReport := nodeRequirements.GenerateNodeReport(nodesSummaries)
+		if len(nodesReport.CompatibleNodes) == 0 {
+			return errors.New("no compatible nodes, run \"groundcover preflight\" for details")
+		}
+
+		// the presets are chosen for the nodes deploy would target, with the same policy, exclusions and tolerations
+		if _, err = nodeTargeting.ExcludeUnsupportedNodes(nodeRequirements, nodesReport); err != nil {
+			return err
+		}
+
+		deployableNodes, tolerations, err := getDeployableNodesAndTolerations(nodesReport, nodeTargeting, sentryKubeContext)
+		if err != nil {
+			return err
+		}
+
+		if len(deployableNodes) == 0 {
+			return errors.New("no deployable nodes, check the node targeting and toleration flags")
+		}
+
+		sentryHelmContext := sentry_utils.NewHelmContext(releaseName, CHART_NAME, HELM_REPO_URL)
+		sentryHelmContext.SetOnCurrentScope()
+
+		var helmClient *helm.Client
+		if helmClient, err = helm.NewHelmClient(namespace, kubecontext); err != nil {
+			return err
+		}
+
+		var chart *helm.Chart
+		if chart, err = pollGetChart(ctx, helmClient, sentryHelmContext); err != nil {
+			return err
+		}
+
+		var isUpgrade bool
+		var release *helm.Release
+		if release, isUpgrade, err = helmClient.IsReleaseInstalled(releaseName); err != nil {
+			return err
+		}
+
+		var chartValues map[string]interface{}
+		if isUpgrade && !viper.GetBool(RESET_VALUES_FLAG) {
+			if chartValues, err = helm.CopyValues(release.Config); err != nil {
+				return err
+			}
+		}
+
+		// the ingestion key isn't part of the recommendation, so the installed one or a placeholder is used
+		if chartValues, err = generateChartValues(chartValues, PREVIEW_API_KEY, viper.GetString(INSTALLATION_ID_FLAG), clusterName, deployableNodes, tolerations, nodeTargeting, nodesReport, sentryHelmContext); err != nil {
+			return err
+		}
+
+		var recommendation *Recommendation
+		if recommendation, err = generateRecommendation(chart, nodesReport, deployableNodes, chartValues); err != nil {
+			return err
+		}
+
+		if outputFormat != "" {
+			return printOutput(outputFormat, recommendation)
+		}
+
+		printRecommendation(recommendation)
+
+		return nil
+	},
+}
+
+// generateRecommendation explains the resources presets deploy chooses for the deployable nodes, and the components resources of the chart values deploy generates
+func generateRecommendation(chart *helm.Chart, nodesReport *k8s.NodesReport, deployableNodes []*k8s.NodeSummary, chartValues map[string]interface{}) (*Recommendation, error) {
+	var err error
+
+	allocatableResources := helm.CalcAllocatableResources(deployableNodes)
+	presets := getResourcesPresets(allocatableResources, nodesReport.MaximalKernelVersion())
+
+	var components []*helm.ComponentResources
+	if components, err = chart.ComponentsResources(chartValues); err != nil {
+		return nil, err
+	}
+
+	recommendation := &Recommendation{
+		ChartVersion:           chart.Version().String(),
+		NodesCount:             nodesReport.NodesCount(),
+		DeployableNodesCount:   len(deployableNodes),
+		CompatibleNodesCount:   len(nodesReport.CompatibleNodes),
+		TaintedNodesCount:      len(nodesReport.TaintedNodes),
+		IncompatibleNodesCount: len(nodesReport.IncompatibleNodes),
+		AllocatableResources:   allocatableResources,
+		MinimalKernelVersion:   nodesReport.MinimalKernelVersion().String(),
+		MaximalKernelVersion:   nodesReport.MaximalKernelVersion().String(),
+		IsLegacyKernel:         nodesReport.IsLegacyKernel(),
+		Presets:                presets,
+		Components:             components,
+	}
+
+	return recommendation, nil
+}
+
+func printRecommendation(recommendation *Recommendation) {
+	allocatableResources := recommendation.AllocatableResources
+
+	ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf(
+		"Nodes: %d (deployable: %d, compatible: %d, tainted: %d, incompatible: %d)",
+		recommendation.NodesCount, recommendation.DeployableNodesCount, recommendation.CompatibleNodesCount, recommendation.TaintedNodesCount, recommendation.IncompatibleNodesCount,
+	))
+	ui.GlobalWriter.Printf("%s minimal node allocatable cpu: %s, memory: %s\n", ui.Bullet, allocatableResources.MinCpu, allocatableResources.MinMemory)
+	ui.GlobalWriter.Printf("%s total allocatable cpu: %s, memory: %s\n", ui.Bullet, allocatableResources.TotalCpu, allocatableResources.TotalMemory)
+	ui.GlobalWriter.Printf("%s kernel versions: %s - %s\n", ui.Bullet, recommendation.MinimalKernelVersion, recommendation.MaximalKernelVersion)
+
+	// the legacy kernel warning is printed when the values are generated
+
+	var presetsBuffer strings.Builder
+	tableWriter := tabwriter.NewWriter(&presetsBuffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "COMPONENT\tPRESET\tREASON")
+	for _, preset := range recommendation.Presets {
+		fmt.Fprintf(tableWriter, "%s\t%s\t%s\n", preset.Component, preset.Name(), preset.Reason)
+	}
+	tableWriter.Flush()
+
+	ui.GlobalWriter.PrintlnWithPrefixln("Resources presets:")
+	ui.GlobalWriter.Printf("%s", presetsBuffer.String())
+
+	var componentsBuffer strings.Builder
+	tableWriter = tabwriter.NewWriter(&componentsBuffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "COMPONENT\tCPU REQUEST\tMEMORY REQUEST\tCPU LIMIT\tMEMORY LIMIT")
+	for _, component := range recommendation.Components {
+		fmt.Fprintf(
+			tableWriter, "%s\t%s\t%s\t%s\t%s\n",
+			component.Component,
+			resourceValue(component.Requests, v1.ResourceCPU),
+			resourceValue(component.Requests, v1.ResourceMemory),
+			resourceValue(component.Limits, v1.ResourceCPU),
+			resourceValue(component.Limits, v1.ResourceMemory),
+		)
+	}
+	tableWriter.Flush()
+
+	ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("Components resources (chart %s):", recommendation.ChartVersion))
+	ui.GlobalWriter.Printf("%s", componentsBuffer.String())
+}
+
+func resourceValue(resources v1.ResourceList, name v1.ResourceName) string {
+	quantity, exists := resources[name]
+	if !exists {
+		return NO_RESOURCE_VALUE
+	}
+
+	return quantity.String()
+}
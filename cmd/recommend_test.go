@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGenerateRecommendation(t *testing.T) {
+	cpu := resource.MustParse("4")
+	memory := resource.MustParse("16Gi")
+	nodesReport := k8s.DefaultNodeRequirements.GenerateNodeReport([]*k8s.NodeSummary{
+		{Name: "node", CPU: &cpu, Memory: &memory, Kernel: "5.15.0", Architecture: "amd64", OperatingSystem: "linux"},
+	})
+
+	groundcoverChart := &helm.Chart{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "groundcover", Version: "1.2.3"},
+			Values: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"sensor": map[string]interface{}{
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"cpu": "100m", "memory": "512Mi"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	chartValues := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"sensor": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"memory": "1Gi"},
+				},
+			},
+		},
+	}
+
+	recommendation, err := generateRecommendation(groundcoverChart, nodesReport, nodesReport.CompatibleNodes, chartValues)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", recommendation.ChartVersion)
+	assert.Equal(t, 1, recommendation.DeployableNodesCount)
+	assert.Equal(t, 1, recommendation.CompatibleNodesCount)
+	assert.Equal(t, "5.15.0", recommendation.MaximalKernelVersion)
+	assert.False(t, recommendation.IsLegacyKernel)
+	assert.Equal(t, helm.AGENT_KERNEL_5_11_PRESET_PATH, recommendation.Presets[0].Path)
+	assert.Equal(t, helm.BACKEND_LOW_RESOURCES_PATH, recommendation.Presets[1].Path)
+	assert.Equal(t, "agent.sensor", recommendation.Components[0].Component)
+	assert.Equal(t, "1Gi", recommendation.Components[0].Requests.Memory().String())
+	assert.Equal(t, "100m", recommendation.Components[0].Requests.Cpu().String())
+}
+
+func TestGetResourcesPresetsLowResources(t *testing.T) {
+	viper.Set(LOW_RESOURCES_FLAG, true)
+	defer viper.Set(LOW_RESOURCES_FLAG, false)
+
+	presets := getResourcesPresets(&helm.AllocatableResources{}, semver.Version{})
+
+	assert.Equal(t, []string{helm.AGENT_LOW_RESOURCES_PATH, helm.BACKEND_LOW_RESOURCES_PATH}, getPresetsPaths(presets))
+	assert.Equal(t, LOW_RESOURCES_PRESET_REASON, presets[0].Reason)
+}
+
+func TestGenerateRecommendationSizesDeployableNodes(t *testing.T) {
+	cpu := resource.MustParse("4")
+	memory := resource.MustParse("16Gi")
+	nodesReport := k8s.DefaultNodeRequirements.GenerateNodeReport([]*k8s.NodeSummary{
+		{Name: "node", CPU: &cpu, Memory: &memory, Kernel: "5.15.0", Architecture: "amd64", OperatingSystem: "linux"},
+		{Name: "excluded", CPU: &cpu, Memory: &memory, Kernel: "5.15.0", Architecture: "amd64", OperatingSystem: "linux"},
+	})
+
+	groundcoverChart := &helm.Chart{
+		Chart: &chart.Chart{Metadata: &chart.Metadata{Name: "groundcover", Version: "1.2.3"}},
+	}
+
+	recommendation, err := generateRecommendation(groundcoverChart, nodesReport, nodesReport.CompatibleNodes[:1], map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, recommendation.DeployableNodesCount)
+	assert.Equal(t, 2, recommendation.CompatibleNodesCount)
+	assert.Equal(t, 1, recommendation.AllocatableResources.NodeCount)
+	assert.Equal(t, "4", recommendation.AllocatableResources.TotalCpu.String())
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"groundcover.com/pkg/segment"
+	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/ui"
+)
+
+const (
+	HELM_ROLLBACK_EVENT_NAME = "helm_rollback"
+	REVISION_OPTION_FORMAT   = "%d (chart: %s, updated: %s, status: %s)"
+)
+
+func init() {
+	RootCmd.AddCommand(RollbackCmd)
+}
+
+var RollbackCmd = &cobra.Command{
+	Use:     "rollback [revision]",
+	Short:   "Rollback groundcover to a previous release revision",
+	Example: "groundcover rollback 3",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+		namespace := viper.GetString(NAMESPACE_FLAG)
+		kubeconfig := viper.GetString(KUBECONFIG_FLAG)
+		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
+		releaseName := viper.GetString(HELM_RELEASE_FLAG)
+
+		sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
+		sentryKubeContext.SetOnCurrentScope()
+
+		var kubeClient *k8s.Client
+		if kubeClient, err = k8s.NewKubeClient(kubeconfig, kubecontext); err != nil {
+			return err
+		}
+
+		var clusterName string
+		if clusterName, err = getClusterName(kubeClient); err != nil {
+			return err
+		}
+
+		var helmClient *helm.Client
+		if helmClient, err = helm.NewHelmClient(namespace, kubecontext); err != nil {
+			return err
+		}
+
+		sentryHelmContext := sentry_utils.NewHelmContext(releaseName, CHART_NAME, HELM_REPO_URL)
+		sentryHelmContext.SetOnCurrentScope()
+
+		var releases []*helm.Release
+		if releases, err = helmClient.History(releaseName); err != nil {
+			return err
+		}
+
+		printReleaseHistory(releases)
+
+		var targetRelease *helm.Release
+		if targetRelease, err = selectRollbackRevision(releases, args); err != nil {
+			return err
+		}
+
+		currentRelease := releases[len(releases)-1]
+		promptMessage := fmt.Sprintf(
+			"Rollback groundcover (cluster: %s, namespace: %s) from revision %d (version: %s) to revision %d (version: %s)?",
+			clusterName, namespace, currentRelease.Revision(), currentRelease.Version(), targetRelease.Revision(), targetRelease.Version(),
+		)
+
+		if !ui.GlobalWriter.YesNoPrompt(promptMessage, false) {
+			return ErrExecutionAborted
+		}
+
+		sentryHelmContext.ChartVersion = targetRelease.Version().String()
+		sentryHelmContext.PreviousChartVersion = currentRelease.Version().String()
+		sentryHelmContext.SetOnCurrentScope()
+		sentry_utils.SetTagOnCurrentScope(sentry_utils.CHART_VERSION_TAG, sentryHelmContext.ChartVersion)
+
+		if err = rollbackHelmRelease(helmClient, releaseName, currentRelease, targetRelease); err != nil {
+			return err
+		}
+
+		var deployableNodesCount int
+		if deployableNodesCount, err = getReleaseDeployableNodesCount(ctx, kubeClient, targetRelease.Config, sentryKubeContext); err != nil {
+			return err
+		}
+
+		var rolledBackRelease *helm.Release
+		if rolledBackRelease, err = helmClient.GetCurrentRelease(releaseName); err != nil {
+			return err
+		}
+
+		agentEnabled := getAgentComponentsConfiguration(rolledBackRelease.Config, false)
+
+		// the cluster was registered by the original deployment, so registration is not validated again
+		return validateInstall(ctx, kubeClient, rolledBackRelease, "", "", clusterName, deployableNodesCount, false, agentEnabled, sentryHelmContext)
+	},
+}
+
+func selectRollbackRevision(releases []*helm.Release, args []string) (*helm.Release, error) {
+	if len(releases) < 2 {
+		return nil, errors.New("no previous revision to rollback to")
+	}
+
+	// previous revisions, latest first, so the default selection is the previous revision
+	candidates := make([]*helm.Release, 0, len(releases)-1)
+	for index := len(releases) - 2; index >= 0; index-- {
+		candidates = append(candidates, releases[index])
+	}
+
+	if len(args) > 0 {
+		revision, err := strconv.Atoi(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid revision %q", args[0])
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Revision() == revision {
+				return candidate, nil
+			}
+		}
+
+		return nil, fmt.Errorf("revision %d is not a previous revision of the release", revision)
+	}
+
+	if ui.IsNonInteractive() {
+		return nil, fmt.Errorf("%w, the revision argument is required", ui.ErrNonInteractivePrompt)
+	}
+
+	options := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		options = append(options, formatRevisionOption(candidate))
+	}
+
+	selected := ui.GlobalWriter.SelectPrompt("Select revision to rollback to:", options)
+	for index, option := range options {
+		if option == selected {
+			return candidates[index], nil
+		}
+	}
+
+	return nil, ErrExecutionAborted
+}
+
+func formatRevisionOption(release *helm.Release) string {
+	return fmt.Sprintf(REVISION_OPTION_FORMAT, release.Revision(), release.Version(), release.Info.LastDeployed.Format(time.RFC3339), release.Info.Status)
+}
+
+func rollbackHelmRelease(helmClient *helm.Client, releaseName string, currentRelease, targetRelease *helm.Release) error {
+	var err error
+
+	event := segment.NewEvent(HELM_ROLLBACK_EVENT_NAME)
+	event.
+		Set("chartVersion", targetRelease.Version()).
+		Set("previousChartVersion", currentRelease.Version()).
+		Set("revision", targetRelease.Revision())
+	event.Start()
+	defer func() {
+		event.StatusByError(err)
+	}()
+
+	spinner := ui.GlobalWriter.NewSpinner(fmt.Sprintf("Rolling back groundcover helm release to revision %d", targetRelease.Revision()))
+	spinner.Start()
+	spinner.SetStopMessage(fmt.Sprintf("groundcover helm release is rolled back to revision %d", targetRelease.Revision()))
+	spinner.SetStopFailMessage("groundcover helm release rollback failed")
+	defer spinner.WriteStop()
+
+	if err = helmClient.Rollback(releaseName, targetRelease.Revision()); err != nil {
+		spinner.WriteStopFail()
+		return err
+	}
+
+	return nil
+}
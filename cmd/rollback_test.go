@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestSelectRollbackRevision(t *testing.T) {
+	releases := []*helm.Release{
+		{Release: &release.Release{Version: 1}},
+		{Release: &release.Release{Version: 2}},
+		{Release: &release.Release{Version: 3}},
+	}
+
+	selected, err := selectRollbackRevision(releases, []string{"1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, selected.Revision())
+
+	_, err = selectRollbackRevision(releases, []string{"3"})
+	assert.EqualError(t, err, "revision 3 is not a previous revision of the release")
+
+	_, err = selectRollbackRevision(releases, []string{"latest"})
+	assert.EqualError(t, err, `invalid revision "latest"`)
+
+	_, err = selectRollbackRevision(releases[:1], nil)
+	assert.EqualError(t, err, "no previous revision to rollback to")
+}
@@ -35,6 +35,8 @@ const (
 	CLUSTER_NAME_FLAG     = "cluster-name"
 	SKIP_CLI_UPDATE_FLAG  = "skip-cli-update"
 	INSTALLATION_ID_FLAG  = "installation-id"
+	TENANT_NAME_FLAG      = "tenant-name"
+	BACKEND_FLAG          = "backend"
 	INVALID_TOKEN_MESSAGE = "Issue with authentication - try again to copy command line and rerun"
 )
 
@@ -47,18 +49,29 @@ var (
 func init() {
 	home := homedir.HomeDir()
 
-	RootCmd.PersistentFlags().String(API_KEY_FLAG, "", "optional api-key")
+	RootCmd.PersistentFlags().String(API_KEY_FLAG, "", "optional api-key (or GROUNDCOVER_API_KEY)")
 	viper.BindPFlag(API_KEY_FLAG, RootCmd.PersistentFlags().Lookup(API_KEY_FLAG))
+	viper.BindEnv(API_KEY_FLAG, ui.EnvName(API_KEY_FLAG))
 
 	RootCmd.PersistentFlags().String(TENANT_UUID_FLAG, "", "optional tenant-uuid")
 	viper.BindPFlag(TENANT_UUID_FLAG, RootCmd.PersistentFlags().Lookup(TENANT_UUID_FLAG))
 
-	RootCmd.PersistentFlags().String(TOKEN_FLAG, "", "optional login token")
+	RootCmd.PersistentFlags().String(TOKEN_FLAG, "", "optional login token (or GROUNDCOVER_TOKEN)")
 	viper.BindPFlag(TOKEN_FLAG, RootCmd.PersistentFlags().Lookup(TOKEN_FLAG))
+	viper.BindEnv(TOKEN_FLAG, ui.EnvName(TOKEN_FLAG))
 
 	RootCmd.PersistentFlags().Bool(ui.ASSUME_YES_FLAG, false, "assume yes on interactive prompts")
 	viper.BindPFlag(ui.ASSUME_YES_FLAG, RootCmd.PersistentFlags().Lookup(ui.ASSUME_YES_FLAG))
 
+	RootCmd.PersistentFlags().Bool(ui.NON_INTERACTIVE_FLAG, false, "never prompt, answers are taken from flags and missing ones fail the command (implies --yes and --skip-cli-update)")
+	viper.BindPFlag(ui.NON_INTERACTIVE_FLAG, RootCmd.PersistentFlags().Lookup(ui.NON_INTERACTIVE_FLAG))
+
+	RootCmd.PersistentFlags().String(TENANT_NAME_FLAG, "", "tenant to use when the user has access to several tenants")
+	viper.BindPFlag(TENANT_NAME_FLAG, RootCmd.PersistentFlags().Lookup(TENANT_NAME_FLAG))
+
+	RootCmd.PersistentFlags().String(BACKEND_FLAG, "", "backend to use when the tenant has several backends")
+	viper.BindPFlag(BACKEND_FLAG, RootCmd.PersistentFlags().Lookup(BACKEND_FLAG))
+
 	RootCmd.PersistentFlags().Bool(SKIP_CLI_UPDATE_FLAG, false, "disable automatic cli update check")
 	viper.BindPFlag(SKIP_CLI_UPDATE_FLAG, RootCmd.PersistentFlags().Lookup(SKIP_CLI_UPDATE_FLAG))
 
@@ -77,6 +90,12 @@ func init() {
 
 	RootCmd.PersistentFlags().String(HELM_RELEASE_FLAG, DEFAULT_GROUNDCOVER_RELEASE, "groundcover chart release name")
 	viper.BindPFlag(HELM_RELEASE_FLAG, RootCmd.PersistentFlags().Lookup(HELM_RELEASE_FLAG))
+
+	RootCmd.PersistentFlags().String(POLICY_FLAG, "", "path to a requirements policy file extending or overriding the default cluster and nodes requirements")
+	viper.BindPFlag(POLICY_FLAG, RootCmd.PersistentFlags().Lookup(POLICY_FLAG))
+
+	addTimeoutFlags(RootCmd, home)
+	addTelemetryFlags(RootCmd)
 }
 
 var (
@@ -84,6 +103,11 @@ var (
 		"help",
 		LoginCmd.Name(),
 		VersionCmd.Name(),
+		PreflightCmd.Name(),
+		RecommendCmd.Name(),
+		TuneCmd.Name(),
+		SupportBundleCmd.Name(),
+		LogsCmd.Name(),
 	}
 
 	ErrExecutionAborted        = errors.New("execution aborted")
@@ -109,6 +133,19 @@ groundcover, more data at: https://docs.groundcover.com/docs`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		var err error
 
+		if err = loadConfigFile(cmd); err != nil {
+			return err
+		}
+
+		if _, err = getOutputFormat(cmd); err != nil {
+			return err
+		}
+
+		if isMachineOutput(cmd) {
+			// keep stdout clean for the machine readable output
+			ui.GlobalWriter.SetOutput(os.Stderr)
+		}
+
 		segment.SetScope(cmd.Name())
 		sentry_utils.SetTransactionOnCurrentScope(cmd.Name())
 
@@ -119,7 +156,7 @@ groundcover, more data at: https://docs.groundcover.com/docs`,
 			return err
 		}
 
-		if !viper.GetBool(SKIP_CLI_UPDATE_FLAG) {
+		if shouldCheckCliUpdate() {
 			return checkAndUpgradeVersion(cmd.Context())
 		}
 
@@ -127,6 +164,11 @@ groundcover, more data at: https://docs.groundcover.com/docs`,
 	},
 }
 
+// shouldCheckCliUpdate skips the update in non-interactive mode, where it would be applied unattended and abort the command
+func shouldCheckCliUpdate() bool {
+	return !viper.GetBool(SKIP_CLI_UPDATE_FLAG) && !ui.IsNonInteractive()
+}
+
 func checkAndUpgradeVersion(ctx context.Context) error {
 	if shouldUpdate, selfUpdater := checkLatestVersionUpdate(ctx); shouldUpdate {
 		if err := selfUpdater.Apply(ctx); err != nil {
@@ -192,6 +234,11 @@ func validateAuthentication(cmd *cobra.Command, args []string) error {
 	if isAuthenicationRequired {
 		event.Set("authType", "auth0")
 		if token, err = auth.LoadAuth0Token(); err != nil {
+			if ui.IsNonInteractive() {
+				err = fmt.Errorf("%w, authentication is required: run \"groundcover login\" beforehand or use the --%s flag", ui.ErrNonInteractivePrompt, TOKEN_FLAG)
+				return err
+			}
+
 			if ui.GlobalWriter.YesNoPrompt("authentication is required, do you want to login?", true) {
 				return runLoginCmd(cmd, args)
 			}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/ui"
+)
+
+func TestShouldCheckCliUpdateSkippedNonInteractive(t *testing.T) {
+	viper.Set(ui.NON_INTERACTIVE_FLAG, true)
+	defer viper.Set(ui.NON_INTERACTIVE_FLAG, false)
+
+	assert.False(t, shouldCheckCliUpdate())
+}
+
+func TestShouldCheckCliUpdateInteractive(t *testing.T) {
+	assert.True(t, shouldCheckCliUpdate())
+
+	viper.Set(SKIP_CLI_UPDATE_FLAG, true)
+	defer viper.Set(SKIP_CLI_UPDATE_FLAG, false)
+
+	assert.False(t, shouldCheckCliUpdate())
+}
+
+func TestPhaseRetriesFlag(t *testing.T) {
+	retriesFlag := RootCmd.PersistentFlags().Lookup(PvcPollingPolicy.RetriesKey())
+	assert.NotNil(t, retriesFlag)
+
+	assert.NoError(t, retriesFlag.Value.Set("120"))
+	defer retriesFlag.Value.Set("0")
+
+	assert.Equal(t, 120, PvcPollingPolicy.Resolve().Retries)
+	assert.Nil(t, RootCmd.PersistentFlags().Lookup(WorkloadsReadinessPolicy.RetriesKey()))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -20,9 +22,7 @@ import (
 )
 
 const (
-	PORTAL_POLLING_INTERVAL = time.Second * 15
-	PORTAL_POLLING_RETRIES  = 28
-	PORTAL_POLLING_TIMEOUT  = time.Minute * 7
+	WORKLOADS_READINESS_TIMEOUT = time.Minute * 10
 
 	PVC_POLLING_INTERVAL = time.Second * 15
 	PVC_POLLING_RETRIES  = 40
@@ -32,23 +32,97 @@ const (
 	SENSORS_POLLING_RETRIES  = 28
 	SENSORS_POLLING_TIMEOUT  = time.Minute * 7
 
+	NODES_FLAG = "nodes"
+
 	SENSOR_LABEL_SELECTOR  = "app=sensor"
 	BACKEND_LABEL_SELECTOR = "app!=sensor"
-	PORTAL_LABEL_SELECTOR  = "app=portal"
 	RUNNING_FIELD_SELECTOR = "status.phase=Running"
 
-	WAIT_FOR_PORTAL_FORMAT      = "Waiting until cluster establish connectivity"
+	AGENT_COMPONENT   = "agent"
+	BACKEND_COMPONENT = "backend"
+
 	WAIT_FOR_PVCS_FORMAT        = "Waiting until all PVCs are bound (%d/%d PVCs)"
 	WAIT_FOR_SENSORS_FORMAT     = "Waiting until all nodes are monitored (%d/%d Nodes)"
+	WAIT_FOR_WORKLOADS_FORMAT   = "Waiting until all workloads are ready (%d/%d workloads)"
+	WORKLOADS_ROLLED_OUT_FORMAT = "Workloads are rolled out (%d/%d workloads ready)"
 	TIMEOUT_INSTALLATION_FORMAT = "Installation takes longer than expected, you can check the status using \"kubectl get pods -n %s\""
+	TIMEOUT_OVERRIDE_FORMAT     = "Use --%s or --%s to wait longer"
+	DIAGNOSIS_FORMAT            = "%s/%s %s (x%d): %s"
+	MORE_DIAGNOSES_FORMAT       = "%d more warnings, run \"groundcover support-bundle\" to collect all the namespace events"
+
+	DIAGNOSES_PRINT_LIMIT = 10
 
-	PVCS_VALIDATION_EVENT_NAME   = "pvcs_validation"
-	AGENTS_VALIDATION_EVENT_NAME = "agents_validation"
-	PORTAL_VALIDATION_EVENT_NAME = "portal_validation"
+	PVCS_VALIDATION_EVENT_NAME      = "pvcs_validation"
+	AGENTS_VALIDATION_EVENT_NAME    = "agents_validation"
+	WORKLOADS_VALIDATION_EVENT_NAME = "workloads_validation"
+)
+
+var (
+	PvcPollingPolicy = ui.PollingPolicy{
+		Phase:    "pvc",
+		Interval: PVC_POLLING_INTERVAL,
+		Timeout:  PVC_POLLING_TIMEOUT,
+		Retries:  PVC_POLLING_RETRIES,
+	}
+	SensorsPollingPolicy = ui.PollingPolicy{
+		Phase:    "sensors",
+		Interval: SENSORS_POLLING_INTERVAL,
+		Timeout:  SENSORS_POLLING_TIMEOUT,
+		Retries:  SENSORS_POLLING_RETRIES,
+	}
+	WorkloadsReadinessPolicy = ui.PollingPolicy{
+		Phase:   "workloads",
+		Timeout: WORKLOADS_READINESS_TIMEOUT,
+	}
 )
 
 func init() {
 	RootCmd.AddCommand(StatusCmd)
+
+	addOutputFlag(StatusCmd)
+	StatusCmd.Flags().Bool(NODES_FLAG, false, "list the nodes and whether a sensor is running on each of them")
+}
+
+type StatusReport struct {
+	ReleaseName         string                              `json:"releaseName"`
+	Namespace           string                              `json:"namespace"`
+	ChartVersion        string                              `json:"chartVersion"`
+	AppVersion          string                              `json:"appVersion"`
+	LatestChartVersion  string                              `json:"latestChartVersion"`
+	IsOutOfDate         bool                                `json:"isOutOfDate"`
+	Sensors             SensorsCoverage                     `json:"sensors"`
+	Nodes               []*k8s.NodeCoverage                 `json:"nodes,omitempty"`
+	Components          map[string]map[string]k8s.PodStatus `json:"components"`
+	Pvcs                []PvcStatus                         `json:"pvcs"`
+	ClusterRequirements map[string]RequirementStatus        `json:"clusterRequirements"`
+	Diagnoses           []*k8s.Diagnosis                    `json:"diagnoses,omitempty"`
+}
+
+type SensorsCoverage struct {
+	Running int `json:"running"`
+	Nodes   int `json:"nodes"`
+}
+
+type PvcStatus struct {
+	Name         string `json:"name"`
+	Phase        string `json:"phase"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+type RequirementStatus struct {
+	IsCompatible    bool     `json:"isCompatible"`
+	IsNonCompatible bool     `json:"isNonCompatible"`
+	Message         string   `json:"message"`
+	ErrorMessages   []string `json:"errorMessages,omitempty"`
+}
+
+func NewRequirementStatus(requirement k8s.Requirement) RequirementStatus {
+	return RequirementStatus{
+		IsCompatible:    requirement.IsCompatible,
+		IsNonCompatible: requirement.IsNonCompatible,
+		Message:         requirement.Message,
+		ErrorMessages:   requirement.ErrorMessages,
+	}
 }
 
 var StatusCmd = &cobra.Command{
@@ -63,6 +137,16 @@ var StatusCmd = &cobra.Command{
 		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
 		releaseName := viper.GetString(HELM_RELEASE_FLAG)
 
+		var outputFormat string
+		if outputFormat, err = getOutputFormat(cmd); err != nil {
+			return err
+		}
+
+		var showNodes bool
+		if showNodes, err = cmd.Flags().GetBool(NODES_FLAG); err != nil {
+			return err
+		}
+
 		sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
 		sentryKubeContext.SetOnCurrentScope()
 
@@ -80,7 +164,12 @@ var StatusCmd = &cobra.Command{
 			return err
 		}
 
-		clusterReport := k8s.DefaultClusterRequirements.Validate(ctx, kubeClient, clusterSummary)
+		var clusterRequirements *k8s.ClusterRequirements
+		if clusterRequirements, err = getClusterRequirements(); err != nil {
+			return err
+		}
+
+		clusterReport := clusterRequirements.Validate(ctx, kubeClient, clusterSummary)
 
 		sentryKubeContext.ClusterReport = clusterReport
 		sentryKubeContext.SetOnCurrentScope()
@@ -107,75 +196,246 @@ var StatusCmd = &cobra.Command{
 			return err
 		}
 
-		if chart.Version().GT(release.Version()) {
-			ui.GlobalWriter.Printf("Current groundcover installation in your cluster version: %s is out of date!, The latest version is %s.", release.Version(), chart.Version())
+		var nodesSummaries []*k8s.NodeSummary
+		if nodesSummaries, err = kubeClient.GetNodesSummaries(ctx); err != nil {
+			return err
 		}
 
-		var nodeList *v1.NodeList
-		if nodeList, err = kubeClient.CoreV1().Nodes().List(cmd.Context(), metav1.ListOptions{}); err != nil {
+		// only nodes the agent can be scheduled on are expected to run a sensor
+		var nodeRequirements *k8s.NodeMinimumRequirements
+		if nodeRequirements, err = getNodeRequirements(); err != nil {
 			return err
 		}
-		nodesCount := len(nodeList.Items)
 
-		if err = waitForSensors(ctx, kubeClient, namespace, chart.AppVersion(), nodesCount, sentryHelmContext); err != nil {
+		var nodesCount int
+		nodesReport := nodeRequirements.GenerateNodeReport(nodesSummaries)
+		if nodesCount, err = countReleaseDeployableNodes(nodesReport, release.Config); err != nil {
 			return err
 		}
 
-		return nil
+		var nodesCoverage []*k8s.NodeCoverage
+		if showNodes {
+			if nodesCoverage, err = getNodesCoverage(ctx, kubeClient, namespace, nodesReport, release.Config); err != nil {
+				return err
+			}
+		}
+
+		if outputFormat != "" {
+			var statusReport *StatusReport
+			if statusReport, err = generateStatusReport(ctx, kubeClient, release, chart, clusterReport, namespace, nodesCount); err != nil {
+				return err
+			}
+
+			statusReport.Nodes = nodesCoverage
+			return printOutput(outputFormat, statusReport)
+		}
+
+		if showNodes {
+			printNodesCoverage(nodesCoverage)
+			return nil
+		}
+
+		if chart.Version().GT(release.Version()) {
+			ui.GlobalWriter.Printf("Current groundcover installation in your cluster version: %s is out of date!, The latest version is %s.", release.Version(), chart.Version())
+		}
+
+		err = waitForSensors(ctx, kubeClient, namespace, chart.AppVersion(), nodesCount, sentryHelmContext)
+		reportNamespaceDiagnoses(ctx, kubeClient, namespace, release.LastDeployed())
+
+		return err
 	},
 }
 
-func waitForPortal(ctx context.Context, kubeClient *k8s.Client, namespace, appVersion string, sentryHelmContext *sentry_utils.HelmContext) error {
+func generateStatusReport(ctx context.Context, kubeClient *k8s.Client, release *helm.Release, chart *helm.Chart, clusterReport *k8s.ClusterReport, namespace string, nodesCount int) (*StatusReport, error) {
 	var err error
 
-	event := segment.NewEvent(PORTAL_VALIDATION_EVENT_NAME)
+	statusReport := &StatusReport{
+		ReleaseName:        release.Name,
+		Namespace:          namespace,
+		ChartVersion:       release.Version().String(),
+		AppVersion:         release.Chart.AppVersion(),
+		LatestChartVersion: chart.Version().String(),
+		IsOutOfDate:        chart.Version().GT(release.Version()),
+		Sensors:            SensorsCoverage{Nodes: nodesCount},
+		Components:         make(map[string]map[string]k8s.PodStatus),
+		Pvcs:               make([]PvcStatus, 0),
+		ClusterRequirements: map[string]RequirementStatus{
+			"clusterType":        NewRequirementStatus(clusterReport.ClusterTypeAllowed),
+			"cliAuthSupported":   NewRequirementStatus(clusterReport.CliAuthSupported),
+			"serverVersion":      NewRequirementStatus(clusterReport.ServerVersionAllowed),
+			"userAuthorized":     NewRequirementStatus(clusterReport.UserAuthorized),
+			"storageProvisional": NewRequirementStatus(clusterReport.StroageProvisional),
+		},
+	}
+
+	if clusterReport.StorageProvisionerAllowed != nil {
+		statusReport.ClusterRequirements["storageProvisioner"] = NewRequirementStatus(*clusterReport.StorageProvisionerAllowed)
+	}
+
+	if statusReport.Sensors.Running, err = getRunningSensors(ctx, kubeClient, release.Chart.AppVersion(), namespace); err != nil {
+		return nil, err
+	}
+
+	if statusReport.Components[AGENT_COMPONENT], err = listPodsStatuses(ctx, kubeClient, namespace, metav1.ListOptions{LabelSelector: SENSOR_LABEL_SELECTOR}); err != nil {
+		return nil, err
+	}
+
+	if statusReport.Components[BACKEND_COMPONENT], err = listPodsStatuses(ctx, kubeClient, namespace, metav1.ListOptions{LabelSelector: BACKEND_LABEL_SELECTOR}); err != nil {
+		return nil, err
+	}
+
+	listOptions := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app.kubernetes.io/instance=%s", release.Name),
+	}
+
+	var pvcList *v1.PersistentVolumeClaimList
+	if pvcList, err = kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(ctx, listOptions); err != nil {
+		return nil, err
+	}
+
+	for _, pvc := range pvcList.Items {
+		pvcStatus := PvcStatus{
+			Name:  pvc.Name,
+			Phase: string(pvc.Status.Phase),
+		}
+
+		if pvc.Spec.StorageClassName != nil {
+			pvcStatus.StorageClass = *pvc.Spec.StorageClassName
+		}
+
+		statusReport.Pvcs = append(statusReport.Pvcs, pvcStatus)
+	}
+
+	// diagnoses only explain the status, so they are left empty when the events or pods can't be listed
+	statusReport.Diagnoses, _ = getNamespaceDiagnoses(ctx, kubeClient, namespace, release.LastDeployed())
+
+	return statusReport, nil
+}
+
+// waitForWorkloads watches the rollout of every workload in the release manifest, reporting the progress and the first failure reason
+// of workloads which aren't ready. It returns as soon as a container is failing, and doesn't wait for the sensors, which are validated by waitForSensors
+func waitForWorkloads(ctx context.Context, kubeClient *k8s.Client, release *helm.Release, agentEnabled bool) error {
+	var err error
+
+	event := segment.NewEvent(WORKLOADS_VALIDATION_EVENT_NAME)
 	event.Start()
 	defer func() {
 		event.StatusByError(err)
 	}()
 
-	spinner := ui.GlobalWriter.NewSpinner(WAIT_FOR_PORTAL_FORMAT)
-	spinner.SetStopMessage("Cluster established connectivity")
-	spinner.SetStopFailMessage(fmt.Sprintf(TIMEOUT_INSTALLATION_FORMAT, namespace))
+	var workloads []k8s.Workload
+	if workloads, err = release.Workloads(); err != nil {
+		return err
+	}
+
+	spinner := ui.GlobalWriter.NewSpinner(fmt.Sprintf(WAIT_FOR_WORKLOADS_FORMAT, 0, len(workloads)))
+	spinner.SetStopFailMessage(fmt.Sprintf(TIMEOUT_INSTALLATION_FORMAT, release.Namespace))
 
 	spinner.Start()
 	defer spinner.WriteStop()
 
-	isPortalRunningFunc := func() error {
-		podClient := kubeClient.CoreV1().Pods(namespace)
-		listOptions := metav1.ListOptions{
-			LabelSelector: PORTAL_LABEL_SELECTOR,
-			FieldSelector: RUNNING_FIELD_SELECTOR,
+	var statuses []*k8s.WorkloadStatus
+	var lastMessage string
+	onChange := func(workloadsStatuses []*k8s.WorkloadStatus) {
+		statuses = workloadsStatuses
+
+		if message := workloadsProgressMessage(statuses); message != lastMessage {
+			lastMessage = message
+			spinner.WriteMessage(message)
 		}
+	}
 
-		podList, err := podClient.List(ctx, listOptions)
-		if err != nil {
-			return err
+	readinessCtx, cancel := context.WithTimeout(ctx, WorkloadsReadinessPolicy.Resolve().Timeout)
+	defer cancel()
+
+	err = kubeClient.WatchWorkloadsReadiness(readinessCtx, release.Namespace, workloads, onChange)
+
+	failureReason := k8s.FirstFailureReason(statuses)
+	readySensors, desiredSensors := countDaemonSetsReadiness(statuses)
+	event.
+		Set("workloadsCount", len(workloads)).
+		Set("readyWorkloadsCount", countReadyWorkloads(statuses)).
+		Set("failureReason", failureReason)
+
+	if agentEnabled {
+		event.
+			Set("sensorsCount", desiredSensors).
+			Set("runningSensorsCount", readySensors)
+	}
+
+	if err == nil {
+		spinner.SetStopMessage(fmt.Sprintf(WORKLOADS_ROLLED_OUT_FORMAT, countReadyWorkloads(statuses), len(statuses)))
+		return nil
+	}
+
+	spinner.WriteStopFail()
+	printWorkloadsStatuses(statuses)
+
+	if !errors.Is(err, k8s.ErrWorkloadFailed) && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	// a failing workload won't become ready by waiting longer
+	if failureReason != "" {
+		return fmt.Errorf("workloads are not ready, first failure: %s", failureReason)
+	}
+
+	ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(TIMEOUT_OVERRIDE_FORMAT, WorkloadsReadinessPolicy.TimeoutKey(), ui.TIMEOUT_FLAG))
+	return ErrExecutionPartialSuccess
+}
+
+func workloadsProgressMessage(statuses []*k8s.WorkloadStatus) string {
+	message := fmt.Sprintf(WAIT_FOR_WORKLOADS_FORMAT, countReadyWorkloads(statuses), len(statuses))
+
+	for _, status := range statuses {
+		if status.IsReady {
+			continue
 		}
 
-		for _, pod := range podList.Items {
-			if pod.Annotations["groundcover_version"] == appVersion {
-				return nil
-			}
+		message = fmt.Sprintf("%s, %s", message, status)
+		if status.FailureReason != "" {
+			message = fmt.Sprintf("%s: %s", message, status.FailureReason)
 		}
 
-		err = errors.New("portal pod is not running")
-		return ui.RetryableError(err)
+		break
 	}
 
-	err = spinner.Poll(ctx, isPortalRunningFunc, PORTAL_POLLING_INTERVAL, PORTAL_POLLING_TIMEOUT, PORTAL_POLLING_RETRIES)
+	return message
+}
+
+func printWorkloadsStatuses(statuses []*k8s.WorkloadStatus) {
+	for _, status := range statuses {
+		switch {
+		case status.IsReady:
+			ui.GlobalWriter.PrintSuccessMessageln(status.String())
+		case status.FailureReason != "":
+			ui.GlobalWriter.PrintErrorMessageln(fmt.Sprintf("%s: %s", status, status.FailureReason))
+		default:
+			ui.GlobalWriter.PrintWarningMessageln(status.String())
+		}
+	}
+}
 
-	if err == nil {
-		return nil
+func countReadyWorkloads(statuses []*k8s.WorkloadStatus) int {
+	readyCount := 0
+	for _, status := range statuses {
+		if status.IsReady {
+			readyCount++
+		}
 	}
 
-	defer spinner.WriteStopFail()
+	return readyCount
+}
 
-	if errors.Is(err, ui.ErrSpinnerTimeout) {
-		return ErrExecutionPartialSuccess
+func countDaemonSetsReadiness(statuses []*k8s.WorkloadStatus) (ready int32, desired int32) {
+	for _, status := range statuses {
+		if status.Kind == k8s.DAEMONSET_KIND {
+			ready += status.Ready
+			desired += status.Desired
+		}
 	}
 
-	return err
+	return ready, desired
 }
 
 func waitForSensors(ctx context.Context, kubeClient *k8s.Client, namespace, appVersion string, expectedSensorsCount int, sentryHelmContext *sentry_utils.HelmContext) error {
@@ -213,7 +473,7 @@ func waitForSensors(ctx context.Context, kubeClient *k8s.Client, namespace, appV
 		return ui.RetryableError(err)
 	}
 
-	err = spinner.Poll(ctx, isSensorRunningFunc, SENSORS_POLLING_INTERVAL, SENSORS_POLLING_TIMEOUT, SENSORS_POLLING_RETRIES)
+	err = spinner.PollWithPolicy(ctx, isSensorRunningFunc, SensorsPollingPolicy)
 
 	runningSensorsStr := fmt.Sprintf("%d/%d", runningSensors, expectedSensorsCount)
 	sentryHelmContext.RunningSensors = runningSensorsStr
@@ -232,6 +492,10 @@ func waitForSensors(ctx context.Context, kubeClient *k8s.Client, namespace, appV
 	defer spinner.WriteStopFail()
 
 	if errors.Is(err, ui.ErrSpinnerTimeout) {
+		if failureReason := getSensorsFailureReason(ctx, kubeClient, namespace); failureReason != "" {
+			return fmt.Errorf("not all expected sensors are running, first failure: %s", failureReason)
+		}
+
 		if runningSensors > 0 {
 			spinner.SetWarningSign()
 			spinner.SetStopFailMessage(fmt.Sprintf("groundcover managed to provision %d/%d nodes", runningSensors, expectedSensorsCount))
@@ -243,6 +507,22 @@ func waitForSensors(ctx context.Context, kubeClient *k8s.Client, namespace, appV
 	return err
 }
 
+// getSensorsFailureReason returns the failure reason of the first failing sensor pod, it is best effort as the pods are listed after the timeout
+func getSensorsFailureReason(ctx context.Context, kubeClient *k8s.Client, namespace string) string {
+	podList, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: SENSOR_LABEL_SELECTOR})
+	if err != nil {
+		return ""
+	}
+
+	for index := range podList.Items {
+		if failureReason := k8s.PodFailureReason(&podList.Items[index]); failureReason != "" {
+			return failureReason
+		}
+	}
+
+	return ""
+}
+
 func getRunningSensors(ctx context.Context, kubeClient *k8s.Client, appVersion string, namespace string) (int, error) {
 	podClient := kubeClient.CoreV1().Pods(namespace)
 	listOptions := metav1.ListOptions{
@@ -266,6 +546,42 @@ func getRunningSensors(ctx context.Context, kubeClient *k8s.Client, appVersion s
 	return runningSensors, nil
 }
 
+func getNodesCoverage(ctx context.Context, kubeClient *k8s.Client, namespace string, nodesReport *k8s.NodesReport, chartValues map[string]interface{}) ([]*k8s.NodeCoverage, error) {
+	var err error
+
+	var placement *k8s.AgentPlacement
+	if placement, err = getAgentPlacement(chartValues); err != nil {
+		return nil, err
+	}
+
+	var podList *v1.PodList
+	if podList, err = kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: SENSOR_LABEL_SELECTOR}); err != nil {
+		return nil, err
+	}
+
+	return k8s.NodesCoverage(nodesReport, placement, podList.Items)
+}
+
+func printNodesCoverage(nodesCoverage []*k8s.NodeCoverage) {
+	var coverageBuffer strings.Builder
+
+	runningSensors := 0
+	tableWriter := tabwriter.NewWriter(&coverageBuffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "NODE\tSENSOR\tPOD\tREASON")
+
+	for _, coverage := range nodesCoverage {
+		if coverage.Sensor == k8s.SENSOR_RUNNING {
+			runningSensors++
+		}
+
+		fmt.Fprintf(tableWriter, "%s\t%s\t%s\t%s\n", coverage.Node, coverage.Sensor, coverage.Pod, coverage.Reason)
+	}
+	tableWriter.Flush()
+
+	ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("groundcover sensors coverage (%d/%d Nodes):", runningSensors, len(nodesCoverage)))
+	ui.GlobalWriter.Printf("%s", coverageBuffer.String())
+}
+
 func reportPodsStatus(ctx context.Context, kubeClient *k8s.Client, namespace string, sentryHelmContext *sentry_utils.HelmContext) {
 	backendPodStatus, err := listPodsStatuses(ctx, kubeClient, namespace, metav1.ListOptions{LabelSelector: BACKEND_LABEL_SELECTOR})
 	if err != nil {
@@ -282,6 +598,49 @@ func reportPodsStatus(ctx context.Context, kubeClient *k8s.Client, namespace str
 	sentryHelmContext.SetOnCurrentScope()
 }
 
+// getNamespaceDiagnoses diagnoses the namespace warnings last seen since the given time, a zero time diagnoses all of them
+func getNamespaceDiagnoses(ctx context.Context, kubeClient *k8s.Client, namespace string, since time.Time) ([]*k8s.Diagnosis, error) {
+	var err error
+
+	var events []v1.Event
+	if events, err = kubeClient.GetNamespaceEvents(ctx, namespace); err != nil {
+		return nil, err
+	}
+
+	var podList *v1.PodList
+	if podList, err = kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		return nil, err
+	}
+
+	return k8s.DiagnoseNamespace(events, podList.Items, since), nil
+}
+
+// reportNamespaceDiagnoses prints the namespace warnings with their remediations, it is best effort as it only explains a failure
+func reportNamespaceDiagnoses(ctx context.Context, kubeClient *k8s.Client, namespace string, since time.Time) {
+	diagnoses, err := getNamespaceDiagnoses(ctx, kubeClient, namespace, since)
+	if err != nil || len(diagnoses) == 0 {
+		return
+	}
+
+	printNamespaceDiagnoses(diagnoses)
+}
+
+func printNamespaceDiagnoses(diagnoses []*k8s.Diagnosis) {
+	ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("Namespace warnings (%d):", len(diagnoses)))
+
+	for index, diagnosis := range diagnoses {
+		if index == DIAGNOSES_PRINT_LIMIT {
+			ui.GlobalWriter.Println(fmt.Sprintf(MORE_DIAGNOSES_FORMAT, len(diagnoses)-DIAGNOSES_PRINT_LIMIT))
+			break
+		}
+
+		ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(DIAGNOSIS_FORMAT, diagnosis.Kind, diagnosis.Name, diagnosis.Reason, diagnosis.Count, diagnosis.Message))
+		if diagnosis.Remediation != "" {
+			ui.GlobalWriter.Println(fmt.Sprintf("   %s %s", ui.Bullet, diagnosis.Remediation))
+		}
+	}
+}
+
 func listPodsStatuses(ctx context.Context, kubeClient *k8s.Client, namespace string, options metav1.ListOptions) (map[string]k8s.PodStatus, error) {
 	podList, err := kubeClient.CoreV1().Pods(namespace).List(ctx, options)
 	if err != nil {
@@ -355,7 +714,7 @@ func waitForPvcs(ctx context.Context, kubeClient *k8s.Client, releaseName, names
 		return ui.RetryableError(err)
 	}
 
-	err = spinner.Poll(ctx, isPvcsReadyFunc, PVC_POLLING_INTERVAL, PVC_POLLING_TIMEOUT, PVC_POLLING_RETRIES)
+	err = spinner.PollWithPolicy(ctx, isPvcsReadyFunc, PvcPollingPolicy)
 
 	sentryHelmContext.BoundPvcs = maps.Keys(boundPvcs)
 	sentryHelmContext.SetOnCurrentScope()
@@ -370,7 +729,7 @@ func waitForPvcs(ctx context.Context, kubeClient *k8s.Client, releaseName, names
 	spinner.WriteStopFail()
 
 	if errors.Is(err, ui.ErrSpinnerTimeout) {
-		err = errors.New("timeout waiting for persistent volume claims to be ready")
+		err = fmt.Errorf("timeout waiting for persistent volume claims to be ready. "+TIMEOUT_OVERRIDE_FORMAT, PvcPollingPolicy.TimeoutKey(), ui.TIMEOUT_FLAG)
 		return err
 	}
 
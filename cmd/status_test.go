@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetSensorsFailureReason(t *testing.T) {
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "portal-abc", Namespace: "groundcover", Labels: map[string]string{"app": "portal"}},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "portal", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-abc", Namespace: "groundcover", Labels: map[string]string{"app": "sensor"}},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "sensor", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				},
+			},
+		},
+	}
+
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(pods[0], pods[1])}
+
+	assert.Equal(t, "pod sensor-abc container sensor: ImagePullBackOff", getSensorsFailureReason(context.Background(), kubeClient, "groundcover"))
+	assert.Equal(t, "", getSensorsFailureReason(context.Background(), kubeClient, "other"))
+}
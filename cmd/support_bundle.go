@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+	"groundcover.com/pkg/bundle"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"groundcover.com/pkg/segment"
+	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/telemetry"
+	"groundcover.com/pkg/ui"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	OUTPUT_DIR_FLAG = "output-dir"
+	SINCE_FLAG      = "since"
+	TAIL_FLAG       = "tail"
+
+	DEFAULT_LOGS_SINCE = time.Hour
+	DEFAULT_LOGS_TAIL  = 1000
+
+	SUPPORT_BUNDLE_NAME_FORMAT = "groundcover-support-bundle-%s"
+	SUPPORT_BUNDLE_TIME_FORMAT = "20060102-150405"
+	CONTAINER_LOG_PATH_FORMAT  = "logs/%s/%s.log"
+	PREVIOUS_LOG_PATH_FORMAT   = "logs/%s/%s.previous.log"
+	SUPPORT_BUNDLE_EVENT_NAME  = "support_bundle"
+)
+
+func init() {
+	RootCmd.AddCommand(SupportBundleCmd)
+
+	SupportBundleCmd.Flags().String(OUTPUT_DIR_FLAG, ".", "directory to write the support bundle archive to")
+	SupportBundleCmd.Flags().Duration(SINCE_FLAG, DEFAULT_LOGS_SINCE, "only collect logs newer than this duration (0 collects all logs)")
+	SupportBundleCmd.Flags().Int64(TAIL_FLAG, DEFAULT_LOGS_TAIL, "maximal number of log lines collected from each container (0 collects all lines)")
+}
+
+type ReleaseRevision struct {
+	Revision     int       `json:"revision"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion"`
+	Status       string    `json:"status"`
+	Updated      time.Time `json:"updated"`
+	Description  string    `json:"description"`
+}
+
+type bundleCollector struct {
+	name    string
+	collect func() error
+}
+
+var SupportBundleCmd = &cobra.Command{
+	Use:     "support-bundle",
+	Short:   "Collect groundcover diagnostics into a single archive",
+	Example: "groundcover support-bundle --since 2h --output-dir /tmp",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+		namespace := viper.GetString(NAMESPACE_FLAG)
+		kubeconfig := viper.GetString(KUBECONFIG_FLAG)
+		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
+		releaseName := viper.GetString(HELM_RELEASE_FLAG)
+
+		var outputDir string
+		if outputDir, err = cmd.Flags().GetString(OUTPUT_DIR_FLAG); err != nil {
+			return err
+		}
+
+		var logOptions *v1.PodLogOptions
+		if logOptions, err = getBundleLogOptions(cmd); err != nil {
+			return err
+		}
+
+		event := segment.NewEvent(SUPPORT_BUNDLE_EVENT_NAME)
+		event.Start()
+		defer func() {
+			event.StatusByError(err)
+		}()
+
+		sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
+		sentryKubeContext.SetOnCurrentScope()
+
+		var kubeClient *k8s.Client
+		if kubeClient, err = k8s.NewKubeClient(kubeconfig, kubecontext); err != nil {
+			return err
+		}
+
+		bundleName := fmt.Sprintf(SUPPORT_BUNDLE_NAME_FORMAT, time.Now().UTC().Format(SUPPORT_BUNDLE_TIME_FORMAT))
+		bundlePath := filepath.Join(outputDir, bundleName+".tar.gz")
+
+		var bundleFile *os.File
+		if bundleFile, err = os.OpenFile(bundlePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, bundle.FILE_MODE); err != nil {
+			return err
+		}
+		defer bundleFile.Close()
+
+		writer := bundle.NewWriter(bundleFile, bundleName)
+
+		collectors := []bundleCollector{
+			{name: "cluster report", collect: func() error { return collectClusterReport(ctx, writer, kubeClient, namespace) }},
+			{name: "nodes report", collect: func() error { return collectNodesReport(ctx, writer, kubeClient) }},
+			{name: "pods statuses", collect: func() error { return collectPodsStatuses(ctx, writer, kubeClient, namespace) }},
+			{name: "pods logs", collect: func() error { return collectPodsLogs(ctx, writer, kubeClient, namespace, logOptions) }},
+			{name: "namespace events", collect: func() error { return collectEvents(ctx, writer, kubeClient, namespace) }},
+			{name: "namespace diagnoses", collect: func() error { return collectDiagnoses(ctx, writer, kubeClient, namespace) }},
+			{name: "pvcs", collect: func() error { return collectPvcs(ctx, writer, kubeClient, namespace) }},
+			{name: "helm release", collect: func() error { return collectHelmRelease(writer, namespace, kubecontext, releaseName) }},
+		}
+
+		ui.GlobalWriter.PrintlnWithPrefixln("Collecting groundcover diagnostics:")
+
+		var collectionErrors []string
+		for _, collector := range collectors {
+			if collectErr := collector.collect(); collectErr != nil {
+				collectionErrors = append(collectionErrors, fmt.Sprintf("%s: %s", collector.name, collectErr))
+				ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf("Failed to collect %s: %s", collector.name, collectErr))
+				continue
+			}
+
+			ui.GlobalWriter.PrintSuccessMessageln(fmt.Sprintf("Collected %s", collector.name))
+		}
+
+		if len(collectionErrors) > 0 {
+			if err = writer.AddFile("errors.txt", []byte(strings.Join(collectionErrors, "\n"))); err != nil {
+				return err
+			}
+		}
+
+		// the transcript is written last, so it includes the collection messages
+		if err = addRedactedLog(writer, "cli.log", []byte(ui.GlobalWriter.Dump())); err != nil {
+			return err
+		}
+
+		if err = writer.Close(); err != nil {
+			return err
+		}
+
+		event.
+			Set("filesCount", len(writer.Files())).
+			Set("errorsCount", len(collectionErrors))
+
+		ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("Support bundle written to %s", bundlePath))
+
+		return nil
+	},
+}
+
+func getBundleLogOptions(cmd *cobra.Command) (*v1.PodLogOptions, error) {
+	var err error
+
+	var since time.Duration
+	if since, err = cmd.Flags().GetDuration(SINCE_FLAG); err != nil {
+		return nil, err
+	}
+
+	var tail int64
+	if tail, err = cmd.Flags().GetInt64(TAIL_FLAG); err != nil {
+		return nil, err
+	}
+
+	// timestamps allow correlating the logs of different pods with the events
+	logOptions := newPodLogOptions(since, tail)
+	logOptions.Timestamps = true
+
+	return logOptions, nil
+}
+
+// newPodLogOptions returns log options limited to the logs newer than since and to the last tail lines, zero values don't limit the logs
+func newPodLogOptions(since time.Duration, tail int64) *v1.PodLogOptions {
+	logOptions := &v1.PodLogOptions{}
+
+	if since > 0 {
+		sinceSeconds := int64(since.Seconds())
+		logOptions.SinceSeconds = &sinceSeconds
+	}
+
+	if tail > 0 {
+		logOptions.TailLines = &tail
+	}
+
+	return logOptions
+}
+
+func collectClusterReport(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client, namespace string) error {
+	var err error
+
+	var clusterSummary *k8s.ClusterSummary
+	if clusterSummary, err = kubeClient.GetClusterSummary(ctx, namespace, viper.GetString(STORAGE_CLASS_FLAG)); err != nil {
+		return err
+	}
+
+	var clusterRequirements *k8s.ClusterRequirements
+	if clusterRequirements, err = getClusterRequirements(); err != nil {
+		return err
+	}
+
+	return writer.AddJSON("cluster-report.json", clusterRequirements.Validate(ctx, kubeClient, clusterSummary))
+}
+
+func collectNodesReport(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client) error {
+	var err error
+
+	var nodesSummaries []*k8s.NodeSummary
+	if nodesSummaries, err = kubeClient.GetNodesSummaries(ctx); err != nil {
+		return err
+	}
+
+	var nodeRequirements *k8s.NodeMinimumRequirements
+	if nodeRequirements, err = getNodeRequirements(); err != nil {
+		return err
+	}
+
+	return writer.AddJSON("nodes-report.json", nodeRequirements.GenerateNodeReport(nodesSummaries))
+}
+
+func collectPodsStatuses(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client, namespace string) error {
+	var err error
+
+	podsStatuses := make(map[string]map[string]k8s.PodStatus)
+
+	if podsStatuses[AGENT_COMPONENT], err = listPodsStatuses(ctx, kubeClient, namespace, metav1.ListOptions{LabelSelector: SENSOR_LABEL_SELECTOR}); err != nil {
+		return err
+	}
+
+	if podsStatuses[BACKEND_COMPONENT], err = listPodsStatuses(ctx, kubeClient, namespace, metav1.ListOptions{LabelSelector: BACKEND_LABEL_SELECTOR}); err != nil {
+		return err
+	}
+
+	return writer.AddJSON("pods-statuses.json", podsStatuses)
+}
+
+// collectPodsLogs writes the logs of every container of the namespace pods, and the previous logs of restarted containers.
+// A container whose logs can't be read is skipped, the failures are returned together
+func collectPodsLogs(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client, namespace string, logOptions *v1.PodLogOptions) error {
+	var err error
+
+	var podList *v1.PodList
+	if podList, err = kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		return err
+	}
+
+	restartCounts := make(map[string]int32)
+	for _, pod := range podList.Items {
+		for _, containerStatus := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			restartCounts[pod.Name+"/"+containerStatus.Name] = containerStatus.RestartCount
+		}
+	}
+
+	var failures []string
+	for _, pod := range podList.Items {
+		for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			containerLogOptions := logOptions.DeepCopy()
+			containerLogOptions.Container = container.Name
+
+			if err = collectContainerLogs(ctx, writer, kubeClient, namespace, pod.Name, fmt.Sprintf(CONTAINER_LOG_PATH_FORMAT, pod.Name, container.Name), containerLogOptions); err != nil {
+				failures = append(failures, fmt.Sprintf("%s/%s: %s", pod.Name, container.Name, err))
+				continue
+			}
+
+			if restartCounts[pod.Name+"/"+container.Name] == 0 {
+				continue
+			}
+
+			containerLogOptions.Previous = true
+			if err = collectContainerLogs(ctx, writer, kubeClient, namespace, pod.Name, fmt.Sprintf(PREVIOUS_LOG_PATH_FORMAT, pod.Name, container.Name), containerLogOptions); err != nil {
+				failures = append(failures, fmt.Sprintf("%s/%s previous: %s", pod.Name, container.Name, err))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to read %d containers logs: %s", len(failures), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+func collectContainerLogs(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client, namespace, podName, path string, logOptions *v1.PodLogOptions) error {
+	var err error
+
+	var logs []byte
+	if logs, err = kubeClient.GetPodLogs(ctx, namespace, podName, logOptions); err != nil {
+		return err
+	}
+
+	return addRedactedLog(writer, path, logs)
+}
+
+// addRedactedLog writes the logs with their credentials replaced by the telemetry text redaction rules
+func addRedactedLog(writer *bundle.Writer, path string, logs []byte) error {
+	return writer.AddFile(path, []byte(telemetry.RedactText(string(logs))))
+}
+
+func collectEvents(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client, namespace string) error {
+	var err error
+
+	var eventList *v1.EventList
+	if eventList, err = kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		return err
+	}
+
+	events := eventList.Items
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(&events[j].LastTimestamp)
+	})
+
+	return writer.AddJSON("events.json", events)
+}
+
+func collectDiagnoses(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client, namespace string) error {
+	var err error
+
+	var diagnoses []*k8s.Diagnosis
+	// the bundle investigates failures of any revision, so no warning is stale
+	if diagnoses, err = getNamespaceDiagnoses(ctx, kubeClient, namespace, time.Time{}); err != nil {
+		return err
+	}
+
+	return writer.AddJSON("diagnoses.json", diagnoses)
+}
+
+func collectPvcs(ctx context.Context, writer *bundle.Writer, kubeClient *k8s.Client, namespace string) error {
+	var err error
+
+	var pvcList *v1.PersistentVolumeClaimList
+	if pvcList, err = kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		return err
+	}
+
+	pvcs := make([]PvcStatus, 0, len(pvcList.Items))
+	for _, pvc := range pvcList.Items {
+		pvcStatus := PvcStatus{
+			Name:  pvc.Name,
+			Phase: string(pvc.Status.Phase),
+		}
+
+		if pvc.Spec.StorageClassName != nil {
+			pvcStatus.StorageClass = *pvc.Spec.StorageClassName
+		}
+
+		pvcs = append(pvcs, pvcStatus)
+	}
+
+	return writer.AddJSON("pvcs.json", pvcs)
+}
+
+func collectHelmRelease(writer *bundle.Writer, namespace, kubecontext, releaseName string) error {
+	var err error
+
+	var helmClient *helm.Client
+	if helmClient, err = helm.NewHelmClient(namespace, kubecontext); err != nil {
+		return err
+	}
+
+	var releases []*helm.Release
+	if releases, err = helmClient.History(releaseName); err != nil {
+		return err
+	}
+
+	if err = writer.AddJSON("helm-history.json", releaseRevisions(releases)); err != nil {
+		return err
+	}
+
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return collectReleaseValues(writer, releases[len(releases)-1])
+}
+
+func releaseRevisions(releases []*helm.Release) []ReleaseRevision {
+	revisions := make([]ReleaseRevision, 0, len(releases))
+	for _, release := range releases {
+		revisions = append(revisions, ReleaseRevision{
+			Revision:     release.Revision(),
+			ChartVersion: release.Version().String(),
+			AppVersion:   release.Chart.AppVersion(),
+			Status:       release.Info.Status.String(),
+			Updated:      release.Info.LastDeployed.Time,
+			Description:  release.Info.Description,
+		})
+	}
+
+	return revisions
+}
+
+// collectReleaseValues writes the release values with credentials, e.g. global.groundcover_token, redacted
+func collectReleaseValues(writer *bundle.Writer, release *helm.Release) error {
+	var err error
+
+	var redactedValues map[string]interface{}
+	if redactedValues, err = helm.RedactValues(release.Config); err != nil {
+		return err
+	}
+
+	var valuesData []byte
+	if valuesData, err = yaml.Marshal(redactedValues); err != nil {
+		return err
+	}
+
+	return writer.AddFile("values.yaml", valuesData)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/bundle"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func readBundle(t *testing.T, data []byte) map[string]string {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	files := make(map[string]string)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(tarReader)
+		assert.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+}
+
+func TestCollectPodsLogs(t *testing.T) {
+	kubeClient := &k8s.Client{
+		Interface: fake.NewSimpleClientset(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-1", Namespace: "groundcover"},
+			Spec: v1.PodSpec{
+				InitContainers: []v1.Container{{Name: "init"}},
+				Containers:     []v1.Container{{Name: "sensor"}},
+			},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{Name: "sensor", RestartCount: 2}},
+			},
+		}),
+	}
+
+	var buffer bytes.Buffer
+	writer := bundle.NewWriter(&buffer, "bundle")
+
+	err := collectPodsLogs(context.Background(), writer, kubeClient, "groundcover", &v1.PodLogOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	files := readBundle(t, buffer.Bytes())
+	assert.Equal(t, "fake logs", files["bundle/logs/sensor-1/init.log"])
+	assert.Equal(t, "fake logs", files["bundle/logs/sensor-1/sensor.log"])
+	assert.Equal(t, "fake logs", files["bundle/logs/sensor-1/sensor.previous.log"])
+	assert.NotContains(t, files, "bundle/logs/sensor-1/init.previous.log")
+}
+
+func TestCollectPvcs(t *testing.T) {
+	storageClass := "gp2"
+	kubeClient := &k8s.Client{
+		Interface: fake.NewSimpleClientset(&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "clickhouse", Namespace: "groundcover"},
+			Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		}),
+	}
+
+	var buffer bytes.Buffer
+	writer := bundle.NewWriter(&buffer, "bundle")
+
+	assert.NoError(t, collectPvcs(context.Background(), writer, kubeClient, "groundcover"))
+	assert.NoError(t, writer.Close())
+
+	files := readBundle(t, buffer.Bytes())
+	assert.JSONEq(t, `[{"name": "clickhouse", "phase": "Pending", "storageClass": "gp2"}]`, files["bundle/pvcs.json"])
+}
+
+func TestCollectReleaseValuesRedactsToken(t *testing.T) {
+	helmRelease := &helm.Release{
+		Release: &release.Release{
+			Config: map[string]interface{}{
+				"clusterId": "cluster",
+				"global":    map[string]interface{}{"groundcover_token": "secret-token"},
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	writer := bundle.NewWriter(&buffer, "bundle")
+
+	assert.NoError(t, collectReleaseValues(writer, helmRelease))
+	assert.NoError(t, writer.Close())
+
+	files := readBundle(t, buffer.Bytes())
+	assert.NotContains(t, files["bundle/values.yaml"], "secret-token")
+	assert.Contains(t, files["bundle/values.yaml"], helm.REDACTED_VALUE)
+	assert.Contains(t, files["bundle/values.yaml"], "clusterId: cluster")
+}
+
+func TestAddRedactedLogRedactsTokens(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := bundle.NewWriter(&buffer, "bundle")
+
+	logs := "connecting with Authorization: Bearer secret-token\napi_key=secret-key\nconnected\n"
+
+	assert.NoError(t, addRedactedLog(writer, "logs/sensor-1/sensor.log", []byte(logs)))
+	assert.NoError(t, writer.Close())
+
+	files := readBundle(t, buffer.Bytes())
+	assert.Equal(t, "connecting with Authorization: Bearer <redacted>\napi_key=<redacted>\nconnected\n", files["bundle/logs/sensor-1/sensor.log"])
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/ui"
+	"groundcover.com/pkg/utils"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	TELEMETRY_KEY        = "telemetry"
+	NO_TELEMETRY_FLAG    = "no-telemetry"
+	TELEMETRY_LOG_FLAG   = "telemetry-log"
+	REDACT_KEYS_FLAG     = "redact-keys"
+	REDACT_PATTERNS_FLAG = "redact-patterns"
+	HELP_FLAG            = "help"
+	HELP_FLAG_SHORTHAND  = "h"
+	TELEMETRY_FLAG_SET   = "telemetry"
+)
+
+var (
+	disabledTelemetryValues = []string{"off", "false", "0", "no", "disabled"}
+)
+
+// TelemetrySettings controls whether events are reported to sentry and segment, and where they are logged locally
+type TelemetrySettings struct {
+	IsEnabled      bool
+	LogPath        string
+	RedactKeys     []string
+	RedactPatterns []string
+}
+
+func addTelemetryFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool(NO_TELEMETRY_FLAG, false, fmt.Sprintf("disable sentry and segment telemetry (or set %s=off or %s: off in the config file)", ui.EnvName(TELEMETRY_KEY), TELEMETRY_KEY))
+	viper.BindPFlag(NO_TELEMETRY_FLAG, cmd.PersistentFlags().Lookup(NO_TELEMETRY_FLAG))
+
+	cmd.PersistentFlags().String(TELEMETRY_LOG_FLAG, "", "path to a file every telemetry event and context is written to, whether telemetry is enabled or not")
+	viper.BindPFlag(TELEMETRY_LOG_FLAG, cmd.PersistentFlags().Lookup(TELEMETRY_LOG_FLAG))
+
+	cmd.PersistentFlags().StringSlice(REDACT_KEYS_FLAG, nil, "dotted key paths redacted from the telemetry contexts, * matches any key (e.g. helm.ValuesOverride.*.host)")
+	cmd.PersistentFlags().StringArray(REDACT_PATTERNS_FLAG, nil, "regular expression whose matches are redacted from the telemetry contexts and captured logs, can be repeated")
+}
+
+// GetTelemetrySettings resolves the telemetry settings from the command line arguments, the environment and the config file.
+// It runs before the telemetry clients are initialized, which is before cobra parses the command line, so only the telemetry and config flags are parsed
+func GetTelemetrySettings(args []string) (*TelemetrySettings, error) {
+	var err error
+
+	flagSet := pflag.NewFlagSet(TELEMETRY_FLAG_SET, pflag.ContinueOnError)
+	flagSet.ParseErrorsWhitelist.UnknownFlags = true
+	flagSet.SetOutput(io.Discard)
+	flagSet.Usage = func() {}
+	flagSet.BoolP(HELP_FLAG, HELP_FLAG_SHORTHAND, false, "")
+	flagSet.Bool(NO_TELEMETRY_FLAG, false, "")
+	flagSet.String(TELEMETRY_LOG_FLAG, "", "")
+	flagSet.StringSlice(REDACT_KEYS_FLAG, nil, "")
+	flagSet.StringArray(REDACT_PATTERNS_FLAG, nil, "")
+	flagSet.String(CONFIG_FLAG, filepath.Join(homedir.HomeDir(), utils.STROAGE_PREFIX, CONFIG_FILE_NAME), "")
+
+	if err = flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := viper.New()
+	config.BindPFlag(NO_TELEMETRY_FLAG, flagSet.Lookup(NO_TELEMETRY_FLAG))
+	config.BindPFlag(TELEMETRY_LOG_FLAG, flagSet.Lookup(TELEMETRY_LOG_FLAG))
+	config.BindEnv(TELEMETRY_KEY, ui.EnvName(TELEMETRY_KEY))
+	config.BindEnv(TELEMETRY_LOG_FLAG, ui.EnvName(TELEMETRY_LOG_FLAG))
+
+	configPath, _ := flagSet.GetString(CONFIG_FLAG)
+	if err = readConfigFile(config, configPath, flagSet.Changed(CONFIG_FLAG)); err != nil {
+		return nil, err
+	}
+
+	telemetryValue := strings.ToLower(strings.TrimSpace(config.GetString(TELEMETRY_KEY)))
+
+	settings := &TelemetrySettings{
+		IsEnabled:      !config.GetBool(NO_TELEMETRY_FLAG) && !slices.Contains(disabledTelemetryValues, telemetryValue),
+		LogPath:        config.GetString(TELEMETRY_LOG_FLAG),
+		RedactKeys:     config.GetStringSlice(REDACT_KEYS_FLAG),
+		RedactPatterns: config.GetStringSlice(REDACT_PATTERNS_FLAG),
+	}
+
+	// patterns may contain commas, so the flags aren't bound to the config and are read as is
+	if flagSet.Changed(REDACT_KEYS_FLAG) {
+		settings.RedactKeys, _ = flagSet.GetStringSlice(REDACT_KEYS_FLAG)
+	}
+	if flagSet.Changed(REDACT_PATTERNS_FLAG) {
+		settings.RedactPatterns, _ = flagSet.GetStringArray(REDACT_PATTERNS_FLAG)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTelemetrySettingsDefault(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GROUNDCOVER_TELEMETRY", "")
+
+	settings, err := GetTelemetrySettings([]string{"deploy", "--yes", "-o", "json", "--help"})
+	assert.NoError(t, err)
+	assert.True(t, settings.IsEnabled)
+	assert.Empty(t, settings.LogPath)
+}
+
+func TestGetTelemetrySettingsFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GROUNDCOVER_TELEMETRY", "")
+
+	settings, err := GetTelemetrySettings([]string{"deploy", "--cluster-name", "prod", "--no-telemetry", "--telemetry-log=/tmp/telemetry.log"})
+	assert.NoError(t, err)
+	assert.False(t, settings.IsEnabled)
+	assert.Equal(t, "/tmp/telemetry.log", settings.LogPath)
+}
+
+func TestGetTelemetrySettingsEnv(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GROUNDCOVER_TELEMETRY", "OFF")
+	t.Setenv("GROUNDCOVER_TELEMETRY_LOG", "/tmp/telemetry.log")
+
+	settings, err := GetTelemetrySettings([]string{"status"})
+	assert.NoError(t, err)
+	assert.False(t, settings.IsEnabled)
+	assert.Equal(t, "/tmp/telemetry.log", settings.LogPath)
+}
+
+func TestGetTelemetrySettingsConfigFile(t *testing.T) {
+	t.Setenv("GROUNDCOVER_TELEMETRY", "")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(configPath, []byte("telemetry: off\n"), 0600))
+
+	settings, err := GetTelemetrySettings([]string{"status", "--config", configPath})
+	assert.NoError(t, err)
+	assert.False(t, settings.IsEnabled)
+}
+
+func TestGetTelemetrySettingsMissingExplicitConfigFile(t *testing.T) {
+	_, err := GetTelemetrySettings([]string{"status", "--config", filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.ErrorContains(t, err, "failed to read config file")
+}
+
+func TestGetTelemetrySettingsRedaction(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := "redact-keys:\n  - helm.ValuesOverride.global.ingress\nredact-patterns:\n  - '[a-z]+\\.internal'\n"
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0600))
+
+	settings, err := GetTelemetrySettings([]string{"deploy", "--config", configPath})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"helm.ValuesOverride.global.ingress"}, settings.RedactKeys)
+	assert.Equal(t, []string{`[a-z]+\.internal`}, settings.RedactPatterns)
+
+	settings, err = GetTelemetrySettings([]string{"deploy", "--config", configPath, "--redact-keys", "a.b,c.d", "--redact-patterns", "x{1,3}"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.b", "c.d"}, settings.RedactKeys)
+	assert.Equal(t, []string{"x{1,3}"}, settings.RedactPatterns)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"groundcover.com/pkg/api"
+	"groundcover.com/pkg/auth"
+	"groundcover.com/pkg/selfupdate"
+	"groundcover.com/pkg/ui"
+	"groundcover.com/pkg/utils"
+)
+
+const (
+	CONFIG_FLAG      = "config"
+	CONFIG_FILE_NAME = "config.yaml"
+)
+
+// pollingPolicies are all the phases the cli waits on, each one gets a --<phase>-timeout flag, and polled phases a --<phase>-retries flag
+func pollingPolicies() []ui.PollingPolicy {
+	return []ui.PollingPolicy{
+		GetChartPollingPolicy,
+		HelmDeployPollingPolicy,
+		PvcPollingPolicy,
+		WorkloadsReadinessPolicy,
+		SensorsPollingPolicy,
+		KernelProbePollingPolicy,
+		api.ClusterPollingPolicy,
+		auth.DeviceCodePollingPolicy,
+		selfupdate.ApplyPollingPolicy,
+	}
+}
+
+func addTimeoutFlags(cmd *cobra.Command, homeDir string) {
+	cmd.PersistentFlags().String(CONFIG_FLAG, filepath.Join(homeDir, utils.STROAGE_PREFIX, CONFIG_FILE_NAME), "path to the cli config file")
+	viper.BindPFlag(CONFIG_FLAG, cmd.PersistentFlags().Lookup(CONFIG_FLAG))
+
+	cmd.PersistentFlags().Duration(ui.TIMEOUT_FLAG, 0, "override the timeout of every waiting phase (e.g. 30m)")
+	viper.BindPFlag(ui.TIMEOUT_FLAG, cmd.PersistentFlags().Lookup(ui.TIMEOUT_FLAG))
+
+	policies := pollingPolicies()
+	for _, policy := range policies {
+		usage := fmt.Sprintf("override the %s phase timeout (default %s)", policy.Phase, policy.Timeout)
+		cmd.PersistentFlags().Duration(policy.TimeoutKey(), 0, usage)
+		viper.BindPFlag(policy.TimeoutKey(), cmd.PersistentFlags().Lookup(policy.TimeoutKey()))
+
+		if !policy.IsPolled() {
+			continue
+		}
+
+		usage = fmt.Sprintf("override the %s phase retries (default %d, scaled with an overridden timeout)", policy.Phase, policy.Retries)
+		cmd.PersistentFlags().Int(policy.RetriesKey(), 0, usage)
+		viper.BindPFlag(policy.RetriesKey(), cmd.PersistentFlags().Lookup(policy.RetriesKey()))
+	}
+
+	ui.BindPollingPolicyEnv(policies...)
+}
+
+// loadConfigFile reads the optional cli config file, a missing file is only an error when it was set explicitly
+func loadConfigFile(cmd *cobra.Command) error {
+	return readConfigFile(viper.GetViper(), viper.GetString(CONFIG_FLAG), cmd.Flags().Changed(CONFIG_FLAG))
+}
+
+func readConfigFile(config *viper.Viper, path string, isExplicit bool) error {
+	config.SetConfigFile(path)
+
+	err := config.ReadInConfig()
+	if err == nil || (errors.Is(err, fs.ErrNotExist) && !isExplicit) {
+		return nil
+	}
+
+	return fmt.Errorf("failed to read config file: %w", err)
+}
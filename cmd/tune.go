@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"groundcover.com/pkg/segment"
+	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/ui"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	HEADROOM_FLAG        = "headroom"
+	SAMPLE_DURATION_FLAG = "sample-duration"
+	SAMPLE_INTERVAL_FLAG = "sample-interval"
+	TUNE_OUTPUT_FLAG     = "output-file"
+	TUNE_APPLY_FLAG      = "apply"
+
+	DEFAULT_HEADROOM        = 0.3
+	DEFAULT_SAMPLE_DURATION = time.Hour
+	DEFAULT_SAMPLE_INTERVAL = 15 * time.Second
+
+	USAGE_SAMPLING_EVENT_NAME = "usage_sampling"
+	TUNE_UPGRADE_EVENT_NAME   = "tune_upgrade"
+)
+
+func init() {
+	RootCmd.AddCommand(TuneCmd)
+
+	addOutputFlag(TuneCmd)
+	TuneCmd.Flags().Float64(HEADROOM_FLAG, DEFAULT_HEADROOM, "fraction of the peak usage added on top of it to the recommended requests")
+	TuneCmd.Flags().Duration(SAMPLE_DURATION_FLAG, DEFAULT_SAMPLE_DURATION, "how long to sample the pods usage")
+	TuneCmd.Flags().Duration(SAMPLE_INTERVAL_FLAG, DEFAULT_SAMPLE_INTERVAL, "interval between pods usage samples")
+	TuneCmd.Flags().String(TUNE_OUTPUT_FLAG, "", "write the recommended resources values override to a file")
+	TuneCmd.Flags().Bool(TUNE_APPLY_FLAG, false, "upgrade the release with the recommended resources")
+}
+
+type TuneReport struct {
+	ChartVersion        string                  `json:"chartVersion"`
+	Headroom            float64                 `json:"headroom"`
+	SamplesCount        int                     `json:"samplesCount"`
+	Components          []*helm.ComponentSizing `json:"components"`
+	UnmatchedContainers []*k8s.ContainerUsage   `json:"unmatchedContainers,omitempty"`
+	Values              map[string]interface{}  `json:"values"`
+}
+
+var TuneCmd = &cobra.Command{
+	Use:     "tune",
+	Short:   "Right-size groundcover components resources from their live usage",
+	Example: "groundcover tune --sample-duration 24h --sample-interval 1m --output-file tuned-values.yaml",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
+		ctx := cmd.Context()
+		namespace := viper.GetString(NAMESPACE_FLAG)
+		kubeconfig := viper.GetString(KUBECONFIG_FLAG)
+		kubecontext := viper.GetString(KUBECONTEXT_FLAG)
+		releaseName := viper.GetString(HELM_RELEASE_FLAG)
+
+		var outputFormat string
+		if outputFormat, err = getOutputFormat(cmd); err != nil {
+			return err
+		}
+
+		var headroom float64
+		if headroom, err = cmd.Flags().GetFloat64(HEADROOM_FLAG); err != nil {
+			return err
+		}
+
+		if headroom < 0 {
+			return fmt.Errorf("--%s must not be negative", HEADROOM_FLAG)
+		}
+
+		var sampleDuration, sampleInterval time.Duration
+		if sampleDuration, err = cmd.Flags().GetDuration(SAMPLE_DURATION_FLAG); err != nil {
+			return err
+		}
+
+		if sampleInterval, err = cmd.Flags().GetDuration(SAMPLE_INTERVAL_FLAG); err != nil {
+			return err
+		}
+
+		if sampleInterval <= 0 {
+			return fmt.Errorf("--%s must be positive", SAMPLE_INTERVAL_FLAG)
+		}
+
+		var outputFile string
+		if outputFile, err = cmd.Flags().GetString(TUNE_OUTPUT_FLAG); err != nil {
+			return err
+		}
+
+		var apply bool
+		if apply, err = cmd.Flags().GetBool(TUNE_APPLY_FLAG); err != nil {
+			return err
+		}
+
+		sentryKubeContext := sentry_utils.NewKubeContext(kubeconfig, kubecontext)
+		sentryKubeContext.SetOnCurrentScope()
+
+		var kubeClient *k8s.Client
+		if kubeClient, err = k8s.NewKubeClient(kubeconfig, kubecontext); err != nil {
+			return err
+		}
+
+		var helmClient *helm.Client
+		if helmClient, err = helm.NewHelmClient(namespace, kubecontext); err != nil {
+			return err
+		}
+
+		sentryHelmContext := sentry_utils.NewHelmContext(releaseName, CHART_NAME, HELM_REPO_URL)
+		sentryHelmContext.SetOnCurrentScope()
+
+		var release *helm.Release
+		if release, err = helmClient.GetCurrentRelease(releaseName); err != nil {
+			return err
+		}
+
+		sentryHelmContext.ChartVersion = release.Version().String()
+		sentryHelmContext.SetOnCurrentScope()
+
+		var workloads []k8s.Workload
+		if workloads, err = release.Workloads(); err != nil {
+			return err
+		}
+
+		var containersUsage []*k8s.ContainerUsage
+		if containersUsage, err = kubeClient.GetWorkloadsContainers(ctx, namespace, workloads); err != nil {
+			return err
+		}
+
+		var samplesCount int
+		if samplesCount, err = sampleContainersUsage(ctx, kubeClient, namespace, containersUsage, sampleDuration, sampleInterval); err != nil {
+			return err
+		}
+
+		var report *TuneReport
+		if report, err = generateTuneReport(release, containersUsage, headroom); err != nil {
+			return err
+		}
+		report.SamplesCount = samplesCount
+
+		if outputFormat != "" {
+			if err = printOutput(outputFormat, report); err != nil {
+				return err
+			}
+		} else {
+			printTuneReport(report)
+		}
+
+		if len(report.Components) == 0 {
+			return errors.New("no component usage was sampled, make sure the metrics server is serving the groundcover pods metrics")
+		}
+
+		var valuesData []byte
+		if valuesData, err = yaml.Marshal(report.Values); err != nil {
+			return err
+		}
+
+		if outputFile != "" {
+			if err = os.WriteFile(outputFile, valuesData, 0644); err != nil {
+				return err
+			}
+			ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf("Recommended values override written to %s, deploy it with: groundcover deploy -f %s", outputFile, outputFile))
+		} else if !apply && outputFormat == "" {
+			ui.GlobalWriter.PrintlnWithPrefixln("Recommended values override:")
+			ui.GlobalWriter.Printf("%s", string(valuesData))
+		}
+
+		if !apply {
+			return nil
+		}
+
+		promptMessage := fmt.Sprintf("Upgrade groundcover (namespace: %s) with the recommended resources?", namespace)
+		if !ui.GlobalWriter.YesNoPrompt(promptMessage, false) {
+			return ErrExecutionAborted
+		}
+
+		return applyTunedValues(ctx, kubeClient, helmClient, release, generateReleaseDescription(cmd, nil), report.Values, sentryKubeContext, sentryHelmContext)
+	},
+}
+
+// sampleContainersUsage records the pods usage every interval for the given duration, returning the number of samples taken
+func sampleContainersUsage(ctx context.Context, kubeClient *k8s.Client, namespace string, containersUsage []*k8s.ContainerUsage, duration, interval time.Duration) (int, error) {
+	var err error
+	var samplesCount int
+
+	event := segment.NewEvent(USAGE_SAMPLING_EVENT_NAME)
+	event.Set("duration", duration.String())
+	event.Start()
+	defer func() {
+		event.Set("samplesCount", samplesCount)
+		event.StatusByError(err)
+	}()
+
+	spinner := ui.GlobalWriter.NewSpinner(fmt.Sprintf("Sampling groundcover pods usage for %s", duration))
+	spinner.Start()
+	spinner.SetStopMessage("groundcover pods usage sampled")
+	spinner.SetStopFailMessage("groundcover pods usage sampling failed")
+	defer spinner.WriteStop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(duration)
+	for {
+		var podsMetrics []*k8s.PodMetrics
+		if podsMetrics, err = kubeClient.GetPodsMetrics(ctx, namespace); err != nil {
+			spinner.WriteStopFail()
+			return samplesCount, err
+		}
+
+		var podList *v1.PodList
+		if podList, err = kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+			spinner.WriteStopFail()
+			return samplesCount, err
+		}
+
+		k8s.RecordPodsUsage(containersUsage, podList.Items, podsMetrics)
+		samplesCount++
+
+		if !time.Now().Add(interval).Before(deadline) {
+			return samplesCount, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			spinner.WriteStopFail()
+			return samplesCount, err
+		case <-ticker.C:
+		}
+	}
+}
+
+// generateTuneReport sizes the release components resources to the sampled usage of the containers rendered from them
+func generateTuneReport(release *helm.Release, containersUsage []*k8s.ContainerUsage, headroom float64) (*TuneReport, error) {
+	var err error
+
+	chart := &helm.Chart{Chart: release.Chart}
+
+	var components []*helm.ComponentResources
+	if components, err = chart.ComponentsResources(release.Config); err != nil {
+		return nil, err
+	}
+
+	// the chart is rendered with a marker in each component resources, so containers are matched to the component they are rendered from
+	var matchingValues map[string]interface{}
+	if matchingValues, err = helm.ComponentsMatchingValues(release.Config, components); err != nil {
+		return nil, err
+	}
+
+	var matchingManifest string
+	if matchingManifest, err = chart.RenderManifest(release.Name, release.Namespace, matchingValues); err != nil {
+		return nil, err
+	}
+
+	var componentsContainers map[helm.ComponentContainer]*helm.ComponentResources
+	if componentsContainers, err = helm.MatchComponentsContainers(matchingManifest, components); err != nil {
+		return nil, err
+	}
+
+	sizings, unmatchedContainers := helm.SizeComponents(componentsContainers, containersUsage, headroom)
+
+	report := &TuneReport{
+		ChartVersion:        release.Version().String(),
+		Headroom:            headroom,
+		Components:          sizings,
+		UnmatchedContainers: unmatchedContainers,
+		Values:              helm.ComponentsSizingValues(sizings),
+	}
+
+	return report, nil
+}
+
+func printTuneReport(report *TuneReport) {
+	var componentsBuffer strings.Builder
+	tableWriter := tabwriter.NewWriter(&componentsBuffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "COMPONENT\tCPU USAGE\tCPU REQUEST\tMEMORY USAGE\tMEMORY REQUEST\tMEMORY LIMIT")
+	for _, sizing := range report.Components {
+		fmt.Fprintf(
+			tableWriter, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			sizing.Component,
+			resourceValue(sizing.Usage, v1.ResourceCPU),
+			resourceChange(sizing.Current.Requests, sizing.Recommended.Requests, v1.ResourceCPU),
+			resourceValue(sizing.Usage, v1.ResourceMemory),
+			resourceChange(sizing.Current.Requests, sizing.Recommended.Requests, v1.ResourceMemory),
+			resourceChange(sizing.Current.Limits, sizing.Recommended.Limits, v1.ResourceMemory),
+		)
+	}
+	tableWriter.Flush()
+
+	ui.GlobalWriter.PrintlnWithPrefixln(fmt.Sprintf(
+		"Components resources (chart %s, %d samples, %.0f%% headroom):",
+		report.ChartVersion, report.SamplesCount, report.Headroom*100,
+	))
+	ui.GlobalWriter.Printf("%s", componentsBuffer.String())
+
+	for _, containerUsage := range report.UnmatchedContainers {
+		ui.GlobalWriter.PrintWarningMessageln(fmt.Sprintf(
+			"%s/%s container %s isn't rendered from any chart component resources, it is not tuned",
+			containerUsage.Workload.Kind, containerUsage.Workload.Name, containerUsage.Container,
+		))
+	}
+}
+
+func resourceChange(current, recommended v1.ResourceList, name v1.ResourceName) string {
+	currentValue := resourceValue(current, name)
+	recommendedValue := resourceValue(recommended, name)
+
+	if currentValue == recommendedValue {
+		return currentValue
+	}
+
+	return fmt.Sprintf("%s -> %s", currentValue, recommendedValue)
+}
+
+func applyTunedValues(ctx context.Context, kubeClient *k8s.Client, helmClient *helm.Client, release *helm.Release, description string, valuesOverride map[string]interface{}, sentryKubeContext *sentry_utils.KubeContext, sentryHelmContext *sentry_utils.HelmContext) error {
+	var err error
+
+	var chartValues map[string]interface{}
+	if chartValues, err = helm.MergeValuesOverride(release.Config, valuesOverride); err != nil {
+		return err
+	}
+
+	if err = upgradeTunedRelease(ctx, helmClient, release, description, chartValues); err != nil {
+		return err
+	}
+
+	var deployableNodesCount int
+	if deployableNodesCount, err = getReleaseDeployableNodesCount(ctx, kubeClient, chartValues, sentryKubeContext); err != nil {
+		return err
+	}
+
+	var tunedRelease *helm.Release
+	if tunedRelease, err = helmClient.GetCurrentRelease(release.Name); err != nil {
+		return err
+	}
+
+	agentEnabled := getAgentComponentsConfiguration(tunedRelease.Config, false)
+
+	// the cluster was registered by the original deployment, so registration is not validated again
+	return validateInstall(ctx, kubeClient, tunedRelease, "", "", "", deployableNodesCount, false, agentEnabled, sentryHelmContext)
+}
+
+func upgradeTunedRelease(ctx context.Context, helmClient *helm.Client, release *helm.Release, description string, chartValues map[string]interface{}) error {
+	var err error
+
+	event := segment.NewEvent(TUNE_UPGRADE_EVENT_NAME)
+	event.Set("chartVersion", release.Version())
+	event.Start()
+	defer func() {
+		event.StatusByError(err)
+	}()
+
+	spinner := ui.GlobalWriter.NewSpinner("Upgrading groundcover helm release with the recommended resources")
+	spinner.Start()
+	spinner.SetStopMessage("groundcover helm release is upgraded")
+	spinner.SetStopFailMessage("groundcover helm release upgrade failed")
+	defer spinner.WriteStop()
+
+	helmUpgradeFunc := func() error {
+		if _, err = helmClient.Upgrade(ctx, release.Name, description, &helm.Chart{Chart: release.Chart}, chartValues); err != nil {
+			return ui.RetryableError(err)
+		}
+
+		return nil
+	}
+
+	if err = spinner.PollWithPolicy(ctx, helmUpgradeFunc, HelmDeployPollingPolicy); err != nil {
+		spinner.WriteStopFail()
+		return err
+	}
+
+	return nil
+}
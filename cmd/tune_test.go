@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGenerateTuneReport(t *testing.T) {
+	helmRelease := &helm.Release{
+		Release: &release.Release{
+			Chart: &chart.Chart{
+				Metadata: &chart.Metadata{Name: "groundcover", Version: "1.0.0", APIVersion: chart.APIVersionV2},
+				Templates: []*chart.File{
+					{
+						Name: "templates/portal.yaml",
+						Data: []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: portal\nspec:\n  template:\n    spec:\n      containers:\n        - name: portal\n          resources:\n            {{- toYaml .Values.portal.resources | nindent 12 }}\n"),
+					},
+				},
+				Values: map[string]interface{}{
+					"portal": map[string]interface{}{
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+						},
+					},
+				},
+			},
+			Name:      "groundcover",
+			Namespace: "groundcover",
+			Config:    map[string]interface{}{},
+		},
+	}
+
+	containersUsage := []*k8s.ContainerUsage{
+		{
+			Workload:  k8s.Workload{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"},
+			Container: "portal",
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			Usage:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m"), v1.ResourceMemory: resource.MustParse("300Mi")},
+			SamplesCount: 2,
+		},
+	}
+
+	report, err := generateTuneReport(helmRelease, containersUsage, 0.5)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1.0.0", report.ChartVersion)
+	assert.Len(t, report.Components, 1)
+	assert.Empty(t, report.UnmatchedContainers)
+
+	expected := map[string]interface{}{
+		"portal": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "300m", "memory": "450Mi"},
+			},
+		},
+	}
+	assert.Equal(t, expected, report.Values)
+}
+
+func TestResourceChange(t *testing.T) {
+	current := v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+	recommended := v1.ResourceList{v1.ResourceCPU: resource.MustParse("300m")}
+
+	assert.Equal(t, "1 -> 300m", resourceChange(current, recommended, v1.ResourceCPU))
+	assert.Equal(t, NO_RESOURCE_VALUE, resourceChange(current, recommended, v1.ResourceMemory))
+	assert.Equal(t, "1", resourceChange(current, current, v1.ResourceCPU))
+}
@@ -10,6 +10,7 @@ import (
 	"groundcover.com/cmd"
 	"groundcover.com/pkg/segment"
 	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/telemetry"
 	"groundcover.com/pkg/ui"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -18,6 +19,10 @@ import (
 const APP_NAME = "cli"
 
 func main() {
+	os.Exit(run())
+}
+
+func run() int {
 	var err error
 
 	klogFlagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
@@ -31,24 +36,54 @@ func main() {
 		environment = "dev"
 	}
 
+	var telemetrySettings *cmd.TelemetrySettings
+	if telemetrySettings, err = cmd.GetTelemetrySettings(os.Args[1:]); err != nil {
+		ui.GlobalWriter.PrintErrorMessageln(err.Error())
+		return 1
+	}
+
+	var redactor *telemetry.Redactor
+	if redactor, err = telemetry.NewRedactor(telemetrySettings.RedactKeys, telemetrySettings.RedactPatterns); err != nil {
+		ui.GlobalWriter.PrintErrorMessageln(err.Error())
+		return 1
+	}
+	telemetry.SetRedactor(redactor)
+
+	var telemetryLog *telemetry.Log
+	if telemetrySettings.LogPath != "" {
+		if telemetryLog, err = telemetry.OpenLog(telemetrySettings.LogPath); err != nil {
+			ui.GlobalWriter.PrintErrorMessageln(err.Error())
+			return 1
+		}
+		defer telemetryLog.Close()
+	}
+
 	sentryClientOptions := sentry_utils.GetSentryClientOptions(APP_NAME, environment, cmd.BinaryVersion)
+	sentryClientOptions = sentry_utils.WithTelemetry(sentryClientOptions, telemetrySettings.IsEnabled, telemetryLog)
 	if err = sentry.Init(sentryClientOptions); err != nil {
 		ui.GlobalWriter.PrintErrorMessageln(err.Error())
 		panic(err)
 	}
 	defer sentry.Flush(sentry_utils.FLUSH_TIMEOUT)
 
-	segmentConfig := segment.GetConfig(APP_NAME, cmd.BinaryVersion)
-	if err = segment.Init(segmentConfig); err != nil {
-		ui.GlobalWriter.PrintErrorMessageln(err.Error())
-		panic(err)
+	segment.SetTelemetryLog(telemetryLog)
+	if telemetrySettings.IsEnabled {
+		segmentConfig := segment.GetConfig(APP_NAME, cmd.BinaryVersion)
+		if err = segment.Init(segmentConfig); err != nil {
+			ui.GlobalWriter.PrintErrorMessageln(err.Error())
+			panic(err)
+		}
 	}
 	defer segment.Close()
 
 	ctx, cleanup := contextWithSignalInterrupt()
 	defer cleanup()
 
-	cmd.ExecuteContext(ctx)
+	if err = cmd.ExecuteContext(ctx); err != nil {
+		return 1
+	}
+
+	return 0
 }
 
 func contextWithSignalInterrupt() (context.Context, func()) {
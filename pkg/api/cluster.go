@@ -19,6 +19,13 @@ const (
 	CLUSTER_POLLING_INTERVAL = time.Second * 10
 )
 
+var ClusterPollingPolicy = ui.PollingPolicy{
+	Phase:    "connection",
+	Interval: CLUSTER_POLLING_INTERVAL,
+	Timeout:  CLUSTER_POLLING_TIMEOUT,
+	Retries:  CLUSTER_POLLING_RETRIES,
+}
+
 type Conditions struct {
 	Conditions []interface{} `json:"conditions"`
 }
@@ -66,7 +73,7 @@ func (client *Client) PollIsClusterExist(ctx context.Context, tenantUUID, backen
 		return ui.RetryableError(err)
 	}
 
-	if err = spinner.Poll(ctx, isClusterExistInSassFunc, CLUSTER_POLLING_INTERVAL, CLUSTER_POLLING_TIMEOUT, CLUSTER_POLLING_RETRIES); err == nil {
+	if err = spinner.PollWithPolicy(ctx, isClusterExistInSassFunc, ClusterPollingPolicy); err == nil {
 		return nil
 	}
 
@@ -19,6 +19,13 @@ const (
 	AUTH0_ACCOUNT_NOT_INVITED_ERROR = "access_denied: User has yet to receive an invitation."
 )
 
+var DeviceCodePollingPolicy = ui.PollingPolicy{
+	Phase:    "login",
+	Interval: DEVICE_CODE_POLLING_INTERVAL,
+	Timeout:  DEVICE_CODE_POLLING_TIMEOUT,
+	Retries:  DEVICE_CODE_POLLING_RETRIES,
+}
+
 type DeviceCode struct {
 	Interval                int    `json:"interval" validate:"required"`
 	UserCode                string `json:"user_code" validate:"required"`
@@ -85,7 +92,7 @@ func (deviceCode *DeviceCode) PollToken(ctx context.Context, auth0Token *Auth0To
 		return err
 	}
 
-	err = spinner.Poll(ctx, fetchTokenFunc, DEVICE_CODE_POLLING_INTERVAL, DEVICE_CODE_POLLING_TIMEOUT, DEVICE_CODE_POLLING_RETRIES)
+	err = spinner.PollWithPolicy(ctx, fetchTokenFunc, DeviceCodePollingPolicy)
 
 	if err == nil {
 		return nil
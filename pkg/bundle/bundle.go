@@ -0,0 +1,84 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"path"
+	"time"
+)
+
+const (
+	// the bundle holds the namespace logs and values, so only its owner can read it
+	FILE_MODE = 0600
+)
+
+// Writer writes files into a tar.gz archive, under a single root directory
+type Writer struct {
+	root       string
+	modTime    time.Time
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+	files      []string
+}
+
+func NewWriter(out io.Writer, root string) *Writer {
+	gzipWriter := gzip.NewWriter(out)
+
+	return &Writer{
+		root:       root,
+		modTime:    time.Now(),
+		gzipWriter: gzipWriter,
+		tarWriter:  tar.NewWriter(gzipWriter),
+	}
+}
+
+func (writer *Writer) AddFile(name string, data []byte) error {
+	var err error
+
+	header := &tar.Header{
+		Name:    path.Join(writer.root, name),
+		Mode:    FILE_MODE,
+		Size:    int64(len(data)),
+		ModTime: writer.modTime,
+	}
+
+	if err = writer.tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if _, err = writer.tarWriter.Write(data); err != nil {
+		return err
+	}
+
+	writer.files = append(writer.files, name)
+
+	return nil
+}
+
+func (writer *Writer) AddJSON(name string, object interface{}) error {
+	var err error
+
+	var data []byte
+	if data, err = json.MarshalIndent(object, "", "  "); err != nil {
+		return err
+	}
+
+	return writer.AddFile(name, data)
+}
+
+// Files returns the names of the files written so far, relative to the root directory
+func (writer *Writer) Files() []string {
+	return writer.files
+}
+
+func (writer *Writer) Close() error {
+	var err error
+
+	if err = writer.tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return writer.gzipWriter.Close()
+}
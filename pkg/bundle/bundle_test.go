@@ -0,0 +1,70 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/bundle"
+)
+
+type BundleTestSuite struct {
+	suite.Suite
+}
+
+func TestBundleTestSuite(t *testing.T) {
+	suite.Run(t, &BundleTestSuite{})
+}
+
+func readArchive(data []byte) (map[string]string, error) {
+	var err error
+
+	var gzipReader *gzip.Reader
+	if gzipReader, err = gzip.NewReader(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		var header *tar.Header
+		if header, err = tarReader.Next(); err == io.EOF {
+			return files, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		var content []byte
+		if content, err = io.ReadAll(tarReader); err != nil {
+			return nil, err
+		}
+
+		files[header.Name] = string(content)
+	}
+}
+
+func (suite *BundleTestSuite) TestWriteArchiveSuccess() {
+	// prepare
+	var buffer bytes.Buffer
+	writer := bundle.NewWriter(&buffer, "bundle")
+
+	// act
+	suite.NoError(writer.AddFile("logs/pod/container.log", []byte("log line")))
+	suite.NoError(writer.AddJSON("report.json", map[string]int{"nodes": 3}))
+	suite.NoError(writer.Close())
+
+	// assert
+	files, err := readArchive(buffer.Bytes())
+	suite.NoError(err)
+
+	expected := map[string]string{
+		"bundle/logs/pod/container.log": "log line",
+		"bundle/report.json":            "{\n  \"nodes\": 3\n}",
+	}
+
+	suite.Equal(expected, files)
+	suite.Equal([]string{"logs/pod/container.log", "report.json"}, writer.Files())
+}
@@ -0,0 +1,204 @@
+package fleet
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Settings struct {
+	Namespace           string   `yaml:"namespace,omitempty"`
+	ReleaseName         string   `yaml:"releaseName,omitempty"`
+	Version             string   `yaml:"version,omitempty"`
+	Mode                string   `yaml:"mode,omitempty"`
+	Registry            string   `yaml:"registry,omitempty"`
+	StorageClass        string   `yaml:"storageClass,omitempty"`
+	Values              []string `yaml:"values,omitempty"`
+	LowResources        *bool    `yaml:"lowResources,omitempty"`
+	CustomMetrics       *bool    `yaml:"customMetrics,omitempty"`
+	KubeStateMetrics    *bool    `yaml:"kubeStateMetrics,omitempty"`
+	StoreIssuesLogsOnly *bool    `yaml:"storeIssuesLogsOnly,omitempty"`
+	Tolerate            []string `yaml:"tolerate,omitempty"`
+	TolerateAllTaints   *bool    `yaml:"tolerateAllTaints,omitempty"`
+	NodeSelector        string   `yaml:"nodeSelector,omitempty"`
+	ExcludeNodeSelector string   `yaml:"excludeNodeSelector,omitempty"`
+	ExcludeNodes        []string `yaml:"excludeNodes,omitempty"`
+}
+
+type Cluster struct {
+	Settings    `yaml:",inline"`
+	Context     string `yaml:"context"`
+	ClusterName string `yaml:"clusterName,omitempty"`
+}
+
+type Fleet struct {
+	Defaults Settings   `yaml:"defaults,omitempty"`
+	Clusters []*Cluster `yaml:"clusters"`
+}
+
+func LoadFleet(path string) (*Fleet, error) {
+	var err error
+
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	var fleet Fleet
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&fleet); err != nil {
+		return nil, fmt.Errorf("failed to parse fleet file %s: %w", path, err)
+	}
+
+	if err = fleet.validate(); err != nil {
+		return nil, err
+	}
+
+	fleet.resolveValuesPaths(filepath.Dir(path))
+
+	return &fleet, nil
+}
+
+// ResolvedClusters returns the fleet clusters with the defaults applied to every unset setting
+func (fleet *Fleet) ResolvedClusters() []*Cluster {
+	clusters := make([]*Cluster, 0, len(fleet.Clusters))
+
+	for _, cluster := range fleet.Clusters {
+		resolvedCluster := *cluster
+		resolvedCluster.Settings = fleet.Defaults.merge(cluster.Settings)
+		clusters = append(clusters, &resolvedCluster)
+	}
+
+	return clusters
+}
+
+func (fleet *Fleet) validate() error {
+	if len(fleet.Clusters) == 0 {
+		return fmt.Errorf("fleet has no clusters")
+	}
+
+	contexts := make(map[string]struct{})
+	for index, cluster := range fleet.Clusters {
+		if cluster.Context == "" {
+			return fmt.Errorf("cluster #%d has no context", index+1)
+		}
+
+		if _, exists := contexts[cluster.Context]; exists {
+			return fmt.Errorf("context %s is declared more than once", cluster.Context)
+		}
+
+		contexts[cluster.Context] = struct{}{}
+
+		if cluster.hasConflictingTolerations() {
+			return fmt.Errorf("cluster %s sets both tolerate and tolerateAllTaints", cluster.Context)
+		}
+	}
+
+	if fleet.Defaults.hasConflictingTolerations() {
+		return fmt.Errorf("defaults set both tolerate and tolerateAllTaints")
+	}
+
+	return nil
+}
+
+func (fleet *Fleet) resolveValuesPaths(baseDir string) {
+	fleet.Defaults.resolveValuesPaths(baseDir)
+
+	for _, cluster := range fleet.Clusters {
+		cluster.resolveValuesPaths(baseDir)
+	}
+}
+
+func (settings Settings) merge(override Settings) Settings {
+	merged := settings
+
+	if override.Namespace != "" {
+		merged.Namespace = override.Namespace
+	}
+
+	if override.ReleaseName != "" {
+		merged.ReleaseName = override.ReleaseName
+	}
+
+	if override.Version != "" {
+		merged.Version = override.Version
+	}
+
+	if override.Mode != "" {
+		merged.Mode = override.Mode
+	}
+
+	if override.Registry != "" {
+		merged.Registry = override.Registry
+	}
+
+	if override.StorageClass != "" {
+		merged.StorageClass = override.StorageClass
+	}
+
+	if override.LowResources != nil {
+		merged.LowResources = override.LowResources
+	}
+
+	if override.CustomMetrics != nil {
+		merged.CustomMetrics = override.CustomMetrics
+	}
+
+	if override.KubeStateMetrics != nil {
+		merged.KubeStateMetrics = override.KubeStateMetrics
+	}
+
+	if override.StoreIssuesLogsOnly != nil {
+		merged.StoreIssuesLogsOnly = override.StoreIssuesLogsOnly
+	}
+
+	if override.NodeSelector != "" {
+		merged.NodeSelector = override.NodeSelector
+	}
+
+	if override.ExcludeNodeSelector != "" {
+		merged.ExcludeNodeSelector = override.ExcludeNodeSelector
+	}
+
+	if override.ExcludeNodes != nil {
+		merged.ExcludeNodes = override.ExcludeNodes
+	}
+
+	// the tolerated taints are chosen either way, so a cluster choice replaces the default one
+	if override.Tolerate != nil {
+		merged.Tolerate = override.Tolerate
+		merged.TolerateAllTaints = nil
+	}
+
+	if override.TolerateAllTaints != nil {
+		merged.TolerateAllTaints = override.TolerateAllTaints
+		merged.Tolerate = nil
+	}
+
+	// cluster values files are applied on top of the default ones
+	merged.Values = append(append([]string{}, settings.Values...), override.Values...)
+
+	return merged
+}
+
+func (settings Settings) hasConflictingTolerations() bool {
+	return len(settings.Tolerate) > 0 && settings.TolerateAllTaints != nil && *settings.TolerateAllTaints
+}
+
+// values files are relative to the fleet file, so it can be applied from any directory
+func (settings *Settings) resolveValuesPaths(baseDir string) {
+	for index, path := range settings.Values {
+		if valuesUrl, err := url.ParseRequestURI(path); err == nil && valuesUrl.IsAbs() {
+			continue
+		}
+
+		if !filepath.IsAbs(path) {
+			settings.Values[index] = filepath.Join(baseDir, path)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package fleet_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/fleet"
+)
+
+const fleetData = `
+defaults:
+  version: "^1.0.0"
+  registry: quay
+  lowResources: false
+  values:
+    - common.yaml
+clusters:
+  - context: prod-eu
+    clusterName: prod-eu-1
+    customMetrics: true
+    values:
+      - prod.yaml
+      - https://example.com/values.yaml
+  - context: staging
+    namespace: observability
+    version: 1.2.3
+    lowResources: true
+`
+
+type FleetTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (suite *FleetTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func TestFleetTestSuite(t *testing.T) {
+	suite.Run(t, &FleetTestSuite{})
+}
+
+func (suite *FleetTestSuite) writeFleet(data string) string {
+	path := filepath.Join(suite.dir, "fleet.yaml")
+	suite.NoError(os.WriteFile(path, []byte(data), 0644))
+	return path
+}
+
+func (suite *FleetTestSuite) TestLoadFleetResolvedClusters() {
+	//prepare
+	path := suite.writeFleet(fleetData)
+
+	//act
+	loadedFleet, err := fleet.LoadFleet(path)
+
+	//assert
+	suite.NoError(err)
+
+	clusters := loadedFleet.ResolvedClusters()
+	suite.Len(clusters, 2)
+
+	prod := clusters[0]
+	suite.Equal("prod-eu", prod.Context)
+	suite.Equal("prod-eu-1", prod.ClusterName)
+	suite.Equal("^1.0.0", prod.Version)
+	suite.Equal("quay", prod.Registry)
+	suite.False(*prod.LowResources)
+	suite.True(*prod.CustomMetrics)
+	suite.Nil(prod.KubeStateMetrics)
+	suite.Equal([]string{
+		filepath.Join(suite.dir, "common.yaml"),
+		filepath.Join(suite.dir, "prod.yaml"),
+		"https://example.com/values.yaml",
+	}, prod.Values)
+
+	staging := clusters[1]
+	suite.Equal("observability", staging.Namespace)
+	suite.Equal("1.2.3", staging.Version)
+	suite.True(*staging.LowResources)
+	suite.Equal([]string{filepath.Join(suite.dir, "common.yaml")}, staging.Values)
+}
+
+func (suite *FleetTestSuite) TestLoadFleetUnknownField() {
+	//prepare
+	path := suite.writeFleet("clusters:\n  - context: prod\n    lowResource: true\n")
+
+	//act
+	_, err := fleet.LoadFleet(path)
+
+	//assert
+	suite.ErrorContains(err, "field lowResource not found")
+}
+
+func (suite *FleetTestSuite) TestLoadFleetMissingContext() {
+	//prepare
+	path := suite.writeFleet("clusters:\n  - clusterName: prod\n")
+
+	//act
+	_, err := fleet.LoadFleet(path)
+
+	//assert
+	suite.EqualError(err, "cluster #1 has no context")
+}
+
+func (suite *FleetTestSuite) TestLoadFleetDuplicateContext() {
+	//prepare
+	path := suite.writeFleet("clusters:\n  - context: prod\n  - context: prod\n")
+
+	//act
+	_, err := fleet.LoadFleet(path)
+
+	//assert
+	suite.EqualError(err, "context prod is declared more than once")
+}
+
+func (suite *FleetTestSuite) TestLoadFleetNoClusters() {
+	//prepare
+	path := suite.writeFleet("defaults:\n  mode: legacy\n")
+
+	//act
+	_, err := fleet.LoadFleet(path)
+
+	//assert
+	suite.EqualError(err, "fleet has no clusters")
+}
+
+func (suite *FleetTestSuite) TestLoadFleetNodeTargeting() {
+	//prepare
+	path := suite.writeFleet(`
+defaults:
+  tolerate:
+    - dedicated=monitoring:NoSchedule
+  excludeNodes:
+    - node-1
+clusters:
+  - context: prod
+    nodeSelector: kubernetes.io/os=linux
+  - context: gpu
+    tolerateAllTaints: true
+    excludeNodes: []
+`)
+
+	//act
+	loadedFleet, err := fleet.LoadFleet(path)
+
+	//assert
+	suite.NoError(err)
+
+	clusters := loadedFleet.ResolvedClusters()
+	suite.Len(clusters, 2)
+
+	prod := clusters[0]
+	suite.Equal([]string{"dedicated=monitoring:NoSchedule"}, prod.Tolerate)
+	suite.Nil(prod.TolerateAllTaints)
+	suite.Equal("kubernetes.io/os=linux", prod.NodeSelector)
+	suite.Equal([]string{"node-1"}, prod.ExcludeNodes)
+
+	gpu := clusters[1]
+	suite.Nil(gpu.Tolerate)
+	suite.True(*gpu.TolerateAllTaints)
+	suite.Empty(gpu.ExcludeNodes)
+}
+
+func (suite *FleetTestSuite) TestLoadFleetConflictingTolerations() {
+	//prepare
+	path := suite.writeFleet("clusters:\n  - context: prod\n    tolerate: [gpu]\n    tolerateAllTaints: true\n")
+
+	//act
+	_, err := fleet.LoadFleet(path)
+
+	//assert
+	suite.EqualError(err, "cluster prod sets both tolerate and tolerateAllTaints")
+}
@@ -1,10 +1,19 @@
 package helm
 
 import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
 	"github.com/blang/semver/v4"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	v1 "k8s.io/api/core/v1"
 )
 
 type Chart struct {
@@ -16,6 +25,159 @@ func (chart *Chart) Version() semver.Version {
 	return version
 }
 
+const (
+	RESOURCES_KEY               = "resources"
+	AGENT_SENSOR_RESOURCES_PATH = "agent.sensor.resources"
+)
+
+// ComponentResources are the resources requests and limits of a chart component, named by its values path
+type ComponentResources struct {
+	Component string          `json:"component"`
+	Requests  v1.ResourceList `json:"requests,omitempty"`
+	Limits    v1.ResourceList `json:"limits,omitempty"`
+}
+
+// AgentSensorRequests returns the agent sensor resources requests, the given values coalesced with the chart default values
+func (chart *Chart) AgentSensorRequests(values map[string]interface{}) (v1.ResourceList, error) {
+	var err error
+
+	var coalescedValues chartutil.Values
+	if coalescedValues, err = chart.coalesceValues(values); err != nil {
+		return nil, err
+	}
+
+	var sensorResources chartutil.Values
+	if sensorResources, err = coalescedValues.Table(AGENT_SENSOR_RESOURCES_PATH); err != nil {
+		return v1.ResourceList{}, nil
+	}
+
+	var resources v1.ResourceRequirements
+	if err = decodeResources(sensorResources, &resources); err != nil {
+		return nil, err
+	}
+
+	return resources.Requests, nil
+}
+
+// ComponentsResources returns the resources of every enabled component, the given values coalesced with the chart default values
+func (chart *Chart) ComponentsResources(values map[string]interface{}) ([]*ComponentResources, error) {
+	var err error
+
+	var coalescedValues chartutil.Values
+	if coalescedValues, err = chart.coalesceValues(values); err != nil {
+		return nil, err
+	}
+
+	var componentsResources []*ComponentResources
+	if err = collectComponentsResources("", coalescedValues, &componentsResources); err != nil {
+		return nil, err
+	}
+
+	return componentsResources, nil
+}
+
+// RenderManifest renders the chart templates locally, without the cluster lookups and capabilities, as a manifest of the given release
+func (chart *Chart) RenderManifest(releaseName, namespace string, values map[string]interface{}) (string, error) {
+	var err error
+
+	options := chartutil.ReleaseOptions{Name: releaseName, Namespace: namespace, IsUpgrade: true}
+
+	var renderValues chartutil.Values
+	if renderValues, err = chartutil.ToRenderValues(chart.Chart, values, options, chartutil.DefaultCapabilities); err != nil {
+		return "", err
+	}
+
+	var templates map[string]string
+	if templates, err = engine.Render(chart.Chart, renderValues); err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		if extension := filepath.Ext(name); extension == ".yaml" || extension == ".yml" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var manifest strings.Builder
+	for _, name := range names {
+		manifest.WriteString(fmt.Sprintf("---\n# Source: %s\n%s\n", name, templates[name]))
+	}
+
+	return manifest.String(), nil
+}
+
+func (chart *Chart) coalesceValues(values map[string]interface{}) (chartutil.Values, error) {
+	var err error
+
+	// coalescing modifies the given values, so a copy is coalesced
+	var valuesCopy map[string]interface{}
+	if valuesCopy, err = CopyValues(values); err != nil {
+		return nil, err
+	}
+
+	return chartutil.CoalesceValues(chart.Chart, valuesCopy)
+}
+
+// collectComponentsResources walks the values tree, collecting every resources table with requests or limits, disabled components are skipped
+func collectComponentsResources(path string, values map[string]interface{}, componentsResources *[]*ComponentResources) error {
+	var err error
+
+	if enabled, ok := values["enabled"].(bool); ok && !enabled {
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		table, ok := values[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if key == RESOURCES_KEY && (table["requests"] != nil || table["limits"] != nil) {
+			var resources v1.ResourceRequirements
+			if err = decodeResources(table, &resources); err != nil {
+				return fmt.Errorf("invalid %s.%s: %w", path, key, err)
+			}
+
+			*componentsResources = append(*componentsResources, &ComponentResources{
+				Component: path,
+				Requests:  resources.Requests,
+				Limits:    resources.Limits,
+			})
+			continue
+		}
+
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+
+		if err = collectComponentsResources(childPath, table, componentsResources); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeResources(values map[string]interface{}, resources *v1.ResourceRequirements) error {
+	var err error
+
+	var data []byte
+	if data, err = json.Marshal(values); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, resources)
+}
+
 func (helmClient *Client) GetChart(name, version string) (*Chart, error) {
 	var err error
 	var chartPath string
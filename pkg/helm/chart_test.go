@@ -0,0 +1,120 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+	"helm.sh/helm/v3/pkg/chart"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestChartAgentSensorRequests(t *testing.T) {
+	// arrange
+	groundcoverChart := &helm.Chart{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "groundcover", Version: "1.0.0"},
+			Values: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"sensor": map[string]interface{}{
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"cpu": "500m", "memory": "512Mi"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	values := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"sensor": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"memory": "1Gi"},
+				},
+			},
+		},
+	}
+
+	// act
+	requests, err := groundcoverChart.AgentSensorRequests(values)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, resource.MustParse("500m"), requests[v1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("1Gi"), requests[v1.ResourceMemory])
+	assert.Equal(t, map[string]interface{}{"memory": "1Gi"}, values["agent"].(map[string]interface{})["sensor"].(map[string]interface{})["resources"].(map[string]interface{})["requests"])
+}
+
+func TestChartAgentSensorRequestsMissing(t *testing.T) {
+	// arrange
+	groundcoverChart := &helm.Chart{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "groundcover", Version: "1.0.0"},
+			Values:   map[string]interface{}{},
+		},
+	}
+
+	// act
+	requests, err := groundcoverChart.AgentSensorRequests(nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestChartComponentsResources(t *testing.T) {
+	// arrange
+	groundcoverChart := &helm.Chart{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "groundcover", Version: "1.0.0"},
+			Values: map[string]interface{}{
+				"portal": map[string]interface{}{
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "50m"},
+						"limits":   map[string]interface{}{"memory": "256Mi"},
+					},
+				},
+				"victoria-metrics-single": map[string]interface{}{
+					"server": map[string]interface{}{
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"memory": "128Mi"},
+						},
+					},
+				},
+				"custom-metrics": map[string]interface{}{
+					"enabled": false,
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "50m"},
+					},
+				},
+			},
+		},
+	}
+
+	values := map[string]interface{}{
+		"portal": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "5m"},
+			},
+		},
+	}
+
+	// act
+	components, err := groundcoverChart.ComponentsResources(values)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []*helm.ComponentResources{
+		{
+			Component: "portal",
+			Requests:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("5m")},
+			Limits:    v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+		{
+			Component: "victoria-metrics-single.server",
+			Requests:  v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")},
+		},
+	}, components)
+}
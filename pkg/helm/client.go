@@ -14,6 +14,10 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+const (
+	HELM_HOME_ENV = "GROUNDCOVER_HELM_HOME"
+)
+
 type Client struct {
 	settings *cli.EnvSettings
 	cfg      *action.Configuration
@@ -22,7 +26,11 @@ type Client struct {
 func NewHelmClient(namespace, kubecontext string) (*Client, error) {
 	var err error
 
-	helmPath := filepath.Join(utils.PersistentStorage.BasePath, "helm")
+	// concurrent cli processes, such as multi cluster deployments, use separate helm homes as helm writes the repositories files non atomically
+	helmPath := os.Getenv(HELM_HOME_ENV)
+	if helmPath == "" {
+		helmPath = filepath.Join(utils.PersistentStorage.BasePath, "helm")
+	}
 
 	os.Setenv("HELM_DATA_HOME", helmPath)
 	os.Setenv("HELM_CACHE_HOME", helmPath)
@@ -0,0 +1,265 @@
+package helm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+	"groundcover.com/pkg/telemetry"
+	"groundcover.com/pkg/ui"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+const (
+	ADDED_CHANGE   = "added"
+	REMOVED_CHANGE = "removed"
+	CHANGED_CHANGE = "changed"
+
+	VALUES_KEY_SEPARATOR = "."
+	OBJECT_KEY_FORMAT    = "%s/%s/%s"
+)
+
+type ValueChange struct {
+	Key      string      `json:"key"`
+	Type     string      `json:"type"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+type ObjectChange struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+}
+
+func (change ObjectChange) String() string {
+	return fmt.Sprintf(OBJECT_KEY_FORMAT, change.Kind, change.Namespace, change.Name)
+}
+
+type ReleaseDiff struct {
+	Values  []ValueChange  `json:"values"`
+	Objects []ObjectChange `json:"objects"`
+}
+
+func (diff *ReleaseDiff) IsEmpty() bool {
+	return len(diff.Values) == 0 && len(diff.Objects) == 0
+}
+
+func (diff *ReleaseDiff) PrintStatus() {
+	if diff.IsEmpty() {
+		ui.GlobalWriter.PrintSuccessMessageln("No changes found")
+		return
+	}
+
+	var messageBuffer strings.Builder
+
+	messageBuffer.WriteString(fmt.Sprintf("Values changes (%d):\n", len(diff.Values)))
+	for _, change := range diff.Values {
+		switch change.Type {
+		case ADDED_CHANGE:
+			messageBuffer.WriteString(color.GreenString("+ %s: %v\n", change.Key, change.NewValue))
+		case REMOVED_CHANGE:
+			messageBuffer.WriteString(color.RedString("- %s: %v\n", change.Key, change.OldValue))
+		default:
+			messageBuffer.WriteString(color.YellowString("~ %s: %v -> %v\n", change.Key, change.OldValue, change.NewValue))
+		}
+	}
+
+	messageBuffer.WriteString(fmt.Sprintf("Kubernetes objects changes (%d):\n", len(diff.Objects)))
+	for _, change := range diff.Objects {
+		switch change.Type {
+		case ADDED_CHANGE:
+			messageBuffer.WriteString(color.GreenString("+ %s\n", change))
+		case REMOVED_CHANGE:
+			messageBuffer.WriteString(color.RedString("- %s\n", change))
+		default:
+			messageBuffer.WriteString(color.YellowString("~ %s\n", change))
+		}
+	}
+
+	ui.GlobalWriter.Printf("%s", messageBuffer.String())
+}
+
+func NewReleaseDiff(oldValues, newValues map[string]interface{}, oldManifest, newManifest string) (*ReleaseDiff, error) {
+	var err error
+
+	diff := &ReleaseDiff{}
+
+	if diff.Values, err = DiffValues(oldValues, newValues); err != nil {
+		return nil, err
+	}
+
+	if diff.Objects, err = DiffManifests(oldManifest, newManifest); err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+func DiffValues(oldValues, newValues map[string]interface{}) ([]ValueChange, error) {
+	var err error
+
+	// values stored by helm are decoded from json while computed values come from yaml,
+	// normalizing both sides avoids reporting int/float64 mismatches as changes
+	var oldFlatValues, newFlatValues map[string]interface{}
+	if oldFlatValues, err = flattenValues(oldValues); err != nil {
+		return nil, err
+	}
+
+	if newFlatValues, err = flattenValues(newValues); err != nil {
+		return nil, err
+	}
+
+	changes := make([]ValueChange, 0)
+
+	for key, newValue := range newFlatValues {
+		oldValue, exists := oldFlatValues[key]
+		switch {
+		case !exists:
+			changes = append(changes, ValueChange{Key: key, Type: ADDED_CHANGE, NewValue: redactChangeValue(key, newValue)})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, ValueChange{Key: key, Type: CHANGED_CHANGE, OldValue: redactChangeValue(key, oldValue), NewValue: redactChangeValue(key, newValue)})
+		}
+	}
+
+	for key, oldValue := range oldFlatValues {
+		if _, exists := newFlatValues[key]; !exists {
+			changes = append(changes, ValueChange{Key: key, Type: REMOVED_CHANGE, OldValue: redactChangeValue(key, oldValue)})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes, nil
+}
+
+// redactChangeValue redacts the value of a flattened key as it would be redacted in place by RedactValues
+func redactChangeValue(key string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	keys := strings.Split(key, VALUES_KEY_SEPARATOR)
+	parentPath := append([]string{VALUES_REDACTION_ROOT}, keys[:len(keys)-1]...)
+	lastKey := keys[len(keys)-1]
+
+	redactedParent, _ := telemetry.Redact(strings.Join(parentPath, telemetry.KEY_PATH_SEPARATOR), map[string]interface{}{lastKey: value}).(map[string]interface{})
+	return redactedParent[lastKey]
+}
+
+func DiffManifests(oldManifest, newManifest string) ([]ObjectChange, error) {
+	var err error
+
+	var oldObjects, newObjects map[string]manifestObject
+	if oldObjects, err = parseManifestObjects(oldManifest); err != nil {
+		return nil, err
+	}
+
+	if newObjects, err = parseManifestObjects(newManifest); err != nil {
+		return nil, err
+	}
+
+	changes := make([]ObjectChange, 0)
+
+	for key, newObject := range newObjects {
+		oldObject, exists := oldObjects[key]
+		switch {
+		case !exists:
+			changes = append(changes, newObject.change(ADDED_CHANGE))
+		case !reflect.DeepEqual(oldObject.content, newObject.content):
+			changes = append(changes, newObject.change(CHANGED_CHANGE))
+		}
+	}
+
+	for key, oldObject := range oldObjects {
+		if _, exists := newObjects[key]; !exists {
+			changes = append(changes, oldObject.change(REMOVED_CHANGE))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].String() < changes[j].String()
+	})
+
+	return changes, nil
+}
+
+type manifestObject struct {
+	kind      string
+	namespace string
+	name      string
+	content   map[string]interface{}
+}
+
+func (object manifestObject) change(changeType string) ObjectChange {
+	return ObjectChange{
+		Kind:      object.kind,
+		Namespace: object.namespace,
+		Name:      object.name,
+		Type:      changeType,
+	}
+}
+
+func parseManifestObjects(manifest string) (map[string]manifestObject, error) {
+	var err error
+
+	objects := make(map[string]manifestObject)
+
+	for _, document := range releaseutil.SplitManifests(manifest) {
+		var content map[string]interface{}
+		if err = yaml.Unmarshal([]byte(document), &content); err != nil {
+			return nil, err
+		}
+
+		if len(content) == 0 {
+			continue
+		}
+
+		object := manifestObject{content: content}
+		object.kind, _ = content["kind"].(string)
+
+		if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+			object.name, _ = metadata["name"].(string)
+			object.namespace, _ = metadata["namespace"].(string)
+		}
+
+		objects[fmt.Sprintf(OBJECT_KEY_FORMAT, object.kind, object.namespace, object.name)] = object
+	}
+
+	return objects, nil
+}
+
+func flattenValues(values map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+
+	var normalizedValues map[string]interface{}
+	if normalizedValues, err = CopyValues(values); err != nil {
+		return nil, err
+	}
+
+	flatValues := make(map[string]interface{})
+	flattenValuesInto(flatValues, "", normalizedValues)
+
+	return flatValues, nil
+}
+
+func flattenValuesInto(flatValues map[string]interface{}, prefix string, values map[string]interface{}) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + VALUES_KEY_SEPARATOR + key
+		}
+
+		if nestedValues, ok := value.(map[string]interface{}); ok && len(nestedValues) > 0 {
+			flattenValuesInto(flatValues, key, nestedValues)
+			continue
+		}
+
+		flatValues[key] = value
+	}
+}
@@ -0,0 +1,175 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+)
+
+const (
+	oldManifest = `---
+# Source: groundcover/templates/sensor.yaml
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: sensor
+  namespace: groundcover
+spec:
+  template:
+    spec:
+      containers:
+        - name: sensor
+          image: sensor:1.0.0
+---
+# Source: groundcover/templates/portal.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: portal
+  namespace: groundcover
+---
+# Source: groundcover/templates/legacy.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: legacy
+  namespace: groundcover
+`
+	newManifest = `---
+# Source: groundcover/templates/sensor.yaml
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: sensor
+  namespace: groundcover
+spec:
+  template:
+    spec:
+      containers:
+        - name: sensor
+          image: sensor:1.1.0
+---
+# Source: groundcover/templates/portal.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: portal
+  namespace: groundcover
+---
+# Source: groundcover/templates/clusterrole.yaml
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: groundcover
+`
+)
+
+func TestDiffValues(t *testing.T) {
+	// arrange
+	oldValues := map[string]interface{}{
+		"clusterId": "cluster",
+		"mode":      "legacy",
+		"replicas":  float64(1),
+		"agent": map[string]interface{}{
+			"tolerations": []interface{}{},
+		},
+	}
+
+	newValues := map[string]interface{}{
+		"clusterId": "cluster",
+		"replicas":  1,
+		"agent": map[string]interface{}{
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "test", "effect": "NoSchedule"},
+			},
+		},
+		"global": map[string]interface{}{
+			"backend": map[string]interface{}{"enabled": false},
+		},
+	}
+
+	// act
+	changes, err := helm.DiffValues(oldValues, newValues)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []helm.ValueChange{
+		{
+			Key:      "agent.tolerations",
+			Type:     helm.CHANGED_CHANGE,
+			OldValue: []interface{}{},
+			NewValue: []interface{}{
+				map[string]interface{}{"key": "test", "effect": "NoSchedule"},
+			},
+		},
+		{
+			Key:      "global.backend.enabled",
+			Type:     helm.ADDED_CHANGE,
+			NewValue: false,
+		},
+		{
+			Key:      "mode",
+			Type:     helm.REMOVED_CHANGE,
+			OldValue: "legacy",
+		},
+	}, changes)
+}
+
+func TestDiffValuesRedactsSensitiveKeys(t *testing.T) {
+	// arrange
+	oldValues := map[string]interface{}{
+		"global": map[string]interface{}{"groundcover_token": "old-token"},
+		"env":    []interface{}{map[string]interface{}{"name": "API_KEY", "apiKey": "old-key"}},
+	}
+
+	newValues := map[string]interface{}{
+		"global": map[string]interface{}{"groundcover_token": "new-token"},
+		"env":    []interface{}{map[string]interface{}{"name": "API_KEY", "apiKey": "new-key"}},
+	}
+
+	// act
+	changes, err := helm.DiffValues(oldValues, newValues)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []helm.ValueChange{
+		{
+			Key:      "env",
+			Type:     helm.CHANGED_CHANGE,
+			OldValue: []interface{}{map[string]interface{}{"name": "API_KEY", "apiKey": helm.REDACTED_VALUE}},
+			NewValue: []interface{}{map[string]interface{}{"name": "API_KEY", "apiKey": helm.REDACTED_VALUE}},
+		},
+		{
+			Key:      "global.groundcover_token",
+			Type:     helm.CHANGED_CHANGE,
+			OldValue: helm.REDACTED_VALUE,
+			NewValue: helm.REDACTED_VALUE,
+		},
+	}, changes)
+}
+
+func TestDiffManifests(t *testing.T) {
+	// act
+	changes, err := helm.DiffManifests(oldManifest, newManifest)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []helm.ObjectChange{
+		{Kind: "ClusterRole", Name: "groundcover", Type: helm.ADDED_CHANGE},
+		{Kind: "ConfigMap", Namespace: "groundcover", Name: "legacy", Type: helm.REMOVED_CHANGE},
+		{Kind: "DaemonSet", Namespace: "groundcover", Name: "sensor", Type: helm.CHANGED_CHANGE},
+	}, changes)
+}
+
+func TestNewReleaseDiffEmpty(t *testing.T) {
+	// arrange
+	values := map[string]interface{}{"clusterId": "cluster"}
+
+	// act
+	releaseDiff, err := helm.NewReleaseDiff(values, values, oldManifest, oldManifest)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, releaseDiff.IsEmpty())
+}
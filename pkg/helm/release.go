@@ -3,11 +3,21 @@ package helm
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
+	"groundcover.com/pkg/k8s"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	MAX_HISTORY_REVISIONS = 256
 )
 
 type Release struct {
@@ -19,6 +29,64 @@ func (release *Release) Version() semver.Version {
 	return version
 }
 
+func (release *Release) Revision() int {
+	return release.Release.Version
+}
+
+// LastDeployed returns when the release revision was deployed, cluster events before it belong to previous revisions
+func (release *Release) LastDeployed() time.Time {
+	if release.Info == nil {
+		return time.Time{}
+	}
+
+	return release.Info.LastDeployed.Time
+}
+
+func (release *Release) RenderedManifest() string {
+	var manifest strings.Builder
+
+	manifest.WriteString(release.Manifest)
+
+	for _, hook := range release.Hooks {
+		manifest.WriteString(fmt.Sprintf("---\n# Source: %s\n%s\n", hook.Path, hook.Manifest))
+	}
+
+	return manifest.String()
+}
+
+// Workloads returns the workloads of the release manifest deployed to the release namespace
+func (release *Release) Workloads() ([]k8s.Workload, error) {
+	var err error
+
+	var objects map[string]manifestObject
+	if objects, err = parseManifestObjects(release.Manifest); err != nil {
+		return nil, err
+	}
+
+	workloads := make([]k8s.Workload, 0)
+	for _, object := range objects {
+		if !slices.Contains(k8s.WorkloadKinds, object.kind) {
+			continue
+		}
+
+		if object.namespace != "" && object.namespace != release.Namespace {
+			continue
+		}
+
+		workloads = append(workloads, k8s.Workload{Kind: object.kind, Name: object.name})
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+
+		return workloads[i].Name < workloads[j].Name
+	})
+
+	return workloads, nil
+}
+
 func (helmClient *Client) IsReleaseInstalled(name string) (*Release, bool, error) {
 	var err error
 
@@ -50,11 +118,12 @@ func (helmClient *Client) GetCurrentRelease(name string) (*Release, error) {
 	return &release, nil
 }
 
-func (helmClient *Client) Install(ctx context.Context, name string, chart *Chart, values map[string]interface{}) (*Release, error) {
+func (helmClient *Client) Install(ctx context.Context, name, description string, chart *Chart, values map[string]interface{}) (*Release, error) {
 	var err error
 
 	client := action.NewInstall(helmClient.cfg)
 	client.Wait = false
+	client.Description = description
 	client.ReleaseName = name
 	client.CreateNamespace = true
 	client.Namespace = helmClient.settings.Namespace()
@@ -67,11 +136,31 @@ func (helmClient *Client) Install(ctx context.Context, name string, chart *Chart
 	return &release, nil
 }
 
-func (helmClient *Client) Upgrade(ctx context.Context, name string, chart *Chart, values map[string]interface{}) (*Release, error) {
+func (helmClient *Client) Template(ctx context.Context, name string, chart *Chart, values map[string]interface{}) (*Release, error) {
+	var err error
+
+	client := action.NewInstall(helmClient.cfg)
+	client.DryRun = true
+	client.Replace = true
+	client.IsUpgrade = true
+	client.IncludeCRDs = true
+	client.ReleaseName = name
+	client.Namespace = helmClient.settings.Namespace()
+
+	var release Release
+	if release.Release, err = client.RunWithContext(ctx, chart.Chart, values); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+func (helmClient *Client) Upgrade(ctx context.Context, name, description string, chart *Chart, values map[string]interface{}) (*Release, error) {
 	var err error
 
 	client := action.NewUpgrade(helmClient.cfg)
 	client.Wait = false
+	client.Description = description
 	client.ReuseValues = false
 	client.Namespace = helmClient.settings.Namespace()
 
@@ -82,12 +171,43 @@ func (helmClient *Client) Upgrade(ctx context.Context, name string, chart *Chart
 	case err == nil:
 		return &release, nil
 	case errors.Is(err, driver.ErrNoDeployedReleases), errors.Is(err, driver.ErrReleaseNotFound):
-		return helmClient.Install(ctx, name, chart, values)
+		return helmClient.Install(ctx, name, description, chart, values)
 	default:
 		return nil, err
 	}
 }
 
+func (helmClient *Client) History(name string) ([]*Release, error) {
+	var err error
+
+	client := action.NewHistory(helmClient.cfg)
+	client.Max = MAX_HISTORY_REVISIONS
+
+	var helmReleases []*release.Release
+	if helmReleases, err = client.Run(name); err != nil {
+		return nil, err
+	}
+
+	releases := make([]*Release, 0, len(helmReleases))
+	for _, helmRelease := range helmReleases {
+		releases = append(releases, &Release{Release: helmRelease})
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Revision() < releases[j].Revision()
+	})
+
+	return releases, nil
+}
+
+func (helmClient *Client) Rollback(name string, revision int) error {
+	client := action.NewRollback(helmClient.cfg)
+	client.Wait = false
+	client.Version = revision
+
+	return client.Run(name)
+}
+
 func (helmClient *Client) Uninstall(name string) error {
 	var err error
 
@@ -0,0 +1,40 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestReleaseWorkloads(t *testing.T) {
+	// arrange
+	manifest := newManifest + `---
+# Source: groundcover/templates/clickhouse.yaml
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: clickhouse
+---
+# Source: groundcover/templates/other.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: other
+  namespace: other
+`
+	groundcoverRelease := &helm.Release{Release: &release.Release{Namespace: "groundcover", Manifest: manifest}}
+
+	// act
+	workloads, err := groundcoverRelease.Workloads()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []k8s.Workload{
+		{Kind: k8s.DAEMONSET_KIND, Name: "sensor"},
+		{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"},
+		{Kind: k8s.STATEFULSET_KIND, Name: "clickhouse"},
+	}, workloads)
+}
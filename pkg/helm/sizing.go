@@ -0,0 +1,273 @@
+package helm
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	MEBIBYTE = 1024 * 1024
+
+	// memory requests marking the components in a matching manifest, far from any real request
+	COMPONENT_MARKER_BASE = 7770000000000
+)
+
+var (
+	MinimalCpuRequest    = resource.MustParse("10m")
+	MinimalMemoryRequest = resource.MustParse("32Mi")
+)
+
+// ComponentContainer is a workload container rendered with the resources of a chart component
+type ComponentContainer struct {
+	Workload  k8s.Workload
+	Container string
+}
+
+// ComponentSizing compares the resources of a chart component with the peak usage of the workload containers rendered from it
+type ComponentSizing struct {
+	Component   string                  `json:"component"`
+	Containers  []string                `json:"containers"`
+	Current     v1.ResourceRequirements `json:"current"`
+	Usage       v1.ResourceList         `json:"usage"`
+	Recommended v1.ResourceRequirements `json:"recommended"`
+}
+
+// SizeComponents sizes every component to the peak usage plus headroom of the workload containers rendered from it.
+// Containers which aren't rendered from any component are returned separately
+func SizeComponents(componentsContainers map[ComponentContainer]*ComponentResources, containersUsage []*k8s.ContainerUsage, headroom float64) ([]*ComponentSizing, []*k8s.ContainerUsage) {
+	var sizings []*ComponentSizing
+	var unmatchedContainers []*k8s.ContainerUsage
+
+	sizingsByComponent := make(map[string]*ComponentSizing)
+	for _, containerUsage := range containersUsage {
+		if containerUsage.SamplesCount == 0 {
+			continue
+		}
+
+		component, exists := componentsContainers[ComponentContainer{Workload: containerUsage.Workload, Container: containerUsage.Container}]
+		if !exists {
+			unmatchedContainers = append(unmatchedContainers, containerUsage)
+			continue
+		}
+
+		sizing, exists := sizingsByComponent[component.Component]
+		if !exists {
+			sizing = &ComponentSizing{
+				Component: component.Component,
+				Current:   v1.ResourceRequirements{Requests: component.Requests, Limits: component.Limits},
+				Usage:     v1.ResourceList{},
+			}
+			sizingsByComponent[component.Component] = sizing
+			sizings = append(sizings, sizing)
+		}
+
+		sizing.Containers = append(sizing.Containers, containerUsage.Workload.Name+"/"+containerUsage.Container)
+		for name, quantity := range containerUsage.Usage {
+			if current, exists := sizing.Usage[name]; !exists || quantity.Cmp(current) > 0 {
+				sizing.Usage[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	for _, sizing := range sizings {
+		sizing.Recommended = SizeResources(sizing.Current, sizing.Usage, headroom)
+	}
+
+	return sizings, unmatchedContainers
+}
+
+// ComponentsMatchingValues returns a copy of the values with the memory request of each component set to a distinct marker,
+// the manifest rendered with them tells which workload containers each component is rendered into, even when components share the same resources
+func ComponentsMatchingValues(values map[string]interface{}, components []*ComponentResources) (map[string]interface{}, error) {
+	var err error
+
+	var matchingValues map[string]interface{}
+	if matchingValues, err = CopyValues(values); err != nil {
+		return nil, err
+	}
+
+	if matchingValues == nil {
+		matchingValues = make(map[string]interface{})
+	}
+
+	for index, component := range components {
+		table := matchingValues
+		for _, key := range append(strings.Split(component.Component, "."), RESOURCES_KEY, "requests") {
+			child, ok := table[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				table[key] = child
+			}
+			table = child
+		}
+
+		table[string(v1.ResourceMemory)] = COMPONENT_MARKER_BASE + index
+	}
+
+	return matchingValues, nil
+}
+
+// MatchComponentsContainers maps the workload containers of a manifest rendered with the ComponentsMatchingValues to their components
+func MatchComponentsContainers(manifest string, components []*ComponentResources) (map[ComponentContainer]*ComponentResources, error) {
+	var err error
+
+	var objects map[string]manifestObject
+	if objects, err = parseManifestObjects(manifest); err != nil {
+		return nil, err
+	}
+
+	componentsContainers := make(map[ComponentContainer]*ComponentResources)
+	for _, object := range objects {
+		if !slices.Contains(k8s.WorkloadKinds, object.kind) {
+			continue
+		}
+
+		podSpec := nestedTable(object.content, "spec", "template", "spec")
+		for _, containersKey := range []string{"initContainers", "containers"} {
+			containers, _ := podSpec[containersKey].([]interface{})
+			for _, item := range containers {
+				container, _ := item.(map[string]interface{})
+
+				index, isMarker := componentMarkerIndex(nestedTable(container, RESOURCES_KEY, "requests")[string(v1.ResourceMemory)])
+				if !isMarker || index >= len(components) {
+					continue
+				}
+
+				name, _ := container["name"].(string)
+				componentsContainers[ComponentContainer{Workload: k8s.Workload{Kind: object.kind, Name: object.name}, Container: name}] = components[index]
+			}
+		}
+	}
+
+	return componentsContainers, nil
+}
+
+func nestedTable(table map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, key := range keys {
+		table, _ = table[key].(map[string]interface{})
+	}
+
+	return table
+}
+
+func componentMarkerIndex(value interface{}) (int, bool) {
+	if value == nil {
+		return 0, false
+	}
+
+	quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+	if err != nil {
+		return 0, false
+	}
+
+	index := quantity.Value() - COMPONENT_MARKER_BASE
+	return int(index), index >= 0
+}
+
+// SizeResources sizes the cpu and memory requests to the usage plus headroom, and scales the existing limits keeping their ratio to the requests.
+// Limits are never lowered, a sample may miss the usage peaks and a lower memory limit gets the containers OOM killed. Resources without usage are kept as is
+func SizeResources(current v1.ResourceRequirements, usage v1.ResourceList, headroom float64) v1.ResourceRequirements {
+	recommended := v1.ResourceRequirements{
+		Requests: current.Requests.DeepCopy(),
+		Limits:   current.Limits.DeepCopy(),
+	}
+
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		usageQuantity, exists := usage[name]
+		if !exists {
+			continue
+		}
+
+		request := scaleQuantity(name, usageQuantity, 1+headroom)
+		if minimal := minimalRequest(name); request.Cmp(minimal) < 0 {
+			request = minimal.DeepCopy()
+		}
+
+		if recommended.Requests == nil {
+			recommended.Requests = v1.ResourceList{}
+		}
+		recommended.Requests[name] = request
+
+		currentLimit, hasLimit := current.Limits[name]
+		if !hasLimit {
+			continue
+		}
+
+		limit := currentLimit.DeepCopy()
+		if currentRequest, hasRequest := current.Requests[name]; hasRequest && !currentRequest.IsZero() {
+			limit = scaleQuantity(name, request, currentLimit.AsApproximateFloat64()/currentRequest.AsApproximateFloat64())
+		}
+
+		if limit.Cmp(currentLimit) < 0 {
+			limit = currentLimit.DeepCopy()
+		}
+
+		if limit.Cmp(request) < 0 {
+			limit = request.DeepCopy()
+		}
+		recommended.Limits[name] = limit
+	}
+
+	return recommended
+}
+
+// scaleQuantity multiplies the quantity by the factor, rounding up to a millicore for cpu and to a mebibyte for memory
+func scaleQuantity(name v1.ResourceName, quantity resource.Quantity, factor float64) resource.Quantity {
+	if name == v1.ResourceCPU {
+		return *resource.NewMilliQuantity(int64(math.Ceil(float64(quantity.MilliValue())*factor)), resource.DecimalSI)
+	}
+
+	mebibytes := int64(math.Ceil(quantity.AsApproximateFloat64() * factor / MEBIBYTE))
+	return *resource.NewQuantity(mebibytes*MEBIBYTE, resource.BinarySI)
+}
+
+func minimalRequest(name v1.ResourceName) resource.Quantity {
+	if name == v1.ResourceCPU {
+		return MinimalCpuRequest
+	}
+
+	return MinimalMemoryRequest
+}
+
+// ComponentsSizingValues returns a values override setting the recommended resources of every sized component
+func ComponentsSizingValues(sizings []*ComponentSizing) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	for _, sizing := range sizings {
+		table := values
+		for _, key := range strings.Split(sizing.Component, ".") {
+			child, ok := table[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				table[key] = child
+			}
+			table = child
+		}
+
+		resources := make(map[string]interface{})
+		if len(sizing.Recommended.Requests) > 0 {
+			resources["requests"] = resourceListValues(sizing.Recommended.Requests)
+		}
+		if len(sizing.Recommended.Limits) > 0 {
+			resources["limits"] = resourceListValues(sizing.Recommended.Limits)
+		}
+		table[RESOURCES_KEY] = resources
+	}
+
+	return values
+}
+
+func resourceListValues(resources v1.ResourceList) map[string]interface{} {
+	values := make(map[string]interface{}, len(resources))
+	for name, quantity := range resources {
+		values[string(name)] = quantity.String()
+	}
+
+	return values
+}
@@ -0,0 +1,232 @@
+package helm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/k8s"
+	"helm.sh/helm/v3/pkg/chart"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func resourceList(cpu, memory string) v1.ResourceList {
+	resources := v1.ResourceList{}
+	if cpu != "" {
+		resources[v1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		resources[v1.ResourceMemory] = resource.MustParse(memory)
+	}
+
+	return resources
+}
+
+func TestSizeResourcesScalesRequestsAndLimits(t *testing.T) {
+	// arrange
+	current := v1.ResourceRequirements{
+		Requests: resourceList("100m", "512Mi"),
+		Limits:   resourceList("", "1Gi"),
+	}
+
+	// act
+	recommended := helm.SizeResources(current, resourceList("500m", "1Gi"), 0.2)
+
+	// assert
+	assert.Equal(t, "600m", recommended.Requests.Cpu().String())
+	assert.Equal(t, "1229Mi", recommended.Requests.Memory().String())
+	assert.Equal(t, "2458Mi", recommended.Limits.Memory().String())
+	_, hasCpuLimit := recommended.Limits[v1.ResourceCPU]
+	assert.False(t, hasCpuLimit)
+}
+
+func TestSizeResourcesNeverLowersLimits(t *testing.T) {
+	// arrange
+	current := v1.ResourceRequirements{
+		Requests: resourceList("2", "4Gi"),
+		Limits:   resourceList("4", "8Gi"),
+	}
+
+	// act
+	recommended := helm.SizeResources(current, resourceList("500m", "1Gi"), 0.2)
+
+	// assert
+	assert.Equal(t, "600m", recommended.Requests.Cpu().String())
+	assert.Equal(t, "1229Mi", recommended.Requests.Memory().String())
+	assert.Equal(t, "4", recommended.Limits.Cpu().String())
+	assert.Equal(t, "8Gi", recommended.Limits.Memory().String())
+}
+
+func TestSizeResourcesMinimalRequests(t *testing.T) {
+	// arrange
+	current := v1.ResourceRequirements{Requests: resourceList("100m", "128Mi")}
+
+	// act
+	recommended := helm.SizeResources(current, resourceList("1m", "1Mi"), 0.3)
+
+	// assert
+	assert.Equal(t, helm.MinimalCpuRequest.String(), recommended.Requests.Cpu().String())
+	assert.Equal(t, helm.MinimalMemoryRequest.String(), recommended.Requests.Memory().String())
+}
+
+func TestSizeResourcesWithoutUsage(t *testing.T) {
+	// arrange
+	current := v1.ResourceRequirements{
+		Requests: resourceList("100m", "128Mi"),
+		Limits:   resourceList("", "256Mi"),
+	}
+
+	// act
+	recommended := helm.SizeResources(current, resourceList("", ""), 0.3)
+
+	// assert
+	assert.Equal(t, current, recommended)
+}
+
+func TestSizeResourcesLimitNotBelowRequest(t *testing.T) {
+	// arrange
+	current := v1.ResourceRequirements{Limits: resourceList("", "256Mi")}
+
+	// act
+	recommended := helm.SizeResources(current, resourceList("", "1Gi"), 0)
+
+	// assert
+	assert.Equal(t, "1Gi", recommended.Requests.Memory().String())
+	assert.Equal(t, "1Gi", recommended.Limits.Memory().String())
+}
+
+func TestSizeComponentsMatchesRenderedContainers(t *testing.T) {
+	// arrange
+	portal := &helm.ComponentResources{Component: "backend.portal", Requests: resourceList("1", "1Gi")}
+	clickhouse := &helm.ComponentResources{Component: "clickhouse", Requests: resourceList("1", "1Gi")}
+
+	clickhouseWorkload := k8s.Workload{Kind: k8s.STATEFULSET_KIND, Name: "groundcover-clickhouse"}
+	portalWorkload := k8s.Workload{Kind: k8s.DEPLOYMENT_KIND, Name: "groundcover-portal"}
+	componentsContainers := map[helm.ComponentContainer]*helm.ComponentResources{
+		{Workload: clickhouseWorkload, Container: "clickhouse"}: clickhouse,
+		{Workload: portalWorkload, Container: "portal"}:         portal,
+	}
+
+	containersUsage := []*k8s.ContainerUsage{
+		{
+			Workload:     clickhouseWorkload,
+			Container:    "clickhouse",
+			Resources:    v1.ResourceRequirements{Requests: resourceList("1", "1Gi")},
+			Usage:        resourceList("100m", "100Mi"),
+			SamplesCount: 1,
+		},
+		{
+			Workload:     clickhouseWorkload,
+			Container:    "sidecar",
+			Resources:    v1.ResourceRequirements{Requests: resourceList("1", "1Gi")},
+			Usage:        resourceList("100m", "100Mi"),
+			SamplesCount: 1,
+		},
+		{
+			Workload:  portalWorkload,
+			Container: "portal",
+			Resources: v1.ResourceRequirements{Requests: resourceList("1", "1Gi")},
+		},
+	}
+
+	// act
+	sizings, unmatchedContainers := helm.SizeComponents(componentsContainers, containersUsage, 0)
+
+	// assert
+	assert.Len(t, sizings, 1)
+	assert.Equal(t, "clickhouse", sizings[0].Component)
+	assert.Equal(t, []string{"groundcover-clickhouse/clickhouse"}, sizings[0].Containers)
+	assert.Equal(t, "100m", sizings[0].Recommended.Requests.Cpu().String())
+
+	assert.Len(t, unmatchedContainers, 1)
+	assert.Equal(t, "sidecar", unmatchedContainers[0].Container)
+}
+
+func TestMatchComponentsContainersSameResources(t *testing.T) {
+	// arrange
+	template := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}-{{ .Values.name }}
+spec:
+  template:
+    spec:
+      containers:
+        - name: {{ .Values.name }}
+          resources:
+            {{- toYaml .Values.resources | nindent 12 }}
+`
+	groundcoverChart := &helm.Chart{
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "groundcover", Version: "1.0.0", APIVersion: chart.APIVersionV2},
+			Templates: []*chart.File{{Name: "templates/portal.yaml", Data: []byte(strings.ReplaceAll(template, ".Values.", ".Values.portal."))}},
+			Values: map[string]interface{}{
+				"portal": map[string]interface{}{"name": "portal"},
+			},
+		},
+	}
+	groundcoverChart.AddDependency(&chart.Chart{
+		Metadata:  &chart.Metadata{Name: "clickhouse", Version: "1.0.0", APIVersion: chart.APIVersionV2},
+		Templates: []*chart.File{{Name: "templates/clickhouse.yaml", Data: []byte(template)}},
+		Values:    map[string]interface{}{"name": "clickhouse"},
+	})
+
+	resources := map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+	}
+	values := map[string]interface{}{
+		"portal":     map[string]interface{}{"resources": resources},
+		"clickhouse": map[string]interface{}{"resources": resources},
+	}
+
+	components, err := groundcoverChart.ComponentsResources(values)
+	assert.NoError(t, err)
+
+	// act
+	matchingValues, err := helm.ComponentsMatchingValues(values, components)
+	assert.NoError(t, err)
+
+	manifest, err := groundcoverChart.RenderManifest("groundcover", "groundcover", matchingValues)
+	assert.NoError(t, err)
+
+	componentsContainers, err := helm.MatchComponentsContainers(manifest, components)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[helm.ComponentContainer]*helm.ComponentResources{
+		{Workload: k8s.Workload{Kind: k8s.DEPLOYMENT_KIND, Name: "groundcover-clickhouse"}, Container: "clickhouse"}: components[0],
+		{Workload: k8s.Workload{Kind: k8s.DEPLOYMENT_KIND, Name: "groundcover-portal"}, Container: "portal"}:         components[1],
+	}, componentsContainers)
+	assert.Equal(t, "1Gi", values["portal"].(map[string]interface{})["resources"].(map[string]interface{})["requests"].(map[string]interface{})["memory"])
+}
+
+func TestComponentsSizingValues(t *testing.T) {
+	// arrange
+	sizings := []*helm.ComponentSizing{
+		{
+			Component: "backend.portal",
+			Recommended: v1.ResourceRequirements{
+				Requests: resourceList("100m", "256Mi"),
+				Limits:   resourceList("", "512Mi"),
+			},
+		},
+	}
+
+	// act
+	values := helm.ComponentsSizingValues(sizings)
+
+	// assert
+	expected := map[string]interface{}{
+		"backend": map[string]interface{}{
+			"portal": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"cpu": "100m", "memory": "256Mi"},
+					"limits":   map[string]interface{}{"memory": "512Mi"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, values)
+}
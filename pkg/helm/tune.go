@@ -2,6 +2,9 @@ package helm
 
 import (
 	"embed"
+	"fmt"
+	"path"
+	"strings"
 
 	"github.com/blang/semver/v4"
 	"groundcover.com/pkg/k8s"
@@ -9,7 +12,10 @@ import (
 )
 
 const (
-	DEFAULT_PRESET = ""
+	DEFAULT_PRESET      = ""
+	DEFAULT_PRESET_NAME = "default"
+	AGENT_COMPONENT     = "agent"
+	BACKEND_COMPONENT   = "backend"
 
 	HIGH_RESOURCES_CLUSTER_NODE_COUNT = 30
 	HUGE_RESOURCES_CLUSTER_NODE_COUNT = 100
@@ -47,25 +53,60 @@ type AllocatableResources struct {
 	NodeCount   int
 }
 
+// PresetChoice is the resources preset chosen for a component, along with the threshold which triggered the choice
+type PresetChoice struct {
+	Component string `json:"component"`
+	Path      string `json:"path"`
+	Reason    string `json:"reason"`
+}
+
+func (choice *PresetChoice) Name() string {
+	if choice.Path == DEFAULT_PRESET {
+		return DEFAULT_PRESET_NAME
+	}
+
+	return strings.TrimSuffix(path.Base(choice.Path), path.Ext(choice.Path))
+}
+
 func GetAgentResourcePresetPath(allocatableResources *AllocatableResources, maxKernelVersion semver.Version) string {
+	return ChooseAgentResourcePreset(allocatableResources, maxKernelVersion).Path
+}
+
+func ChooseAgentResourcePreset(allocatableResources *AllocatableResources, maxKernelVersion semver.Version) *PresetChoice {
 	defaultCpuThreshold := resource.MustParse(AGENT_DEFAULT_CPU_THRESHOLD)
 	defaultMemoryThreshold := resource.MustParse(AGENT_DEFAULT_MEMORY_THRESHOLD)
 
 	minAllocatableCpu := allocatableResources.MinCpu.AsApproximateFloat64()
 	minAllocatableMemory := allocatableResources.MinMemory.AsApproximateFloat64()
 
-	if minAllocatableCpu <= defaultCpuThreshold.AsApproximateFloat64() || minAllocatableMemory <= defaultMemoryThreshold.AsApproximateFloat64() {
-		return AGENT_LOW_RESOURCES_PATH
-	}
+	choice := &PresetChoice{Component: AGENT_COMPONENT}
 
-	if semver.MustParseRange(KERNEL_5_11_SEMVER_EXPRESSION)(maxKernelVersion) {
-		return AGENT_KERNEL_5_11_PRESET_PATH
+	switch {
+	case minAllocatableCpu <= defaultCpuThreshold.AsApproximateFloat64():
+		choice.Path = AGENT_LOW_RESOURCES_PATH
+		choice.Reason = fmt.Sprintf("minimal node allocatable cpu %s <= %s", allocatableResources.MinCpu, AGENT_DEFAULT_CPU_THRESHOLD)
+	case minAllocatableMemory <= defaultMemoryThreshold.AsApproximateFloat64():
+		choice.Path = AGENT_LOW_RESOURCES_PATH
+		choice.Reason = fmt.Sprintf("minimal node allocatable memory %s <= %s", allocatableResources.MinMemory, AGENT_DEFAULT_MEMORY_THRESHOLD)
+	case semver.MustParseRange(KERNEL_5_11_SEMVER_EXPRESSION)(maxKernelVersion):
+		choice.Path = AGENT_KERNEL_5_11_PRESET_PATH
+		choice.Reason = fmt.Sprintf("maximal kernel version %s %s, eBPF maps are accounted in the agent memory", maxKernelVersion, KERNEL_5_11_SEMVER_EXPRESSION)
+	default:
+		choice.Path = DEFAULT_PRESET
+		choice.Reason = fmt.Sprintf(
+			"minimal node allocatable cpu %s > %s and memory %s > %s, maximal kernel version %s < 5.11.0",
+			allocatableResources.MinCpu, AGENT_DEFAULT_CPU_THRESHOLD, allocatableResources.MinMemory, AGENT_DEFAULT_MEMORY_THRESHOLD, maxKernelVersion,
+		)
 	}
 
-	return DEFAULT_PRESET
+	return choice
 }
 
 func GetBackendResourcePresetPath(allocatableResources *AllocatableResources) string {
+	return ChooseBackendResourcePreset(allocatableResources).Path
+}
+
+func ChooseBackendResourcePreset(allocatableResources *AllocatableResources) *PresetChoice {
 	defaultCpuThreshold := resource.MustParse(BACKEND_DEFAULT_TOTAL_CPU_THRESHOLD)
 	defaultMemoryThreshold := resource.MustParse(BACKEND_DEFAULT_TOTAL_MEMORY_THRESHOLD)
 
@@ -75,19 +116,38 @@ func GetBackendResourcePresetPath(allocatableResources *AllocatableResources) st
 	totalAllocatableCpu := allocatableResources.TotalCpu.AsApproximateFloat64()
 	totalAllocatableMemory := allocatableResources.TotalMemory.AsApproximateFloat64()
 
-	var presetPath string
+	choice := &PresetChoice{Component: BACKEND_COMPONENT}
+
 	switch {
-	case totalAllocatableCpu <= defaultCpuThreshold.AsApproximateFloat64(), totalAllocatableMemory <= defaultMemoryThreshold.AsApproximateFloat64():
-		presetPath = BACKEND_LOW_RESOURCES_PATH
-	case totalAllocatableCpu <= highCpuThreshold.AsApproximateFloat64(), totalAllocatableMemory <= highMemoryThreshold.AsApproximateFloat64():
-		presetPath = DEFAULT_PRESET
+	case totalAllocatableCpu <= defaultCpuThreshold.AsApproximateFloat64():
+		choice.Path = BACKEND_LOW_RESOURCES_PATH
+		choice.Reason = fmt.Sprintf("total allocatable cpu %s <= %s", allocatableResources.TotalCpu, BACKEND_DEFAULT_TOTAL_CPU_THRESHOLD)
+	case totalAllocatableMemory <= defaultMemoryThreshold.AsApproximateFloat64():
+		choice.Path = BACKEND_LOW_RESOURCES_PATH
+		choice.Reason = fmt.Sprintf("total allocatable memory %s <= %s", allocatableResources.TotalMemory, BACKEND_DEFAULT_TOTAL_MEMORY_THRESHOLD)
+	case totalAllocatableCpu <= highCpuThreshold.AsApproximateFloat64():
+		choice.Path = DEFAULT_PRESET
+		choice.Reason = fmt.Sprintf("total allocatable cpu %s <= %s", allocatableResources.TotalCpu, BACKEND_HIGH_TOTAL_CPU_THRESHOLD)
+	case totalAllocatableMemory <= highMemoryThreshold.AsApproximateFloat64():
+		choice.Path = DEFAULT_PRESET
+		choice.Reason = fmt.Sprintf("total allocatable memory %s <= %s", allocatableResources.TotalMemory, BACKEND_HIGH_TOTAL_MEMORY_THRESHOLD)
 	case allocatableResources.NodeCount < HUGE_RESOURCES_CLUSTER_NODE_COUNT:
-		presetPath = BACKEND_HIGH_RESOURCES_PATH
+		choice.Path = BACKEND_HIGH_RESOURCES_PATH
+		choice.Reason = fmt.Sprintf(
+			"total allocatable cpu %s > %s and memory %s > %s, %d nodes < %d",
+			allocatableResources.TotalCpu, BACKEND_HIGH_TOTAL_CPU_THRESHOLD, allocatableResources.TotalMemory, BACKEND_HIGH_TOTAL_MEMORY_THRESHOLD,
+			allocatableResources.NodeCount, HUGE_RESOURCES_CLUSTER_NODE_COUNT,
+		)
 	default:
-		return BACKEND_HUGE_RESOURCES_PATH
+		choice.Path = BACKEND_HUGE_RESOURCES_PATH
+		choice.Reason = fmt.Sprintf(
+			"total allocatable cpu %s > %s and memory %s > %s, %d nodes >= %d",
+			allocatableResources.TotalCpu, BACKEND_HIGH_TOTAL_CPU_THRESHOLD, allocatableResources.TotalMemory, BACKEND_HIGH_TOTAL_MEMORY_THRESHOLD,
+			allocatableResources.NodeCount, HUGE_RESOURCES_CLUSTER_NODE_COUNT,
+		)
 	}
 
-	return presetPath
+	return choice
 }
 
 func CalcAllocatableResources(nodesSummaries []*k8s.NodeSummary) *AllocatableResources {
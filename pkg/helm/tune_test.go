@@ -281,3 +281,59 @@ func TestCalcAllocatableResourcesMultiNodeWithArmArch(t *testing.T) {
 	assert.Equal(t, resource.NewMilliQuantity(2000, resource.DecimalSI), resources.TotalCpu)
 	assert.Equal(t, resource.NewQuantity(2000, resource.BinarySI), resources.TotalMemory)
 }
+
+func TestChooseAgentResourcePresetLowMemoryReason(t *testing.T) {
+	// arrange
+	resources := &helm.AllocatableResources{
+		MinCpu:    resource.NewScaledQuantity(2, 0),
+		MinMemory: resource.NewQuantity(512*1024*1024, resource.BinarySI),
+	}
+
+	// act
+	choice := helm.ChooseAgentResourcePreset(resources, Kernel511Semver)
+
+	// assert
+	assert.Equal(t, &helm.PresetChoice{
+		Component: helm.AGENT_COMPONENT,
+		Path:      helm.AGENT_LOW_RESOURCES_PATH,
+		Reason:    "minimal node allocatable memory 512Mi <= 1024Mi",
+	}, choice)
+	assert.Equal(t, "low-resources", choice.Name())
+}
+
+func TestChooseBackendResourcePresetHugeReason(t *testing.T) {
+	// arrange
+	totalCpu := resource.MustParse("400")
+	totalMemory := resource.MustParse("800Gi")
+	resources := &helm.AllocatableResources{
+		TotalCpu:    &totalCpu,
+		TotalMemory: &totalMemory,
+		NodeCount:   helm.HUGE_RESOURCES_CLUSTER_NODE_COUNT,
+	}
+
+	// act
+	choice := helm.ChooseBackendResourcePreset(resources)
+
+	// assert
+	assert.Equal(t, helm.BACKEND_HUGE_RESOURCES_PATH, choice.Path)
+	assert.Equal(t, "total allocatable cpu 400 > 30000m and memory 800Gi > 60000Mi, 100 nodes >= 100", choice.Reason)
+}
+
+func TestChooseBackendResourcePresetDefaultName(t *testing.T) {
+	// arrange
+	totalCpu := resource.MustParse("20")
+	totalMemory := resource.MustParse("40Gi")
+	resources := &helm.AllocatableResources{
+		TotalCpu:    &totalCpu,
+		TotalMemory: &totalMemory,
+		NodeCount:   5,
+	}
+
+	// act
+	choice := helm.ChooseBackendResourcePreset(resources)
+
+	// assert
+	assert.Equal(t, helm.DEFAULT_PRESET, choice.Path)
+	assert.Equal(t, helm.DEFAULT_PRESET_NAME, choice.Name())
+	assert.Equal(t, "total allocatable cpu 20 <= 30000m", choice.Reason)
+}
@@ -3,16 +3,36 @@ package helm
 import (
 	"bytes"
 	"embed"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
 	textTemplate "text/template"
 
 	"github.com/imdario/mergo"
 	"gopkg.in/yaml.v3"
+	"groundcover.com/pkg/telemetry"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	REDACTED_VALUE        = telemetry.REDACTED_VALUE
+	VALUES_REDACTION_ROOT = "values"
+	SECRET_KIND           = "Secret"
+	MANIFEST_SEPARATOR    = "---\n"
+	MIN_CREDENTIAL_LENGTH = 8
+)
+
+var (
+	// values key paths holding credentials, their values are redacted wherever they are rendered in the manifest
+	CredentialKeyPaths = []string{"global.groundcover_token"}
+
+	manifestSeparatorRegex = regexp.MustCompile(`(?m)^---[ \t]*\n`)
 )
 
 //go:embed templates/*
@@ -50,6 +70,22 @@ func GetChartValuesOverrides(paths []string, templateValues *TemplateValues) (ma
 	return valuesOverride, nil
 }
 
+func CopyValues(values map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+
+	var data []byte
+	if data, err = json.Marshal(values); err != nil {
+		return nil, err
+	}
+
+	var valuesCopy map[string]interface{}
+	if err = json.Unmarshal(data, &valuesCopy); err != nil {
+		return nil, err
+	}
+
+	return valuesCopy, nil
+}
+
 func readTemplateOverride(path string, templateValues *TemplateValues) ([]byte, error) {
 	var err error
 	var template *textTemplate.Template
@@ -93,3 +129,153 @@ func readValuesOverride(path string, templateValues *TemplateValues) ([]byte, er
 
 	return os.ReadFile(path)
 }
+
+// MergeValuesOverride returns a copy of the values with the override merged on top of them
+func MergeValuesOverride(values, valuesOverride map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+
+	var mergedValues map[string]interface{}
+	if mergedValues, err = CopyValues(values); err != nil {
+		return nil, err
+	}
+
+	if mergedValues == nil {
+		mergedValues = make(map[string]interface{})
+	}
+
+	if err = mergo.Merge(&mergedValues, valuesOverride, mergo.WithOverride); err != nil {
+		return nil, err
+	}
+
+	return mergedValues, nil
+}
+
+// RedactValues returns a copy of the values with their credentials replaced by the telemetry redaction rules,
+// the configured key paths are matched starting from values, e.g. values.global.ingress
+func RedactValues(values map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+
+	var valuesCopy map[string]interface{}
+	if valuesCopy, err = CopyValues(values); err != nil {
+		return nil, err
+	}
+
+	redactedValues, _ := telemetry.Redact(VALUES_REDACTION_ROOT, valuesCopy).(map[string]interface{})
+	return redactedValues, nil
+}
+
+// RedactManifest redacts a manifest rendered with the values: the data of its secrets, and the credential values wherever they are rendered,
+// as is and base64 encoded. Only the token and the configured values key paths are credentials, other values of token like keys,
+// e.g. secretName, are left as is since replacing their short common values everywhere would corrupt the manifest
+func RedactManifest(manifest string, values map[string]interface{}) string {
+	documents := manifestSeparatorRegex.Split(manifest, -1)
+	for index, document := range documents {
+		documents[index] = redactSecretData(document)
+	}
+
+	manifest = strings.Join(documents, MANIFEST_SEPARATOR)
+
+	for _, credential := range credentialValues(nil, values) {
+		if len(credential) < MIN_CREDENTIAL_LENGTH {
+			continue
+		}
+
+		manifest = strings.ReplaceAll(manifest, credential, REDACTED_VALUE)
+		manifest = strings.ReplaceAll(manifest, base64.StdEncoding.EncodeToString([]byte(credential)), REDACTED_VALUE)
+	}
+
+	return manifest
+}
+
+// redactSecretData replaces the data and stringData values of a secret document, other documents are returned as is
+func redactSecretData(document string) string {
+	var err error
+
+	var node yaml.Node
+	if err = yaml.Unmarshal([]byte(document), &node); err != nil || len(node.Content) == 0 {
+		return document
+	}
+
+	object := node.Content[0]
+	if object.Kind != yaml.MappingNode || mappingValue(object, "kind") == nil || mappingValue(object, "kind").Value != SECRET_KIND {
+		return document
+	}
+
+	for _, dataKey := range []string{"data", "stringData"} {
+		data := mappingValue(object, dataKey)
+		if data == nil || data.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for index := 1; index < len(data.Content); index += 2 {
+			data.Content[index] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: REDACTED_VALUE}
+		}
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(&node); err != nil {
+		return document
+	}
+
+	return buffer.String()
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for index := 0; index+1 < len(mapping.Content); index += 2 {
+		if mapping.Content[index].Value == key {
+			return mapping.Content[index+1]
+		}
+	}
+
+	return nil
+}
+
+// credentialValues returns the string values of the credential key paths, and every string value nested in a configured key path
+func credentialValues(path []string, value interface{}) []string {
+	var values []string
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			childPath := append(path[:len(path):len(path)], key)
+			if isCredentialKeyPath(childPath) {
+				values = append(values, stringValues(child)...)
+				continue
+			}
+
+			values = append(values, credentialValues(childPath, child)...)
+		}
+	case []interface{}:
+		for _, child := range typedValue {
+			values = append(values, credentialValues(path, child)...)
+		}
+	}
+
+	return values
+}
+
+func isCredentialKeyPath(path []string) bool {
+	keyPath := strings.Join(path, telemetry.KEY_PATH_SEPARATOR)
+	return slices.Contains(CredentialKeyPaths, keyPath) || telemetry.IsConfiguredKeyPath(VALUES_REDACTION_ROOT+telemetry.KEY_PATH_SEPARATOR+keyPath)
+}
+
+func stringValues(value interface{}) []string {
+	var values []string
+
+	switch typedValue := value.(type) {
+	case string:
+		values = append(values, typedValue)
+	case map[string]interface{}:
+		for _, child := range typedValue {
+			values = append(values, stringValues(child)...)
+		}
+	case []interface{}:
+		for _, child := range typedValue {
+			values = append(values, stringValues(child)...)
+		}
+	}
+
+	return values
+}
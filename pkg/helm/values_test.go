@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/yaml.v3"
 	"groundcover.com/pkg/helm"
+	"groundcover.com/pkg/telemetry"
 )
 
 type HelmValuesTestSuite struct {
@@ -147,3 +148,176 @@ func (suite *HelmValuesTestSuite) TestFileChartValuesOverrideSuccess() {
 
 	suite.Equal(expected, chartValues)
 }
+
+func (suite *HelmValuesTestSuite) TestMergeValuesOverrideSuccess() {
+	//prepare
+	values := map[string]interface{}{
+		"portal": map[string]interface{}{
+			"enabled":   true,
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "1"}},
+		},
+	}
+	valuesOverride := map[string]interface{}{
+		"portal": map[string]interface{}{
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "100m"}},
+		},
+	}
+
+	//act
+	mergedValues, err := helm.MergeValuesOverride(values, valuesOverride)
+	suite.NoError(err)
+
+	// assert
+	expected := map[string]interface{}{
+		"portal": map[string]interface{}{
+			"enabled":   true,
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "100m"}},
+		},
+	}
+
+	suite.Equal(expected, mergedValues)
+	suite.Equal("1", values["portal"].(map[string]interface{})["resources"].(map[string]interface{})["requests"].(map[string]interface{})["cpu"])
+}
+
+func (suite *HelmValuesTestSuite) TestRedactValuesSuccess() {
+	//prepare
+	values := map[string]interface{}{
+		"clusterId": "cluster",
+		"global": map[string]interface{}{
+			"groundcover_token": "secret-token",
+		},
+		"postgresql": map[string]interface{}{
+			"auth": map[string]interface{}{"password": "pass", "username": "user"},
+		},
+		"extraEnv": []interface{}{
+			map[string]interface{}{"name": "API_KEY", "apiKey": "key"},
+		},
+		"tokens": map[string]interface{}{"enabled": true},
+	}
+
+	//act
+	redactedValues, err := helm.RedactValues(values)
+	suite.NoError(err)
+
+	// assert
+	expected := map[string]interface{}{
+		"clusterId": "cluster",
+		"global": map[string]interface{}{
+			"groundcover_token": helm.REDACTED_VALUE,
+		},
+		"postgresql": map[string]interface{}{
+			"auth": map[string]interface{}{"password": helm.REDACTED_VALUE, "username": "user"},
+		},
+		"extraEnv": []interface{}{
+			map[string]interface{}{"name": "API_KEY", "apiKey": helm.REDACTED_VALUE},
+		},
+		"tokens": map[string]interface{}{"enabled": true},
+	}
+
+	suite.Equal(expected, redactedValues)
+	suite.Equal("secret-token", values["global"].(map[string]interface{})["groundcover_token"])
+}
+
+func (suite *HelmValuesTestSuite) TestRedactValuesConfiguredRulesSuccess() {
+	//prepare
+	redactor, err := telemetry.NewRedactor([]string{"values.global.ingress"}, []string{`[a-z]+\.corp\.example\.com`})
+	suite.NoError(err)
+
+	telemetry.SetRedactor(redactor)
+	defer func() {
+		defaultRedactor, _ := telemetry.NewRedactor(nil, nil)
+		telemetry.SetRedactor(defaultRedactor)
+	}()
+
+	values := map[string]interface{}{
+		"global": map[string]interface{}{
+			"ingress": map[string]interface{}{"site": "site"},
+			"backend": map[string]interface{}{"host": "backend.corp.example.com"},
+		},
+	}
+
+	//act
+	redactedValues, err := helm.RedactValues(values)
+	suite.NoError(err)
+
+	// assert
+	expected := map[string]interface{}{
+		"global": map[string]interface{}{
+			"ingress": helm.REDACTED_VALUE,
+			"backend": map[string]interface{}{"host": helm.REDACTED_VALUE},
+		},
+	}
+
+	suite.Equal(expected, redactedValues)
+}
+
+func (suite *HelmValuesTestSuite) TestRedactManifestSuccess() {
+	//prepare
+	values := map[string]interface{}{
+		"global": map[string]interface{}{
+			"groundcover_token": "secret-token",
+			"tokenEnabled":      true,
+		},
+	}
+
+	manifest := "kind: Secret\ndata:\n  API_KEY: c2VjcmV0LXRva2Vu\n---\nkind: DaemonSet\nenv:\n  - name: API_KEY\n    value: secret-token\n"
+
+	//act
+	redactedManifest := helm.RedactManifest(manifest, values)
+
+	// assert
+	expected := "kind: Secret\ndata:\n  API_KEY: <redacted>\n---\nkind: DaemonSet\nenv:\n  - name: API_KEY\n    value: <redacted>\n"
+
+	suite.Equal(expected, redactedManifest)
+}
+
+func (suite *HelmValuesTestSuite) TestRedactManifestKeepsSecretReferences() {
+	//prepare
+	values := map[string]interface{}{
+		"global": map[string]interface{}{
+			"groundcover_token": "secret-token",
+		},
+		"clickhouse": map[string]interface{}{
+			"auth": map[string]interface{}{"existingSecret": "groundcover", "tokenTTL": "1h"},
+		},
+		"portal": map[string]interface{}{"secretName": "groundcover"},
+	}
+
+	manifest := "---\n# Source: groundcover/templates/secret.yaml\napiVersion: v1\nkind: Secret\nmetadata:\n  name: groundcover\nstringData:\n  password: plain-password\n" +
+		"---\n# Source: groundcover/templates/portal.yaml\nkind: Deployment\nmetadata:\n  name: groundcover-portal\n  labels:\n    app: groundcover\nspec:\n  ttl: 1h\n  volumes:\n    - secret:\n        secretName: groundcover\n"
+
+	//act
+	redactedManifest := helm.RedactManifest(manifest, values)
+
+	// assert
+	expected := "---\n# Source: groundcover/templates/secret.yaml\napiVersion: v1\nkind: Secret\nmetadata:\n  name: groundcover\nstringData:\n  password: <redacted>\n" +
+		"---\n# Source: groundcover/templates/portal.yaml\nkind: Deployment\nmetadata:\n  name: groundcover-portal\n  labels:\n    app: groundcover\nspec:\n  ttl: 1h\n  volumes:\n    - secret:\n        secretName: groundcover\n"
+
+	suite.Equal(expected, redactedManifest)
+}
+
+func (suite *HelmValuesTestSuite) TestRedactManifestConfiguredKeyPaths() {
+	//prepare
+	redactor, err := telemetry.NewRedactor([]string{"values.backend.auth"}, nil)
+	suite.NoError(err)
+
+	telemetry.SetRedactor(redactor)
+	defer func() {
+		defaultRedactor, _ := telemetry.NewRedactor(nil, nil)
+		telemetry.SetRedactor(defaultRedactor)
+	}()
+
+	values := map[string]interface{}{
+		"backend": map[string]interface{}{
+			"auth": map[string]interface{}{"user": "backend-user", "pin": "1234"},
+		},
+	}
+
+	manifest := "kind: ConfigMap\ndata:\n  user: backend-user\n  pin: \"1234\"\n"
+
+	//act
+	redactedManifest := helm.RedactManifest(manifest, values)
+
+	// assert
+	suite.Equal("kind: ConfigMap\ndata:\n  user: <redacted>\n  pin: \"1234\"\n", redactedManifest)
+}
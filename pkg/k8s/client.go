@@ -4,7 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"path"
+	"sort"
+	"strings"
 
+	"golang.org/x/exp/maps"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	restclient "k8s.io/client-go/rest"
@@ -38,6 +42,39 @@ func NewKubeClient(kubeconfig, kubecontext string) (*Client, error) {
 	return kubeClient, nil
 }
 
+func MatchContextNames(kubeconfig string, patterns []string) ([]string, error) {
+	var err error
+
+	var rawConfig *clientcmdapi.Config
+	if rawConfig, err = clientcmd.LoadFromFile(kubeconfig); err != nil {
+		return nil, err
+	}
+
+	contextNames := maps.Keys(rawConfig.Contexts)
+	sort.Strings(contextNames)
+
+	var matchedContextNames []string
+	for _, contextName := range contextNames {
+		for _, pattern := range patterns {
+			var matched bool
+			if matched, err = path.Match(pattern, contextName); err != nil {
+				return nil, fmt.Errorf("invalid context pattern %q: %w", pattern, err)
+			}
+
+			if matched {
+				matchedContextNames = append(matchedContextNames, contextName)
+				break
+			}
+		}
+	}
+
+	if len(matchedContextNames) == 0 {
+		return nil, fmt.Errorf("no kubeconfig contexts match %s", strings.Join(patterns, ","))
+	}
+
+	return matchedContextNames, nil
+}
+
 func (kubeClient *Client) loadConfig(kubeconfig, kubecontext string) error {
 	var err error
 
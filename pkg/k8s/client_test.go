@@ -0,0 +1,70 @@
+package k8s_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type KubeClientTestSuite struct {
+	suite.Suite
+	Kubeconfig string
+}
+
+func (suite *KubeClientTestSuite) SetupSuite() {
+	config := clientcmdapi.NewConfig()
+	for _, contextName := range []string{"prod-eu", "prod-us", "staging-eu", "kind-local"} {
+		config.Contexts[contextName] = &clientcmdapi.Context{Cluster: contextName}
+	}
+
+	suite.Kubeconfig = filepath.Join(suite.T().TempDir(), "config")
+	suite.NoError(clientcmd.WriteToFile(*config, suite.Kubeconfig))
+}
+
+func (suite *KubeClientTestSuite) TearDownSuite() {
+	os.Remove(suite.Kubeconfig)
+}
+
+func TestKubeClientTestSuite(t *testing.T) {
+	suite.Run(t, &KubeClientTestSuite{})
+}
+
+func (suite *KubeClientTestSuite) TestMatchContextNamesExact() {
+	//act
+	contextNames, err := k8s.MatchContextNames(suite.Kubeconfig, []string{"kind-local", "staging-eu"})
+
+	//assert
+	suite.NoError(err)
+	suite.Equal([]string{"kind-local", "staging-eu"}, contextNames)
+}
+
+func (suite *KubeClientTestSuite) TestMatchContextNamesGlob() {
+	//act
+	contextNames, err := k8s.MatchContextNames(suite.Kubeconfig, []string{"prod-*", "*-us"})
+
+	//assert
+	suite.NoError(err)
+	suite.Equal([]string{"prod-eu", "prod-us"}, contextNames)
+}
+
+func (suite *KubeClientTestSuite) TestMatchContextNamesNoMatch() {
+	//act
+	contextNames, err := k8s.MatchContextNames(suite.Kubeconfig, []string{"dev-*"})
+
+	//assert
+	suite.EqualError(err, "no kubeconfig contexts match dev-*")
+	suite.Nil(contextNames)
+}
+
+func (suite *KubeClientTestSuite) TestMatchContextNamesInvalidPattern() {
+	//act
+	_, err := k8s.MatchContextNames(suite.Kubeconfig, []string{"prod-["})
+
+	//assert
+	suite.ErrorContains(err, "invalid context pattern")
+}
@@ -21,6 +21,7 @@ const (
 	CLUSTER_AUTHORIZATION_REPORT_MESSAGE_FORMAT = "K8s user authorized for groundcover installation"
 	CLUSTER_CLI_AUTH_SUPPORTED                  = "K8s CLI auth supported"
 	CLUSTER_STORAGE_SUPPORTED                   = "K8s storage provision supported"
+	CLUSTER_STORAGE_PROVISIONER_ALLOWED         = "K8s storage provisioner allowed"
 )
 
 var (
@@ -98,9 +99,10 @@ var (
 )
 
 type ClusterRequirements struct {
-	Actions       []*authv1.ResourceAttributes
-	ServerVersion semver.Version
-	BlockedTypes  []string
+	Actions             []*authv1.ResourceAttributes
+	ServerVersion       semver.Version
+	BlockedTypes        []string
+	StorageProvisioners []string
 }
 
 type ClusterSummary struct {
@@ -146,16 +148,12 @@ type ClusterReport struct {
 	ServerVersionAllowed Requirement
 	ClusterTypeAllowed   Requirement
 	StroageProvisional   Requirement
+
+	StorageProvisionerAllowed *Requirement `json:",omitempty"`
 }
 
 func (clusterReport *ClusterReport) IsLocalCluster() bool {
-	for _, localCluster := range LocalClusterTypes {
-		if strings.HasPrefix(clusterReport.ClusterName, localCluster) {
-			return true
-		}
-	}
-
-	return false
+	return IsLocalCluster(clusterReport.ClusterName)
 }
 
 func (clusterReport *ClusterReport) PrintStatus() {
@@ -183,6 +181,10 @@ func (clusterReport *ClusterReport) PrintStatus() {
 	if clusterReport.StroageProvisional.IsNonCompatible {
 		return
 	}
+
+	if clusterReport.StorageProvisionerAllowed != nil {
+		clusterReport.StorageProvisionerAllowed.PrintStatus()
+	}
 }
 
 func (clusterRequirements ClusterRequirements) Validate(ctx context.Context, client *Client, clusterSummary *ClusterSummary) *ClusterReport {
@@ -195,11 +197,17 @@ func (clusterRequirements ClusterRequirements) Validate(ctx context.Context, cli
 		StroageProvisional:   clusterRequirements.validateStorage(ctx, client, clusterSummary),
 	}
 
+	if len(clusterRequirements.StorageProvisioners) > 0 {
+		storageProvisionerAllowed := clusterRequirements.validateStorageProvisioner(clusterSummary.StorageClass)
+		clusterReport.StorageProvisionerAllowed = &storageProvisionerAllowed
+	}
+
 	clusterReport.IsCompatible = clusterReport.ServerVersionAllowed.IsCompatible &&
 		clusterReport.UserAuthorized.IsCompatible &&
 		clusterReport.ClusterTypeAllowed.IsCompatible &&
 		clusterReport.CliAuthSupported.IsCompatible &&
-		!clusterReport.StroageProvisional.IsNonCompatible
+		!clusterReport.StroageProvisional.IsNonCompatible &&
+		(clusterReport.StorageProvisionerAllowed == nil || clusterReport.StorageProvisionerAllowed.IsCompatible)
 
 	return clusterReport
 }
@@ -351,6 +359,16 @@ func (kubeClient Client) GetServerVersion() (semver.Version, error) {
 	return serverVersion, nil
 }
 
+func IsLocalCluster(clusterName string) bool {
+	for _, localCluster := range LocalClusterTypes {
+		if strings.HasPrefix(clusterName, localCluster) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func IsEksCluster(clusterName string) bool {
 	return eksClusterRegex.MatchString(clusterName)
 }
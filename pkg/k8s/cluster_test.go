@@ -402,3 +402,38 @@ func (suite *KubeClusterTestSuite) TestClusterReportEbsCsiDriverFail() {
 
 	suite.Equal(expected, clusterReport.StroageProvisional)
 }
+
+func (suite *KubeClusterTestSuite) TestClusterReportStorageProvisionerFail() {
+	// arrange
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_CONTEXT_TIMEOUT)
+	defer cancel()
+
+	clusterSummary := &k8s.ClusterSummary{
+		Namespace:     "default",
+		ClusterName:   "minikube",
+		ServerVersion: semver.Version{Major: 1, Minor: 24},
+		StorageClass: &v1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "standard"},
+			Provisioner: "k8s.io/minikube-hostpath",
+		},
+	}
+
+	clusterRequirements := *k8s.DefaultClusterRequirements
+	clusterRequirements.StorageProvisioners = []string{"ebs.csi.aws.com"}
+
+	// act
+	clusterReport := clusterRequirements.Validate(ctx, &suite.KubeClient, clusterSummary)
+
+	// assert
+	expected := &k8s.Requirement{
+		IsCompatible:    false,
+		IsNonCompatible: true,
+		Message:         "K8s storage provisioner allowed",
+		ErrorMessages: []string{
+			"storage class standard provisioner k8s.io/minikube-hostpath isn't one of: ebs.csi.aws.com",
+		},
+	}
+
+	suite.Equal(expected, clusterReport.StorageProvisionerAllowed)
+	suite.False(clusterReport.IsCompatible)
+}
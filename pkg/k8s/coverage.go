@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	SENSOR_RUNNING  = "running"
+	SENSOR_PENDING  = "pending"
+	SENSOR_CRASHING = "crashing"
+	SENSOR_ABSENT   = "absent"
+
+	CRASH_LOOP_BACK_OFF_REASON = "CrashLoopBackOff"
+	UNTOLERATED_TAINTS_FORMAT  = "taints not tolerated: %s"
+	NODE_PRESSURE_FORMAT       = "node under %s"
+	NOT_READY_REASON           = "containers not ready"
+	EXCLUDED_PLACEMENT_REASON  = "excluded by the agent node selector or affinity"
+	NOT_SCHEDULED_REASON       = "no sensor pod scheduled"
+)
+
+var (
+	// the order in which sensor states are preferred when a node has several sensor pods, e.g. during a rollout
+	sensorStatesOrder = []string{SENSOR_RUNNING, SENSOR_PENDING, SENSOR_CRASHING, SENSOR_ABSENT}
+
+	nodePressureConditions = []v1.NodeConditionType{v1.NodeMemoryPressure, v1.NodeDiskPressure, v1.NodePIDPressure}
+)
+
+// AgentPlacement holds the agent scheduling values of a release
+type AgentPlacement struct {
+	Tolerations  []v1.Toleration
+	NodeSelector map[string]string
+	Affinity     *v1.Affinity
+}
+
+func (placement *AgentPlacement) Matches(node *NodeSummary) (bool, error) {
+	return MatchesNodePlacement(node, placement.NodeSelector, placement.Affinity)
+}
+
+type NodeCoverage struct {
+	Node   string `json:"node"`
+	Sensor string `json:"sensor"`
+	Pod    string `json:"pod,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// NodesCoverage joins the report nodes with the sensor pods scheduled on them, explaining why nodes aren't covered
+func NodesCoverage(nodesReport *NodesReport, placement *AgentPlacement, sensorPods []v1.Pod) ([]*NodeCoverage, error) {
+	var err error
+
+	podsByNode := make(map[string][]*v1.Pod)
+	for index := range sensorPods {
+		pod := &sensorPods[index]
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+
+	incompatibleReasons := make(map[string]string)
+	nodes := nodesReport.SupportedNodes()
+	for _, node := range nodesReport.IncompatibleNodes {
+		nodes = append(nodes, node.NodeSummary)
+		incompatibleReasons[node.Name] = strings.Join(node.RequirementErrors, ", ")
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	coverages := make([]*NodeCoverage, 0, len(nodes))
+	for _, node := range nodes {
+		coverage := &NodeCoverage{Node: node.Name, Sensor: SENSOR_ABSENT}
+
+		for _, pod := range podsByNode[node.Name] {
+			sensor, reason := sensorState(pod)
+			if coverage.Pod == "" || slices.Index(sensorStatesOrder, sensor) < slices.Index(sensorStatesOrder, coverage.Sensor) {
+				coverage.Sensor, coverage.Pod, coverage.Reason = sensor, pod.Name, reason
+			}
+		}
+
+		if coverage.Sensor == SENSOR_PENDING && coverage.Reason == NOT_READY_REASON {
+			if pressure := nodePressureReason(node); pressure != "" {
+				coverage.Reason = pressure
+			}
+		}
+
+		if coverage.Sensor == SENSOR_ABSENT {
+			if coverage.Reason, err = absentSensorReason(node, incompatibleReasons[node.Name], placement); err != nil {
+				return nil, err
+			}
+		}
+
+		coverages = append(coverages, coverage)
+	}
+
+	return coverages, nil
+}
+
+func sensorState(pod *v1.Pod) (string, string) {
+	for _, containerStatus := range append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		if waiting := containerStatus.State.Waiting; waiting != nil && waiting.Reason == CRASH_LOOP_BACK_OFF_REASON {
+			return SENSOR_CRASHING, PodFailureReason(pod)
+		}
+	}
+
+	switch pod.Status.Phase {
+	case v1.PodFailed:
+		return SENSOR_CRASHING, joinReason(pod.Status.Reason, pod.Status.Message)
+	case v1.PodRunning:
+		if isPodReady(pod) {
+			return SENSOR_RUNNING, ""
+		}
+	}
+
+	if reason := PodFailureReason(pod); reason != "" {
+		return SENSOR_PENDING, reason
+	}
+
+	return SENSOR_PENDING, NOT_READY_REASON
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func absentSensorReason(node *NodeSummary, incompatibleReason string, placement *AgentPlacement) (string, error) {
+	var err error
+
+	if incompatibleReason != "" {
+		return incompatibleReason, nil
+	}
+
+	var untoleratedTaints []string
+	for _, taint := range node.Taints {
+		if isBuiltinTaint(taint) || isToleratingTaints(placement.Tolerations, []v1.Taint{taint}) {
+			continue
+		}
+
+		untoleratedTaints = append(untoleratedTaints, taint.ToString())
+	}
+
+	if len(untoleratedTaints) > 0 {
+		return fmt.Sprintf(UNTOLERATED_TAINTS_FORMAT, strings.Join(untoleratedTaints, ", ")), nil
+	}
+
+	var matches bool
+	if matches, err = placement.Matches(node); err != nil {
+		return "", err
+	}
+
+	if !matches {
+		return EXCLUDED_PLACEMENT_REASON, nil
+	}
+
+	if pressure := nodePressureReason(node); pressure != "" {
+		return pressure, nil
+	}
+
+	return NOT_SCHEDULED_REASON, nil
+}
+
+func nodePressureReason(node *NodeSummary) string {
+	var pressures []string
+	for _, condition := range node.Conditions {
+		if slices.Contains(nodePressureConditions, condition.Type) && condition.Status == v1.ConditionTrue {
+			pressures = append(pressures, string(condition.Type))
+		}
+	}
+
+	if len(pressures) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(NODE_PRESSURE_FORMAT, strings.Join(pressures, ", "))
+}
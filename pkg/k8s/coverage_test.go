@@ -0,0 +1,149 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type KubeCoverageTestSuite struct {
+	suite.Suite
+	NodesReport *k8s.NodesReport
+}
+
+func (suite *KubeCoverageTestSuite) SetupSuite() {
+	suite.NodesReport = &k8s.NodesReport{
+		CompatibleNodes: []*k8s.NodeSummary{
+			{Name: "running"},
+			{Name: "crashing"},
+			{Name: "pending"},
+			{Name: "excluded", Labels: map[string]string{"pool": "gpu"}},
+			{
+				Name: "pressure",
+				Conditions: []v1.NodeCondition{
+					{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue},
+					{Type: v1.NodeDiskPressure, Status: v1.ConditionFalse},
+				},
+			},
+			{Name: "unscheduled"},
+		},
+		TaintedNodes: []*k8s.IncompatibleNode{
+			{
+				NodeSummary: &k8s.NodeSummary{
+					Name:   "tainted",
+					Taints: []v1.Taint{{Key: "dedicated", Value: "db", Effect: v1.TaintEffectNoSchedule}},
+				},
+			},
+		},
+		IncompatibleNodes: []*k8s.IncompatibleNode{
+			{
+				NodeSummary:       &k8s.NodeSummary{Name: "windows"},
+				RequirementErrors: []string{"windows is unspported operating system"},
+			},
+		},
+	}
+}
+
+func TestKubeCoverageTestSuite(t *testing.T) {
+	suite.Run(t, &KubeCoverageTestSuite{})
+}
+
+func (suite *KubeCoverageTestSuite) TestNodesCoverageSuccess() {
+	//prepare
+	placement := &k8s.AgentPlacement{
+		Affinity: &v1.Affinity{
+			NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+					NodeSelectorTerms: []v1.NodeSelectorTerm{
+						{
+							MatchExpressions: []v1.NodeSelectorRequirement{
+								{Key: "pool", Operator: v1.NodeSelectorOpNotIn, Values: []string{"gpu"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	sensorPods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-old"},
+			Spec:       v1.PodSpec{NodeName: "running"},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-running"},
+			Spec:       v1.PodSpec{NodeName: "running"},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-crashing"},
+			Spec:       v1.PodSpec{NodeName: "crashing"},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "sensor",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-pending"},
+			Spec:       v1.PodSpec{NodeName: "pending"},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "sensor",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}},
+					},
+				},
+			},
+		},
+	}
+
+	//act
+	coverages, err := k8s.NodesCoverage(suite.NodesReport, placement, sensorPods)
+	suite.NoError(err)
+
+	// assert
+	expected := []*k8s.NodeCoverage{
+		{Node: "crashing", Sensor: k8s.SENSOR_CRASHING, Pod: "sensor-crashing", Reason: "pod sensor-crashing container sensor: CrashLoopBackOff"},
+		{Node: "excluded", Sensor: k8s.SENSOR_ABSENT, Reason: k8s.EXCLUDED_PLACEMENT_REASON},
+		{Node: "pending", Sensor: k8s.SENSOR_PENDING, Pod: "sensor-pending", Reason: "pod sensor-pending container sensor: ImagePullBackOff (not found)"},
+		{Node: "pressure", Sensor: k8s.SENSOR_ABSENT, Reason: "node under MemoryPressure"},
+		{Node: "running", Sensor: k8s.SENSOR_RUNNING, Pod: "sensor-running"},
+		{Node: "tainted", Sensor: k8s.SENSOR_ABSENT, Reason: "taints not tolerated: dedicated=db:NoSchedule"},
+		{Node: "unscheduled", Sensor: k8s.SENSOR_ABSENT, Reason: k8s.NOT_SCHEDULED_REASON},
+		{Node: "windows", Sensor: k8s.SENSOR_ABSENT, Reason: "windows is unspported operating system"},
+	}
+
+	suite.Equal(expected, coverages)
+}
+
+func (suite *KubeCoverageTestSuite) TestNodesCoverageToleratedTaint() {
+	//prepare
+	placement := &k8s.AgentPlacement{
+		Tolerations: []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpExists}},
+	}
+
+	//act
+	coverages, err := k8s.NodesCoverage(suite.NodesReport, placement, nil)
+	suite.NoError(err)
+
+	// assert
+	for _, coverage := range coverages {
+		if coverage.Node == "tainted" {
+			suite.Equal(k8s.NOT_SCHEDULED_REASON, coverage.Reason)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	POD_KIND = "Pod"
+
+	FAILED_SCHEDULING_REASON = "FailedScheduling"
+	FAILED_MOUNT_REASON      = "FailedMount"
+	FAILED_ATTACH_REASON     = "FailedAttachVolume"
+	BACKOFF_REASON           = "BackOff"
+	FAILED_REASON            = "Failed"
+	OOM_KILLED_REASON        = "OOMKilled"
+	UNHEALTHY_REASON         = "Unhealthy"
+	EVICTED_REASON           = "Evicted"
+	FAILED_CREATE_REASON     = "FailedCreate"
+	PROVISIONING_REASON      = "ProvisioningFailed"
+
+	OOM_KILLED_MESSAGE = "container %s exceeded its memory limit"
+)
+
+// Diagnosis groups the warnings of an involved object with the same reason, with the most likely remediation
+type Diagnosis struct {
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	Reason      string    `json:"reason"`
+	Count       int32     `json:"count"`
+	LastSeen    time.Time `json:"lastSeen"`
+	Message     string    `json:"message"`
+	Remediation string    `json:"remediation,omitempty"`
+}
+
+func (kubeClient *Client) GetNamespaceEvents(ctx context.Context, namespace string) ([]v1.Event, error) {
+	var err error
+
+	var eventList *v1.EventList
+	if eventList, err = kubeClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		return nil, err
+	}
+
+	return eventList.Items, nil
+}
+
+// DiagnoseNamespace groups the warning events by involved object and reason, and adds the OOM killed containers of the pods,
+// which aren't reported by events. Warnings last seen before since, e.g. of a previous release revision, are stale and skipped.
+// The most frequent and recent diagnoses come first
+func DiagnoseNamespace(events []v1.Event, pods []v1.Pod, since time.Time) []*Diagnosis {
+	diagnosesByKey := make(map[string]*Diagnosis)
+	var diagnoses []*Diagnosis
+
+	addDiagnosis := func(kind, name, reason, message string, count int32, lastSeen time.Time) {
+		if lastSeen.Before(since) {
+			return
+		}
+
+		key := strings.Join([]string{kind, name, reason}, "/")
+
+		diagnosis, exists := diagnosesByKey[key]
+		if !exists {
+			diagnosis = &Diagnosis{Kind: kind, Name: name, Reason: reason}
+			diagnosesByKey[key] = diagnosis
+			diagnoses = append(diagnoses, diagnosis)
+		}
+
+		diagnosis.Count += count
+		if !lastSeen.Before(diagnosis.LastSeen) {
+			diagnosis.LastSeen = lastSeen
+			diagnosis.Message = message
+		}
+	}
+
+	for _, event := range events {
+		if event.Type != v1.EventTypeWarning {
+			continue
+		}
+
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+
+		addDiagnosis(event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Reason, event.Message, count, eventLastSeen(event))
+	}
+
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			terminated := containerStatus.LastTerminationState.Terminated
+			if terminated == nil || terminated.Reason != OOM_KILLED_REASON {
+				continue
+			}
+
+			addDiagnosis(POD_KIND, pod.Name, OOM_KILLED_REASON, fmt.Sprintf(OOM_KILLED_MESSAGE, containerStatus.Name), 1, terminated.FinishedAt.Time)
+		}
+	}
+
+	for _, diagnosis := range diagnoses {
+		diagnosis.Remediation = Remediation(diagnosis.Reason, diagnosis.Message)
+	}
+
+	sort.SliceStable(diagnoses, func(i, j int) bool {
+		if diagnoses[i].Count != diagnoses[j].Count {
+			return diagnoses[i].Count > diagnoses[j].Count
+		}
+
+		return diagnoses[i].LastSeen.After(diagnoses[j].LastSeen)
+	})
+
+	return diagnoses
+}
+
+func eventLastSeen(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
+
+// Remediation returns the most likely remediation of a warning reason, refined by its message
+func Remediation(reason, message string) string {
+	lowerMessage := strings.ToLower(message)
+
+	switch reason {
+	case FAILED_SCHEDULING_REASON:
+		switch {
+		case strings.Contains(lowerMessage, "insufficient cpu"), strings.Contains(lowerMessage, "insufficient memory"):
+			return "nodes don't have enough free cpu or memory for the pod requests, add nodes or lower the requests (e.g. deploy --low-resources)"
+		case strings.Contains(lowerMessage, "persistentvolumeclaim"):
+			return "the pod volume claim isn't bound, check the storage class has a working provisioner (deploy --storage-class)"
+		case strings.Contains(lowerMessage, "taint"):
+			return "the pod doesn't tolerate the nodes taints, tolerate them with deploy --tolerate"
+		case strings.Contains(lowerMessage, "affinity"), strings.Contains(lowerMessage, "selector"):
+			return "no node matches the pod node selector or affinity, check deploy --node-selector and --exclude-nodes"
+		default:
+			return "the pod can't be scheduled, check the nodes capacity, taints and labels"
+		}
+	case FAILED_MOUNT_REASON, FAILED_ATTACH_REASON:
+		return "the pod volume can't be mounted, check the volume claim is bound and the storage driver is healthy"
+	case PROVISIONING_REASON:
+		return "the volume can't be provisioned, check the storage class provisioner and the cloud permissions"
+	case BACKOFF_REASON:
+		if strings.Contains(lowerMessage, "image") {
+			return "the image can't be pulled, check the registry is reachable from the nodes (deploy --registry)"
+		}
+		return "the container keeps crashing, check its previous logs with groundcover logs <component> --previous"
+	case FAILED_REASON:
+		if strings.Contains(lowerMessage, "pull") || strings.Contains(lowerMessage, "image") {
+			return "the image can't be pulled, check the registry is reachable from the nodes (deploy --registry)"
+		}
+		return "the container failed to start, check the pod events and the container logs"
+	case OOM_KILLED_REASON:
+		return "the container exceeded its memory limit, raise it with a values override or groundcover tune"
+	case UNHEALTHY_REASON:
+		return "the container health probe fails, check the container logs"
+	case EVICTED_REASON:
+		return "the node is under resource pressure, free the node resources or add nodes"
+	case FAILED_CREATE_REASON:
+		if strings.Contains(lowerMessage, "quota") {
+			return "the namespace resource quota is exceeded, raise the quota or lower the requests"
+		}
+		return "the workload can't create its pods, check the admission policies and the pod security settings"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,158 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type KubeEventsTestSuite struct {
+	suite.Suite
+	Now time.Time
+}
+
+func (suite *KubeEventsTestSuite) SetupSuite() {
+	suite.Now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func TestKubeEventsTestSuite(t *testing.T) {
+	suite.Run(t, &KubeEventsTestSuite{})
+}
+
+func warningEvent(kind, name, reason, message string, count int32, lastSeen time.Time) v1.Event {
+	return v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name + "." + reason, Namespace: "groundcover"},
+		InvolvedObject: v1.ObjectReference{Kind: kind, Name: name},
+		Type:           v1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+}
+
+func (suite *KubeEventsTestSuite) TestDiagnoseNamespaceGroupsWarnings() {
+	// prepare
+	events := []v1.Event{
+		warningEvent("Pod", "portal-1", k8s.FAILED_SCHEDULING_REASON, "0/3 nodes are available: 3 Insufficient memory.", 4, suite.Now.Add(-time.Minute)),
+		warningEvent("Pod", "portal-1", k8s.FAILED_SCHEDULING_REASON, "0/3 nodes are available: 3 Insufficient cpu.", 2, suite.Now),
+		warningEvent("Pod", "sensor-1", k8s.BACKOFF_REASON, "Back-off restarting failed container", 1, suite.Now),
+		{
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "portal-1"},
+			Type:           v1.EventTypeNormal,
+			Reason:         "Scheduled",
+		},
+	}
+
+	// act
+	diagnoses := k8s.DiagnoseNamespace(events, nil, time.Time{})
+
+	// assert
+	suite.Len(diagnoses, 2)
+
+	suite.Equal("portal-1", diagnoses[0].Name)
+	suite.Equal(k8s.FAILED_SCHEDULING_REASON, diagnoses[0].Reason)
+	suite.Equal(int32(6), diagnoses[0].Count)
+	suite.Equal("0/3 nodes are available: 3 Insufficient cpu.", diagnoses[0].Message)
+	suite.Contains(diagnoses[0].Remediation, "enough free cpu or memory")
+
+	suite.Equal("sensor-1", diagnoses[1].Name)
+	suite.Contains(diagnoses[1].Remediation, "--previous")
+}
+
+func (suite *KubeEventsTestSuite) TestDiagnoseNamespaceOOMKilled() {
+	// prepare
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "clickhouse-0"},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "clickhouse",
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: k8s.OOM_KILLED_REASON, FinishedAt: metav1.NewTime(suite.Now)},
+						},
+					},
+					{Name: "healthy"},
+				},
+			},
+		},
+	}
+
+	// act
+	diagnoses := k8s.DiagnoseNamespace(nil, pods, time.Time{})
+
+	// assert
+	expected := []*k8s.Diagnosis{
+		{
+			Kind:        k8s.POD_KIND,
+			Name:        "clickhouse-0",
+			Reason:      k8s.OOM_KILLED_REASON,
+			Count:       1,
+			LastSeen:    suite.Now,
+			Message:     "container clickhouse exceeded its memory limit",
+			Remediation: k8s.Remediation(k8s.OOM_KILLED_REASON, ""),
+		},
+	}
+
+	suite.Equal(expected, diagnoses)
+}
+
+func (suite *KubeEventsTestSuite) TestDiagnoseNamespaceSkipsStaleWarnings() {
+	// prepare
+	events := []v1.Event{
+		warningEvent("Pod", "portal-1", k8s.BACKOFF_REASON, "Back-off pulling image", 3, suite.Now.Add(-time.Hour)),
+		warningEvent("Pod", "portal-2", k8s.BACKOFF_REASON, "Back-off restarting failed container", 1, suite.Now),
+	}
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "clickhouse-0"},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "clickhouse",
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: k8s.OOM_KILLED_REASON, FinishedAt: metav1.NewTime(suite.Now.Add(-time.Hour))},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// act
+	diagnoses := k8s.DiagnoseNamespace(events, pods, suite.Now.Add(-time.Minute))
+
+	// assert
+	suite.Len(diagnoses, 1)
+	suite.Equal("portal-2", diagnoses[0].Name)
+}
+
+func (suite *KubeEventsTestSuite) TestRemediationByMessage() {
+	// act & assert
+	suite.Contains(k8s.Remediation(k8s.FAILED_SCHEDULING_REASON, "0/2 nodes are available: 2 node(s) had untolerated taint {dedicated: infra}"), "--tolerate")
+	suite.Contains(k8s.Remediation(k8s.FAILED_SCHEDULING_REASON, "pod has unbound immediate PersistentVolumeClaims"), "--storage-class")
+	suite.Contains(k8s.Remediation(k8s.BACKOFF_REASON, "Back-off pulling image \"quay.io/groundcover/sensor\""), "--registry")
+	suite.Contains(k8s.Remediation(k8s.FAILED_CREATE_REASON, "exceeded quota: compute"), "quota")
+	suite.Empty(k8s.Remediation("Unknown", ""))
+}
+
+func (suite *KubeEventsTestSuite) TestGetNamespaceEvents() {
+	// prepare
+	event := warningEvent("Pod", "portal-1", k8s.FAILED_MOUNT_REASON, "mount failed", 1, suite.Now)
+	kubeClient := k8s.Client{Interface: fake.NewSimpleClientset(&event)}
+
+	// act
+	events, err := kubeClient.GetNamespaceEvents(context.Background(), "groundcover")
+	suite.NoError(err)
+
+	// assert
+	suite.Len(events, 1)
+	suite.Equal(k8s.FAILED_MOUNT_REASON, events[0].Reason)
+}
@@ -0,0 +1,18 @@
+package k8s
+
+import (
+	"context"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// GetPodLogs returns the logs of the pod container selected by the log options
+func (kubeClient *Client) GetPodLogs(ctx context.Context, namespace, podName string, options *v1.PodLogOptions) ([]byte, error) {
+	return kubeClient.CoreV1().Pods(namespace).GetLogs(podName, options).DoRaw(ctx)
+}
+
+// StreamPodLogs returns a stream of the logs of the pod container selected by the log options, which follows new logs if requested
+func (kubeClient *Client) StreamPodLogs(ctx context.Context, namespace, podName string, options *v1.PodLogOptions) (io.ReadCloser, error) {
+	return kubeClient.CoreV1().Pods(namespace).GetLogs(podName, options).Stream(ctx)
+}
@@ -0,0 +1,159 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	PODS_METRICS_PATH_FORMAT = "/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods"
+)
+
+// ContainerMetrics and PodMetrics mirror the metrics.k8s.io v1beta1 resources, only the fields used for sizing are decoded
+type ContainerMetrics struct {
+	Name  string          `json:"name"`
+	Usage v1.ResourceList `json:"usage"`
+}
+
+type PodMetrics struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Containers        []ContainerMetrics `json:"containers"`
+}
+
+type podMetricsList struct {
+	Items []*PodMetrics `json:"items"`
+}
+
+// ContainerUsage is the peak usage of a workload container across its pods and the recorded samples
+type ContainerUsage struct {
+	Workload     Workload                `json:"workload"`
+	Container    string                  `json:"container"`
+	Resources    v1.ResourceRequirements `json:"resources"`
+	Usage        v1.ResourceList         `json:"usage,omitempty"`
+	SamplesCount int                     `json:"samplesCount"`
+
+	selector labels.Selector
+}
+
+// GetPodsMetrics returns the current usage of the namespace pods, served by the metrics server through the metrics.k8s.io api
+func (kubeClient *Client) GetPodsMetrics(ctx context.Context, namespace string) ([]*PodMetrics, error) {
+	var err error
+
+	var data []byte
+	if data, err = kubeClient.Discovery().RESTClient().Get().AbsPath(fmt.Sprintf(PODS_METRICS_PATH_FORMAT, namespace)).DoRaw(ctx); err != nil {
+		if k8serrors.IsNotFound(err) || k8serrors.IsServiceUnavailable(err) {
+			return nil, fmt.Errorf("metrics.k8s.io api is unavailable, make sure the metrics server is installed: %w", err)
+		}
+		return nil, err
+	}
+
+	return ParsePodsMetrics(data)
+}
+
+func ParsePodsMetrics(data []byte) ([]*PodMetrics, error) {
+	var err error
+
+	var metricsList podMetricsList
+	if err = json.Unmarshal(data, &metricsList); err != nil {
+		return nil, fmt.Errorf("failed to parse pods metrics: %w", err)
+	}
+
+	return metricsList.Items, nil
+}
+
+// GetWorkloadsContainers returns the containers of the given workloads pod templates, without usage
+func (kubeClient *Client) GetWorkloadsContainers(ctx context.Context, namespace string, workloads []Workload) ([]*ContainerUsage, error) {
+	var err error
+
+	var containersUsage []*ContainerUsage
+	for _, workload := range workloads {
+		var labelSelector *metav1.LabelSelector
+		var podSpec v1.PodSpec
+
+		switch workload.Kind {
+		case DEPLOYMENT_KIND:
+			var deployment *appsv1.Deployment
+			if deployment, err = kubeClient.AppsV1().Deployments(namespace).Get(ctx, workload.Name, metav1.GetOptions{}); err != nil {
+				return nil, err
+			}
+			labelSelector, podSpec = deployment.Spec.Selector, deployment.Spec.Template.Spec
+		case STATEFULSET_KIND:
+			var statefulSet *appsv1.StatefulSet
+			if statefulSet, err = kubeClient.AppsV1().StatefulSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{}); err != nil {
+				return nil, err
+			}
+			labelSelector, podSpec = statefulSet.Spec.Selector, statefulSet.Spec.Template.Spec
+		case DAEMONSET_KIND:
+			var daemonSet *appsv1.DaemonSet
+			if daemonSet, err = kubeClient.AppsV1().DaemonSets(namespace).Get(ctx, workload.Name, metav1.GetOptions{}); err != nil {
+				return nil, err
+			}
+			labelSelector, podSpec = daemonSet.Spec.Selector, daemonSet.Spec.Template.Spec
+		default:
+			continue
+		}
+
+		var selector labels.Selector
+		if selector, err = metav1.LabelSelectorAsSelector(labelSelector); err != nil {
+			return nil, fmt.Errorf("%s/%s selector: %w", workload.Kind, workload.Name, err)
+		}
+
+		for _, container := range podSpec.Containers {
+			containersUsage = append(containersUsage, &ContainerUsage{
+				Workload:  workload,
+				Container: container.Name,
+				Resources: container.Resources,
+				selector:  selector,
+			})
+		}
+	}
+
+	return containersUsage, nil
+}
+
+// RecordPodsUsage adds a sample of the pods metrics to the containers usage, keeping the peak usage of each resource
+func RecordPodsUsage(containersUsage []*ContainerUsage, pods []v1.Pod, podsMetrics []*PodMetrics) {
+	podsLabels := make(map[string]labels.Set, len(pods))
+	for _, pod := range pods {
+		podsLabels[pod.Name] = labels.Set(pod.Labels)
+	}
+
+	for _, containerUsage := range containersUsage {
+		isSampled := false
+
+		for _, podMetrics := range podsMetrics {
+			podLabels, exists := podsLabels[podMetrics.Name]
+			if !exists || containerUsage.selector == nil || !containerUsage.selector.Matches(podLabels) {
+				continue
+			}
+
+			for _, containerMetrics := range podMetrics.Containers {
+				if containerMetrics.Name != containerUsage.Container {
+					continue
+				}
+
+				isSampled = true
+				if containerUsage.Usage == nil {
+					containerUsage.Usage = v1.ResourceList{}
+				}
+
+				for name, quantity := range containerMetrics.Usage {
+					if current, exists := containerUsage.Usage[name]; !exists || quantity.Cmp(current) > 0 {
+						containerUsage.Usage[name] = quantity.DeepCopy()
+					}
+				}
+			}
+		}
+
+		if isSampled {
+			containerUsage.SamplesCount++
+		}
+	}
+}
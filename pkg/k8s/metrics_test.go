@@ -0,0 +1,157 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	PODS_METRICS_JSON = `{
+		"kind": "PodMetricsList",
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"items": [
+			{
+				"metadata": {"name": "portal-1", "namespace": "groundcover"},
+				"containers": [{"name": "portal", "usage": {"cpu": "120m", "memory": "200Mi"}}]
+			}
+		]
+	}`
+)
+
+type KubeMetricsTestSuite struct {
+	suite.Suite
+	KubeClient k8s.Client
+}
+
+func (suite *KubeMetricsTestSuite) SetupTest() {
+	suite.KubeClient = k8s.Client{
+		Interface: fake.NewSimpleClientset(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "portal", Namespace: "groundcover"},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "portal"}},
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name: "portal",
+									Resources: v1.ResourceRequirements{
+										Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		),
+	}
+}
+
+func TestKubeMetricsTestSuite(t *testing.T) {
+	suite.Run(t, &KubeMetricsTestSuite{})
+}
+
+func podMetrics(name, container, cpu, memory string) *k8s.PodMetrics {
+	return &k8s.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Containers: []k8s.ContainerMetrics{
+			{
+				Name: container,
+				Usage: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse(cpu),
+					v1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		},
+	}
+}
+
+func (suite *KubeMetricsTestSuite) TestParsePodsMetricsSuccess() {
+	// act
+	podsMetrics, err := k8s.ParsePodsMetrics([]byte(PODS_METRICS_JSON))
+	suite.NoError(err)
+
+	// assert
+	suite.Len(podsMetrics, 1)
+	suite.Equal("portal-1", podsMetrics[0].Name)
+	suite.Equal("portal", podsMetrics[0].Containers[0].Name)
+	suite.Equal("120m", podsMetrics[0].Containers[0].Usage.Cpu().String())
+	suite.Equal("200Mi", podsMetrics[0].Containers[0].Usage.Memory().String())
+}
+
+func (suite *KubeMetricsTestSuite) TestParsePodsMetricsInvalid() {
+	// act
+	_, err := k8s.ParsePodsMetrics([]byte("not json"))
+
+	// assert
+	suite.ErrorContains(err, "failed to parse pods metrics")
+}
+
+func (suite *KubeMetricsTestSuite) TestGetWorkloadsContainersSuccess() {
+	// prepare
+	ctx := context.Background()
+	workloads := []k8s.Workload{{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"}}
+
+	// act
+	containersUsage, err := suite.KubeClient.GetWorkloadsContainers(ctx, "groundcover", workloads)
+	suite.NoError(err)
+
+	// assert
+	suite.Len(containersUsage, 1)
+	suite.Equal(workloads[0], containersUsage[0].Workload)
+	suite.Equal("portal", containersUsage[0].Container)
+	suite.Equal("500m", containersUsage[0].Resources.Requests.Cpu().String())
+	suite.Equal(0, containersUsage[0].SamplesCount)
+}
+
+func (suite *KubeMetricsTestSuite) TestGetWorkloadsContainersMissingWorkload() {
+	// prepare
+	ctx := context.Background()
+	workloads := []k8s.Workload{{Kind: k8s.STATEFULSET_KIND, Name: "clickhouse"}}
+
+	// act
+	_, err := suite.KubeClient.GetWorkloadsContainers(ctx, "groundcover", workloads)
+
+	// assert
+	suite.Error(err)
+}
+
+func (suite *KubeMetricsTestSuite) TestRecordPodsUsageKeepsPeak() {
+	// prepare
+	ctx := context.Background()
+	workloads := []k8s.Workload{{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"}}
+
+	containersUsage, err := suite.KubeClient.GetWorkloadsContainers(ctx, "groundcover", workloads)
+	suite.NoError(err)
+
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "portal-1", Labels: map[string]string{"app": "portal"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "portal-2", Labels: map[string]string{"app": "portal"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"app": "other"}}},
+	}
+
+	// act
+	k8s.RecordPodsUsage(containersUsage, pods, []*k8s.PodMetrics{
+		podMetrics("portal-1", "portal", "100m", "300Mi"),
+		podMetrics("portal-2", "portal", "250m", "100Mi"),
+		podMetrics("other", "portal", "4", "8Gi"),
+	})
+	k8s.RecordPodsUsage(containersUsage, pods, []*k8s.PodMetrics{
+		podMetrics("portal-1", "portal", "50m", "400Mi"),
+	})
+	k8s.RecordPodsUsage(containersUsage, pods, []*k8s.PodMetrics{})
+
+	// assert
+	suite.Equal(2, containersUsage[0].SamplesCount)
+	suite.Equal("250m", containersUsage[0].Usage.Cpu().String())
+	suite.Equal("400Mi", containersUsage[0].Usage.Memory().String())
+}
@@ -23,10 +23,11 @@ const (
 	PROVIDER_REPORT_MESSAGE_FORMAT         = "Cloud provider supported (%d/%d Nodes)"
 	ARCHITECTURE_REPORT_MESSAGE_FORMAT     = "Node architecture supported (%d/%d Nodes)"
 	OPERATING_SYSTEM_REPORT_MESSAGE_FORMAT = "Node operating system supported (%d/%d Nodes)"
+	OS_IMAGE_REPORT_MESSAGE_FORMAT         = "Node OS image allowed (%d/%d Nodes)"
 )
 
 var (
-	KERNEL_VERSION_REGEX = regexp.MustCompile("^(?P<major>[0-9]).(?P<minor>[0-9]+).(?P<patch>[0-9]+)")
+	KERNEL_VERSION_REGEX = regexp.MustCompile(`^(?P<major>[0-9]+)\.(?P<minor>[0-9]+)\.(?P<patch>[0-9]+)`)
 
 	LegacyKernelVersionRange = ">=4.14.0"
 	StableKernelVersionRange = ">=5.3.0"
@@ -43,6 +44,8 @@ var (
 type NodeSummary struct {
 	CPU             *resource.Quantity `json:",omitempty"`
 	Memory          *resource.Quantity `json:",omitempty"`
+	FreeCPU         *resource.Quantity `json:",omitempty"`
+	FreeMemory      *resource.Quantity `json:",omitempty"`
 	Name            string             `json:"-"`
 	Kernel          string             `json:",omitempty"`
 	Provider        string             `json:"-"`
@@ -50,6 +53,10 @@ type NodeSummary struct {
 	Architecture    string             `json:",omitempty"`
 	OperatingSystem string             `json:",omitempty"`
 	Taints          []v1.Taint         `json:"-"`
+	Labels          map[string]string  `json:"-"`
+	Conditions      []v1.NodeCondition `json:"-"`
+
+	KernelCapabilities *KernelCapabilities `json:",omitempty"`
 }
 
 func (nodeSummary *NodeSummary) IsArm64() bool {
@@ -72,6 +79,8 @@ func (kubeClient *Client) GetNodesSummaries(ctx context.Context) ([]*NodeSummary
 	for _, node := range nodeList.Items {
 		nodeSummary := &NodeSummary{
 			Taints:          node.Spec.Taints,
+			Labels:          node.ObjectMeta.Labels,
+			Conditions:      node.Status.Conditions,
 			Name:            node.ObjectMeta.Name,
 			Provider:        node.Spec.ProviderID,
 			OSImage:         node.Status.NodeInfo.OSImage,
@@ -93,6 +102,7 @@ type NodeMinimumRequirements struct {
 	BlockedProviders         []string
 	AllowedArchitectures     []string
 	AllowedOperatingSystems  []string
+	AllowedOSImages          []string
 	LegacyKernelVersionRange semver.Range
 	StableKernelVersionRange semver.Range
 }
@@ -103,6 +113,11 @@ type NodesReport struct {
 	ProviderAllowed        Requirement
 	ArchitectureAllowed    Requirement
 	OperatingSystemAllowed Requirement
+	CPUSufficient          *Requirement `json:",omitempty"`
+	MemorySufficient       *Requirement `json:",omitempty"`
+	OSImageAllowed         *Requirement `json:",omitempty"`
+	AgentCPUFits           *Requirement `json:",omitempty"`
+	AgentMemoryFits        *Requirement `json:",omitempty"`
 	KernelVersions         semver.Versions
 	CompatibleNodes        []*NodeSummary      `json:"-"`
 	TaintedNodes           []*IncompatibleNode `json:"-"`
@@ -121,8 +136,35 @@ func (nodesReport *NodesReport) MaximalKernelVersion() semver.Version {
 	return nodesReport.KernelVersions[len(nodesReport.KernelVersions)-1]
 }
 
+// SupportedNodes returns the nodes which can run the agent, the compatible nodes and the tainted ones
+func (nodesReport *NodesReport) SupportedNodes() []*NodeSummary {
+	nodes := append([]*NodeSummary{}, nodesReport.CompatibleNodes...)
+	for _, node := range nodesReport.TaintedNodes {
+		nodes = append(nodes, node.NodeSummary)
+	}
+
+	return nodes
+}
+
+// IsLegacyKernel returns whether a node which can run the agent has a kernel version below the stable range,
+// unless a kernel probe found the node kernel capable of running the agent in the default mode
 func (nodesReport *NodesReport) IsLegacyKernel() bool {
-	return !DefaultNodeRequirements.StableKernelVersionRange(nodesReport.MinimalKernelVersion())
+	if DefaultNodeRequirements.StableKernelVersionRange(nodesReport.MinimalKernelVersion()) {
+		return false
+	}
+
+	for _, node := range nodesReport.SupportedNodes() {
+		kernelVersion, err := semver.Parse(KERNEL_VERSION_REGEX.FindString(node.Kernel))
+		if err != nil || DefaultNodeRequirements.StableKernelVersionRange(kernelVersion) {
+			continue
+		}
+
+		if node.KernelCapabilities == nil || !node.KernelCapabilities.SupportsStableMode() {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (nodesReport *NodesReport) PrintStatus() {
@@ -130,6 +172,13 @@ func (nodesReport *NodesReport) PrintStatus() {
 	nodesReport.OperatingSystemAllowed.PrintStatus()
 	nodesReport.ProviderAllowed.PrintStatus()
 	nodesReport.ArchitectureAllowed.PrintStatus()
+
+	for _, requirement := range []*Requirement{nodesReport.OSImageAllowed, nodesReport.CPUSufficient, nodesReport.MemorySufficient} {
+		if requirement != nil {
+			requirement.PrintStatus()
+		}
+	}
+
 	nodesReport.Schedulable.PrintStatus()
 }
 
@@ -145,6 +194,18 @@ func (nodeRequirements *NodeMinimumRequirements) GenerateNodeReport(nodesSummari
 	nodesCount := len(nodesSummaries)
 	kernelVersionsSet := make(map[string]struct{})
 
+	if len(nodeRequirements.AllowedOSImages) > 0 {
+		nodesReport.OSImageAllowed = &Requirement{}
+	}
+
+	if nodeRequirements.CPUAmount != nil {
+		nodesReport.CPUSufficient = &Requirement{}
+	}
+
+	if nodeRequirements.MemoryAmount != nil {
+		nodesReport.MemorySufficient = &Requirement{}
+	}
+
 	for _, nodeSummary := range nodesSummaries {
 		var requirementErrors []string
 
@@ -181,6 +242,36 @@ func (nodeRequirements *NodeMinimumRequirements) GenerateNodeReport(nodesSummari
 			)
 		}
 
+		if nodesReport.OSImageAllowed != nil {
+			if err = nodeRequirements.validateNodeOSImage(nodeSummary); err != nil {
+				requirementErrors = append(requirementErrors, err.Error())
+				nodesReport.OSImageAllowed.ErrorMessages = append(
+					nodesReport.OSImageAllowed.ErrorMessages,
+					fmt.Sprintf("node: %s - %s", nodeSummary.Name, err.Error()),
+				)
+			}
+		}
+
+		if nodesReport.CPUSufficient != nil {
+			if err = nodeRequirements.validateNodeCPU(nodeSummary); err != nil {
+				requirementErrors = append(requirementErrors, err.Error())
+				nodesReport.CPUSufficient.ErrorMessages = append(
+					nodesReport.CPUSufficient.ErrorMessages,
+					fmt.Sprintf("node: %s - %s", nodeSummary.Name, err.Error()),
+				)
+			}
+		}
+
+		if nodesReport.MemorySufficient != nil {
+			if err = nodeRequirements.validateNodeMemory(nodeSummary); err != nil {
+				requirementErrors = append(requirementErrors, err.Error())
+				nodesReport.MemorySufficient.ErrorMessages = append(
+					nodesReport.MemorySufficient.ErrorMessages,
+					fmt.Sprintf("node: %s - %s", nodeSummary.Name, err.Error()),
+				)
+			}
+		}
+
 		if len(requirementErrors) > 0 {
 			nodesReport.IncompatibleNodes = append(
 				nodesReport.IncompatibleNodes,
@@ -251,6 +342,36 @@ func (nodeRequirements *NodeMinimumRequirements) GenerateNodeReport(nodesSummari
 		len(nodesSummaries),
 	)
 
+	if nodesReport.OSImageAllowed != nil {
+		nodesReport.OSImageAllowed.IsCompatible = len(nodesReport.OSImageAllowed.ErrorMessages) == 0
+		nodesReport.OSImageAllowed.IsNonCompatible = len(nodesReport.OSImageAllowed.ErrorMessages) == nodesCount
+		nodesReport.OSImageAllowed.Message = fmt.Sprintf(
+			OS_IMAGE_REPORT_MESSAGE_FORMAT,
+			len(nodesSummaries)-len(nodesReport.OSImageAllowed.ErrorMessages),
+			len(nodesSummaries),
+		)
+	}
+
+	if nodesReport.CPUSufficient != nil {
+		nodesReport.CPUSufficient.IsCompatible = len(nodesReport.CPUSufficient.ErrorMessages) == 0
+		nodesReport.CPUSufficient.IsNonCompatible = len(nodesReport.CPUSufficient.ErrorMessages) == nodesCount
+		nodesReport.CPUSufficient.Message = fmt.Sprintf(
+			CPU_REPORT_MESSAGE_FORMAT,
+			len(nodesSummaries)-len(nodesReport.CPUSufficient.ErrorMessages),
+			len(nodesSummaries),
+		)
+	}
+
+	if nodesReport.MemorySufficient != nil {
+		nodesReport.MemorySufficient.IsCompatible = len(nodesReport.MemorySufficient.ErrorMessages) == 0
+		nodesReport.MemorySufficient.IsNonCompatible = len(nodesReport.MemorySufficient.ErrorMessages) == nodesCount
+		nodesReport.MemorySufficient.Message = fmt.Sprintf(
+			MEMORY_REPORT_MESSAGE_FORMAT,
+			len(nodesSummaries)-len(nodesReport.MemorySufficient.ErrorMessages),
+			len(nodesSummaries),
+		)
+	}
+
 	nodesReport.Schedulable.IsCompatible = len(nodesReport.Schedulable.ErrorMessages) == 0
 	nodesReport.Schedulable.IsNonCompatible = len(nodesReport.Schedulable.ErrorMessages) == nodesCount
 	nodesReport.Schedulable.Message = fmt.Sprintf(
@@ -309,6 +430,32 @@ func (nodeRequirements *NodeMinimumRequirements) validateNodeOperatingSystem(nod
 	return fmt.Errorf("%s is unspported operating system", nodeSummary.OperatingSystem)
 }
 
+func (nodeRequirements *NodeMinimumRequirements) validateNodeOSImage(nodeSummary *NodeSummary) error {
+	for _, allowedOSImage := range nodeRequirements.AllowedOSImages {
+		if strings.HasPrefix(nodeSummary.OSImage, allowedOSImage) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is unallowed os image", nodeSummary.OSImage)
+}
+
+func (nodeRequirements *NodeMinimumRequirements) validateNodeCPU(nodeSummary *NodeSummary) error {
+	if nodeSummary.CPU == nil || nodeSummary.CPU.Cmp(*nodeRequirements.CPUAmount) < 0 {
+		return fmt.Errorf("insufficient cpu, %s is required", nodeRequirements.CPUAmount)
+	}
+
+	return nil
+}
+
+func (nodeRequirements *NodeMinimumRequirements) validateNodeMemory(nodeSummary *NodeSummary) error {
+	if nodeSummary.Memory == nil || nodeSummary.Memory.Cmp(*nodeRequirements.MemoryAmount) < 0 {
+		return fmt.Errorf("insufficient memory, %s is required", nodeRequirements.MemoryAmount)
+	}
+
+	return nil
+}
+
 func (nodeRequirements *NodeMinimumRequirements) validateNodeSchedulable(nodeSummary *NodeSummary) error {
 	for _, taint := range nodeSummary.Taints {
 		if isBuiltinTaint(taint) {
@@ -225,3 +225,101 @@ func (suite *KubeNodeTestSuite) TestGenerateNodeReportSuccess() {
 
 	suite.Equal(expected, nodesReport)
 }
+
+func (suite *KubeNodeTestSuite) TestGenerateNodeReportPolicyRequirements() {
+	// prepare
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_CONTEXT_TIMEOUT)
+	defer cancel()
+
+	nodesSummaries, err := suite.KubeClient.GetNodesSummaries(ctx)
+	suite.NoError(err)
+
+	cpuAmount := resource.MustParse("1")
+	memoryAmount := resource.MustParse("2G")
+	nodeRequirements := *k8s.DefaultNodeRequirements
+	nodeRequirements.CPUAmount = &cpuAmount
+	nodeRequirements.MemoryAmount = &memoryAmount
+	nodeRequirements.AllowedOSImages = []string{"amazon"}
+
+	// act
+	nodesReport := nodeRequirements.GenerateNodeReport(nodesSummaries)
+
+	// assert
+	suite.Equal(nodesSummaries[:1], nodesReport.CompatibleNodes)
+	suite.Len(nodesReport.TaintedNodes, 1)
+
+	suite.Equal(&k8s.Requirement{
+		IsCompatible:  false,
+		Message:       "Sufficient node CPU (2/3 Nodes)",
+		ErrorMessages: []string{"node: incompatible - insufficient cpu, 1 is required"},
+	}, nodesReport.CPUSufficient)
+
+	suite.Equal(&k8s.Requirement{
+		IsCompatible:  false,
+		Message:       "Sufficient node memory (2/3 Nodes)",
+		ErrorMessages: []string{"node: incompatible - insufficient memory, 2G is required"},
+	}, nodesReport.MemorySufficient)
+
+	suite.Equal(&k8s.Requirement{
+		IsCompatible: true,
+		Message:      "Node OS image allowed (3/3 Nodes)",
+	}, nodesReport.OSImageAllowed)
+}
+
+func (suite *KubeNodeTestSuite) TestGenerateNodeReportOSImageNotAllowed() {
+	// prepare
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_CONTEXT_TIMEOUT)
+	defer cancel()
+
+	nodesSummaries, err := suite.KubeClient.GetNodesSummaries(ctx)
+	suite.NoError(err)
+
+	nodeRequirements := *k8s.DefaultNodeRequirements
+	nodeRequirements.AllowedOSImages = []string{"Bottlerocket"}
+
+	// act
+	nodesReport := nodeRequirements.GenerateNodeReport(nodesSummaries)
+
+	// assert
+	suite.Empty(nodesReport.CompatibleNodes)
+	suite.Nil(nodesReport.CPUSufficient)
+	suite.Nil(nodesReport.MemorySufficient)
+	suite.True(nodesReport.OSImageAllowed.IsNonCompatible)
+	suite.Contains(nodesReport.OSImageAllowed.ErrorMessages, "node: compatible - amazon linux is unallowed os image")
+}
+
+func (suite *KubeNodeTestSuite) TestIsLegacyKernelProbed() {
+	// prepare
+	stableCapabilities := &k8s.KernelCapabilities{
+		Kernel:       "4.18.0-477.el8.x86_64",
+		BTF:          true,
+		ProgramTypes: k8s.StableModeProgramTypes,
+	}
+
+	backportedNode := &k8s.NodeSummary{
+		Name:            "rhel",
+		Kernel:          "4.18.0-477.el8.x86_64",
+		Architecture:    "amd64",
+		OperatingSystem: "linux",
+	}
+
+	modernNode := &k8s.NodeSummary{
+		Name:            "modern",
+		Kernel:          "10.1.2",
+		Architecture:    "amd64",
+		OperatingSystem: "linux",
+	}
+
+	// act
+	nodesReport := k8s.DefaultNodeRequirements.GenerateNodeReport([]*k8s.NodeSummary{backportedNode, modernNode})
+	unprobedLegacy := nodesReport.IsLegacyKernel()
+
+	backportedNode.KernelCapabilities = stableCapabilities
+	probedLegacy := nodesReport.IsLegacyKernel()
+
+	// assert
+	suite.Len(nodesReport.CompatibleNodes, 2)
+	suite.Equal(semver.MustParse("10.1.2"), nodesReport.MaximalKernelVersion())
+	suite.True(unprobedLegacy)
+	suite.False(probedLegacy)
+}
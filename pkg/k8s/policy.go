@@ -0,0 +1,162 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/blang/semver/v4"
+	"gopkg.in/yaml.v3"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	POLICY_EXTEND_MODE   = "extend"
+	POLICY_OVERRIDE_MODE = "override"
+)
+
+type PolicyAction struct {
+	Verb        string `yaml:"verb"`
+	Group       string `yaml:"group,omitempty"`
+	Resource    string `yaml:"resource"`
+	Subresource string `yaml:"subresource,omitempty"`
+}
+
+// Policy holds requirements which are applied on top of the default cluster and nodes requirements.
+// In extend mode the policy can only make the defaults stricter, in override mode its values replace the defaults
+type Policy struct {
+	Mode                        string          `yaml:"mode,omitempty"`
+	MinimumCPU                  string          `yaml:"minimumCpu,omitempty"`
+	MinimumMemory               string          `yaml:"minimumMemory,omitempty"`
+	AllowedOSImages             []string        `yaml:"allowedOsImages,omitempty"`
+	BlockedProviders            []string        `yaml:"blockedProviders,omitempty"`
+	ExtraActions                []*PolicyAction `yaml:"extraActions,omitempty"`
+	MinimumServerVersion        string          `yaml:"minimumServerVersion,omitempty"`
+	RequiredStorageProvisioners []string        `yaml:"requiredStorageProvisioners,omitempty"`
+
+	cpuAmount     *resource.Quantity
+	memoryAmount  *resource.Quantity
+	serverVersion *semver.Version
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	var err error
+
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	var policy Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+
+	if err = policy.parse(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+
+	return &policy, nil
+}
+
+func (policy *Policy) parse() error {
+	var err error
+
+	switch policy.Mode {
+	case "":
+		policy.Mode = POLICY_EXTEND_MODE
+	case POLICY_EXTEND_MODE, POLICY_OVERRIDE_MODE:
+	default:
+		return fmt.Errorf("unknown mode %q, expected %s or %s", policy.Mode, POLICY_EXTEND_MODE, POLICY_OVERRIDE_MODE)
+	}
+
+	if policy.MinimumCPU != "" {
+		var cpuAmount resource.Quantity
+		if cpuAmount, err = resource.ParseQuantity(policy.MinimumCPU); err != nil {
+			return fmt.Errorf("minimumCpu %q: %w", policy.MinimumCPU, err)
+		}
+		policy.cpuAmount = &cpuAmount
+	}
+
+	if policy.MinimumMemory != "" {
+		var memoryAmount resource.Quantity
+		if memoryAmount, err = resource.ParseQuantity(policy.MinimumMemory); err != nil {
+			return fmt.Errorf("minimumMemory %q: %w", policy.MinimumMemory, err)
+		}
+		policy.memoryAmount = &memoryAmount
+	}
+
+	if policy.MinimumServerVersion != "" {
+		var serverVersion semver.Version
+		if serverVersion, err = semver.ParseTolerant(policy.MinimumServerVersion); err != nil {
+			return fmt.Errorf("minimumServerVersion %q: %w", policy.MinimumServerVersion, err)
+		}
+		policy.serverVersion = &serverVersion
+	}
+
+	for index, action := range policy.ExtraActions {
+		if action == nil || action.Verb == "" || action.Resource == "" {
+			return fmt.Errorf("extra action #%d must have a verb and a resource", index+1)
+		}
+	}
+
+	return nil
+}
+
+func (policy *Policy) isOverride() bool {
+	return policy.Mode == POLICY_OVERRIDE_MODE
+}
+
+// NodeRequirements returns a copy of the given node requirements with the policy applied
+func (policy *Policy) NodeRequirements(defaults *NodeMinimumRequirements) *NodeMinimumRequirements {
+	nodeRequirements := *defaults
+
+	if policy.cpuAmount != nil {
+		nodeRequirements.CPUAmount = policy.cpuAmount
+	}
+
+	if policy.memoryAmount != nil {
+		nodeRequirements.MemoryAmount = policy.memoryAmount
+	}
+
+	if len(policy.AllowedOSImages) > 0 {
+		nodeRequirements.AllowedOSImages = policy.AllowedOSImages
+	}
+
+	if policy.isOverride() && policy.BlockedProviders != nil {
+		nodeRequirements.BlockedProviders = policy.BlockedProviders
+	} else {
+		nodeRequirements.BlockedProviders = append(append([]string{}, defaults.BlockedProviders...), policy.BlockedProviders...)
+	}
+
+	return &nodeRequirements
+}
+
+// ClusterRequirements returns a copy of the given cluster requirements with the policy applied,
+// extra actions are always added to the default ones
+func (policy *Policy) ClusterRequirements(defaults *ClusterRequirements) *ClusterRequirements {
+	clusterRequirements := *defaults
+
+	if policy.serverVersion != nil && (policy.isOverride() || policy.serverVersion.GT(defaults.ServerVersion)) {
+		clusterRequirements.ServerVersion = *policy.serverVersion
+	}
+
+	if len(policy.RequiredStorageProvisioners) > 0 {
+		clusterRequirements.StorageProvisioners = policy.RequiredStorageProvisioners
+	}
+
+	clusterRequirements.Actions = append([]*authv1.ResourceAttributes{}, defaults.Actions...)
+	for _, action := range policy.ExtraActions {
+		clusterRequirements.Actions = append(clusterRequirements.Actions, &authv1.ResourceAttributes{
+			Verb:        action.Verb,
+			Group:       action.Group,
+			Resource:    action.Resource,
+			Subresource: action.Subresource,
+		})
+	}
+
+	return &clusterRequirements
+}
@@ -0,0 +1,127 @@
+package k8s_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const policyData = `
+minimumCpu: 2
+minimumMemory: 4Gi
+allowedOsImages:
+  - Bottlerocket
+blockedProviders:
+  - gce
+extraActions:
+  - verb: create
+    group: policy
+    resource: podsecuritypolicies
+minimumServerVersion: v1.24
+requiredStorageProvisioners:
+  - ebs.csi.aws.com
+`
+
+type KubePolicyTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (suite *KubePolicyTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func TestKubePolicyTestSuite(t *testing.T) {
+	suite.Run(t, &KubePolicyTestSuite{})
+}
+
+func (suite *KubePolicyTestSuite) writePolicy(data string) string {
+	path := filepath.Join(suite.dir, "policy.yaml")
+	suite.NoError(os.WriteFile(path, []byte(data), 0644))
+	return path
+}
+
+func (suite *KubePolicyTestSuite) TestPolicyExtendSuccess() {
+	//prepare
+	path := suite.writePolicy(policyData)
+
+	//act
+	policy, err := k8s.LoadPolicy(path)
+	suite.NoError(err)
+
+	nodeRequirements := policy.NodeRequirements(k8s.DefaultNodeRequirements)
+	clusterRequirements := policy.ClusterRequirements(k8s.DefaultClusterRequirements)
+
+	// assert
+	suite.Equal(k8s.POLICY_EXTEND_MODE, policy.Mode)
+	suite.Equal(resource.MustParse("2"), *nodeRequirements.CPUAmount)
+	suite.Equal(resource.MustParse("4Gi"), *nodeRequirements.MemoryAmount)
+	suite.Equal([]string{"Bottlerocket"}, nodeRequirements.AllowedOSImages)
+	suite.Equal([]string{"fargate", "gce"}, nodeRequirements.BlockedProviders)
+	suite.Equal(k8s.DefaultNodeRequirements.AllowedArchitectures, nodeRequirements.AllowedArchitectures)
+
+	suite.Equal(semver.Version{Major: 1, Minor: 24}, clusterRequirements.ServerVersion)
+	suite.Equal([]string{"ebs.csi.aws.com"}, clusterRequirements.StorageProvisioners)
+	suite.Len(clusterRequirements.Actions, len(k8s.DefaultClusterRequirements.Actions)+1)
+	suite.Equal(&authv1.ResourceAttributes{Verb: "create", Group: "policy", Resource: "podsecuritypolicies"}, clusterRequirements.Actions[len(clusterRequirements.Actions)-1])
+
+	suite.Equal([]string{"fargate"}, k8s.DefaultNodeRequirements.BlockedProviders)
+	suite.Nil(k8s.DefaultNodeRequirements.CPUAmount)
+	suite.Empty(k8s.DefaultClusterRequirements.StorageProvisioners)
+}
+
+func (suite *KubePolicyTestSuite) TestPolicyExtendKeepsStricterDefaults() {
+	//prepare
+	path := suite.writePolicy("minimumServerVersion: 1.10.0\n")
+
+	//act
+	policy, err := k8s.LoadPolicy(path)
+	suite.NoError(err)
+
+	clusterRequirements := policy.ClusterRequirements(k8s.DefaultClusterRequirements)
+
+	// assert
+	suite.Equal(k8s.MinimumServerVersionSupport, clusterRequirements.ServerVersion)
+}
+
+func (suite *KubePolicyTestSuite) TestPolicyOverrideSuccess() {
+	//prepare
+	path := suite.writePolicy("mode: override\nminimumServerVersion: 1.10.0\nblockedProviders: []\n")
+
+	//act
+	policy, err := k8s.LoadPolicy(path)
+	suite.NoError(err)
+
+	nodeRequirements := policy.NodeRequirements(k8s.DefaultNodeRequirements)
+	clusterRequirements := policy.ClusterRequirements(k8s.DefaultClusterRequirements)
+
+	// assert
+	suite.Empty(nodeRequirements.BlockedProviders)
+	suite.Equal(semver.Version{Major: 1, Minor: 10}, clusterRequirements.ServerVersion)
+	suite.Equal(k8s.DefaultClusterRequirements.Actions, clusterRequirements.Actions)
+}
+
+func (suite *KubePolicyTestSuite) TestLoadPolicyInvalid() {
+	for _, data := range []string{
+		"mode: replace\n",
+		"minimumCpu: a lot\n",
+		"minimumServerVersion: latest\n",
+		"extraActions:\n  - verb: get\n",
+		"unknownRule: true\n",
+	} {
+		//prepare
+		path := suite.writePolicy(data)
+
+		//act
+		_, err := k8s.LoadPolicy(path)
+
+		// assert
+		suite.Error(err, data)
+	}
+}
@@ -0,0 +1,271 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	KERNEL_PROBE_NAME_PREFIX    = "groundcover-kernel-probe-"
+	KERNEL_PROBE_LABEL_KEY      = "app"
+	KERNEL_PROBE_LABEL_VALUE    = "groundcover-kernel-probe"
+	KERNEL_PROBE_HOST_PATH      = "/host"
+	KERNEL_PROBE_TTL_SECONDS    = 300
+	KERNEL_PROBE_DEADLINE       = 120
+	CONFIDENTIALITY_LOCKDOWN    = "confidentiality"
+	KERNEL_PROBE_FAILURE_FORMAT = "kernel probe on node %s failed: %s"
+	UNKNOWN_PROGRAM_TYPES       = "unknown"
+
+	// KERNEL_PROBE_SCRIPT prints the node kernel eBPF capabilities as key=value lines, using busybox utilities only.
+	// The program types are unknown when the kernel config isn't exposed, as on COS and Bottlerocket
+	KERNEL_PROBE_SCRIPT = `btf=false; [ -f /host/sys/kernel/btf/vmlinux ] && btf=true
+cgroup=1; [ -f /host/sys/fs/cgroup/cgroup.controllers ] && cgroup=2
+lockdown=none; [ -f /host/sys/kernel/security/lockdown ] && lockdown=$(sed -n 's/.*\[\(.*\)\].*/\1/p' /host/sys/kernel/security/lockdown)
+config=$(zcat /proc/config.gz 2>/dev/null || cat /host/boot/config-$(uname -r) 2>/dev/null)
+has() { echo "$config" | grep -q "^$1=y"; }
+types=""
+has CONFIG_BPF_SYSCALL && types="$types,socket_filter"
+has CONFIG_BPF_EVENTS && types="$types,kprobe,tracepoint,raw_tracepoint,perf_event"
+has CONFIG_CGROUP_BPF && types="$types,cgroup_skb,cgroup_sock"
+has CONFIG_BPF_LSM && types="$types,lsm"
+[ -n "$config" ] || types="unknown"
+echo "kernel=$(uname -r)"
+echo "btf=$btf"
+echo "cgroup=$cgroup"
+echo "lockdown=$lockdown"
+echo "program_types=${types#,}"`
+)
+
+var (
+	ErrKernelProbeNotCompleted = errors.New("kernel probe hasn't completed yet")
+
+	// eBPF program types the agent requires when not running in legacy mode
+	StableModeProgramTypes = []string{"kprobe", "tracepoint", "raw_tracepoint"}
+)
+
+// KernelCapabilities are the eBPF related capabilities of a node kernel, as reported by a kernel probe
+type KernelCapabilities struct {
+	Kernel        string   `json:"kernel"`
+	BTF           bool     `json:"btf"`
+	CgroupVersion int      `json:"cgroupVersion"`
+	Lockdown      string   `json:"lockdown"`
+	ProgramTypes  []string `json:"programTypes"`
+
+	// ProgramTypesUnknown is set when the node exposes no kernel config, the kernel version decides the agent mode instead
+	ProgramTypesUnknown bool `json:"programTypesUnknown,omitempty"`
+}
+
+// SupportsStableMode returns whether the kernel can run the agent in the default mode, regardless of its version
+func (capabilities *KernelCapabilities) SupportsStableMode() bool {
+	if !capabilities.BTF || capabilities.Lockdown == CONFIDENTIALITY_LOCKDOWN {
+		return false
+	}
+
+	for _, programType := range StableModeProgramTypes {
+		if !slices.Contains(capabilities.ProgramTypes, programType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func ParseKernelCapabilities(output string) (*KernelCapabilities, error) {
+	var err error
+
+	capabilities := &KernelCapabilities{}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "kernel":
+			capabilities.Kernel = value
+		case "btf":
+			if capabilities.BTF, err = strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("invalid kernel probe btf value %q", value)
+			}
+		case "cgroup":
+			if capabilities.CgroupVersion, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("invalid kernel probe cgroup value %q", value)
+			}
+		case "lockdown":
+			capabilities.Lockdown = value
+		case "program_types":
+			if value == UNKNOWN_PROGRAM_TYPES {
+				capabilities.ProgramTypesUnknown = true
+			} else if value != "" {
+				capabilities.ProgramTypes = strings.Split(value, ",")
+			}
+		}
+	}
+
+	if capabilities.Kernel == "" {
+		return nil, fmt.Errorf("invalid kernel probe output %q", output)
+	}
+
+	return capabilities, nil
+}
+
+// KernelProbePools groups the nodes which can run the agent by node pool, nodes of a pool share their kernel, image and architecture
+func (nodesReport *NodesReport) KernelProbePools() [][]*NodeSummary {
+	var poolsKeys []string
+	pools := make(map[string][]*NodeSummary)
+
+	for _, node := range nodesReport.SupportedNodes() {
+		poolKey := strings.Join([]string{node.Kernel, node.OSImage, node.Architecture}, "/")
+		if _, exists := pools[poolKey]; !exists {
+			poolsKeys = append(poolsKeys, poolKey)
+		}
+
+		pools[poolKey] = append(pools[poolKey], node)
+	}
+
+	nodesPools := make([][]*NodeSummary, 0, len(poolsKeys))
+	for _, poolKey := range poolsKeys {
+		nodesPools = append(nodesPools, pools[poolKey])
+	}
+
+	return nodesPools
+}
+
+// CreateKernelProbeJob launches a short lived privileged job reading the kernel capabilities of the given node
+func (kubeClient *Client) CreateKernelProbeJob(ctx context.Context, namespace, image string, node *NodeSummary) (*batchv1.Job, error) {
+	hostPathType := v1.HostPathDirectory
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: KERNEL_PROBE_NAME_PREFIX,
+			Namespace:    namespace,
+			Labels:       map[string]string{KERNEL_PROBE_LABEL_KEY: KERNEL_PROBE_LABEL_VALUE},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To[int32](0),
+			ActiveDeadlineSeconds:   ptr.To[int64](KERNEL_PROBE_DEADLINE),
+			TTLSecondsAfterFinished: ptr.To[int32](KERNEL_PROBE_TTL_SECONDS),
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{KERNEL_PROBE_LABEL_KEY: KERNEL_PROBE_LABEL_VALUE},
+				},
+				Spec: v1.PodSpec{
+					NodeName:      node.Name,
+					RestartPolicy: v1.RestartPolicyNever,
+					Tolerations:   []v1.Toleration{{Operator: v1.TolerationOpExists}},
+					Containers: []v1.Container{
+						{
+							Name:            "probe",
+							Image:           image,
+							Command:         []string{"sh", "-c", KERNEL_PROBE_SCRIPT},
+							SecurityContext: &v1.SecurityContext{Privileged: ptr.To(true)},
+							VolumeMounts: []v1.VolumeMount{
+								{Name: "sys", MountPath: KERNEL_PROBE_HOST_PATH + "/sys", ReadOnly: true},
+								{Name: "boot", MountPath: KERNEL_PROBE_HOST_PATH + "/boot", ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []v1.Volume{
+						{Name: "sys", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/sys", Type: &hostPathType}}},
+						{Name: "boot", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/boot"}}},
+					},
+				},
+			},
+		},
+	}
+
+	return kubeClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+}
+
+// GetKernelProbeResult returns the capabilities reported by a kernel probe job, or ErrKernelProbeNotCompleted while it runs
+func (kubeClient *Client) GetKernelProbeResult(ctx context.Context, job *batchv1.Job) (*KernelCapabilities, error) {
+	var err error
+
+	if job, err = kubeClient.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	if job.Status.Failed > 0 {
+		return nil, fmt.Errorf(KERNEL_PROBE_FAILURE_FORMAT, job.Spec.Template.Spec.NodeName, jobFailureReason(job))
+	}
+
+	if job.Status.Succeeded == 0 {
+		return nil, ErrKernelProbeNotCompleted
+	}
+
+	var podList *v1.PodList
+	listOptions := metav1.ListOptions{LabelSelector: fmt.Sprintf("job-name=%s", job.Name)}
+	if podList, err = kubeClient.CoreV1().Pods(job.Namespace).List(ctx, listOptions); err != nil {
+		return nil, err
+	}
+
+	if len(podList.Items) == 0 {
+		return nil, fmt.Errorf(KERNEL_PROBE_FAILURE_FORMAT, job.Spec.Template.Spec.NodeName, "probe pod not found")
+	}
+
+	var logs []byte
+	if logs, err = kubeClient.CoreV1().Pods(job.Namespace).GetLogs(podList.Items[0].Name, &v1.PodLogOptions{}).DoRaw(ctx); err != nil {
+		return nil, err
+	}
+
+	return ParseKernelCapabilities(string(logs))
+}
+
+// NamespaceExists returns whether the namespace exists, without creating it
+func (kubeClient *Client) NamespaceExists(ctx context.Context, namespace string) (bool, error) {
+	var err error
+
+	if _, err = kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// EnsureNamespace creates the namespace the kernel probes run in, unless it already exists
+func (kubeClient *Client) EnsureNamespace(ctx context.Context, namespace string) error {
+	var err error
+
+	if _, err = kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	namespaceObject := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	if _, err = kubeClient.CoreV1().Namespaces().Create(ctx, namespaceObject, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (kubeClient *Client) DeleteKernelProbeJob(ctx context.Context, job *batchv1.Job) error {
+	propagationPolicy := metav1.DeletePropagationBackground
+	return kubeClient.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+}
+
+func jobFailureReason(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			return joinReason(condition.Reason, condition.Message)
+		}
+	}
+
+	return "job failed"
+}
@@ -0,0 +1,197 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	PROBE_NAMESPACE = "groundcover"
+	PROBE_OUTPUT    = "kernel=4.18.0-477.el8.x86_64\nbtf=true\ncgroup=2\nlockdown=none\nprogram_types=socket_filter,kprobe,tracepoint,raw_tracepoint,perf_event\n"
+)
+
+type KubeProbeTestSuite struct {
+	suite.Suite
+}
+
+func TestKubeProbeTestSuite(t *testing.T) {
+	suite.Run(t, &KubeProbeTestSuite{})
+}
+
+func (suite *KubeProbeTestSuite) TestParseKernelCapabilitiesSuccess() {
+	//act
+	capabilities, err := k8s.ParseKernelCapabilities(PROBE_OUTPUT)
+	suite.NoError(err)
+
+	// assert
+	expected := &k8s.KernelCapabilities{
+		Kernel:        "4.18.0-477.el8.x86_64",
+		BTF:           true,
+		CgroupVersion: 2,
+		Lockdown:      "none",
+		ProgramTypes:  []string{"socket_filter", "kprobe", "tracepoint", "raw_tracepoint", "perf_event"},
+	}
+
+	suite.Equal(expected, capabilities)
+	suite.True(capabilities.SupportsStableMode())
+}
+
+func (suite *KubeProbeTestSuite) TestParseKernelCapabilitiesInvalid() {
+	//act
+	_, missingKernelErr := k8s.ParseKernelCapabilities("fake logs")
+	_, invalidBtfErr := k8s.ParseKernelCapabilities("kernel=5.4.0\nbtf=maybe")
+
+	// assert
+	suite.EqualError(missingKernelErr, "invalid kernel probe output \"fake logs\"")
+	suite.EqualError(invalidBtfErr, "invalid kernel probe btf value \"maybe\"")
+}
+
+func (suite *KubeProbeTestSuite) TestParseKernelCapabilitiesUnknownProgramTypes() {
+	//act
+	capabilities, err := k8s.ParseKernelCapabilities("kernel=6.1.0-cos\nbtf=true\ncgroup=2\nlockdown=none\nprogram_types=unknown\n")
+	suite.NoError(err)
+
+	// assert
+	suite.True(capabilities.ProgramTypesUnknown)
+	suite.Empty(capabilities.ProgramTypes)
+}
+
+func (suite *KubeProbeTestSuite) TestSupportsStableModeUnsupported() {
+	//prepare
+	capabilities, err := k8s.ParseKernelCapabilities(PROBE_OUTPUT)
+	suite.NoError(err)
+
+	noBtf := *capabilities
+	noBtf.BTF = false
+
+	lockedDown := *capabilities
+	lockedDown.Lockdown = k8s.CONFIDENTIALITY_LOCKDOWN
+
+	noTracing := *capabilities
+	noTracing.ProgramTypes = []string{"socket_filter"}
+
+	// assert
+	suite.False(noBtf.SupportsStableMode())
+	suite.False(lockedDown.SupportsStableMode())
+	suite.False(noTracing.SupportsStableMode())
+}
+
+func (suite *KubeProbeTestSuite) TestKernelProbePoolsSuccess() {
+	//prepare
+	nodesReport := &k8s.NodesReport{
+		CompatibleNodes: []*k8s.NodeSummary{
+			{Name: "rhel-1", Kernel: "4.18.0", OSImage: "rhel", Architecture: "amd64"},
+			{Name: "ubuntu-1", Kernel: "5.15.0", OSImage: "ubuntu", Architecture: "amd64"},
+			{Name: "rhel-2", Kernel: "4.18.0", OSImage: "rhel", Architecture: "amd64"},
+		},
+		TaintedNodes: []*k8s.IncompatibleNode{
+			{NodeSummary: &k8s.NodeSummary{Name: "rhel-arm", Kernel: "4.18.0", OSImage: "rhel", Architecture: "arm64"}},
+		},
+		IncompatibleNodes: []*k8s.IncompatibleNode{
+			{NodeSummary: &k8s.NodeSummary{Name: "windows", Kernel: "10.0.17763", OSImage: "windows", Architecture: "amd64"}},
+		},
+	}
+
+	//act
+	pools := nodesReport.KernelProbePools()
+
+	// assert
+	var poolsNames [][]string
+	for _, pool := range pools {
+		var names []string
+		for _, node := range pool {
+			names = append(names, node.Name)
+		}
+		poolsNames = append(poolsNames, names)
+	}
+
+	suite.Equal([][]string{{"rhel-1", "rhel-2"}, {"ubuntu-1"}, {"rhel-arm"}}, poolsNames)
+}
+
+func (suite *KubeProbeTestSuite) TestCreateKernelProbeJobSuccess() {
+	//prepare
+	ctx := context.Background()
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset()}
+
+	//act
+	err := kubeClient.EnsureNamespace(ctx, PROBE_NAMESPACE)
+	suite.NoError(err)
+
+	job, err := kubeClient.CreateKernelProbeJob(ctx, PROBE_NAMESPACE, "busybox", &k8s.NodeSummary{Name: "node"})
+	suite.NoError(err)
+
+	// assert
+	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, PROBE_NAMESPACE, metav1.GetOptions{})
+	suite.NoError(err)
+
+	podSpec := job.Spec.Template.Spec
+	suite.Equal("node", podSpec.NodeName)
+	suite.Equal(v1.RestartPolicyNever, podSpec.RestartPolicy)
+	suite.Equal([]v1.Toleration{{Operator: v1.TolerationOpExists}}, podSpec.Tolerations)
+	suite.True(*podSpec.Containers[0].SecurityContext.Privileged)
+	suite.Equal([]string{"sh", "-c", k8s.KERNEL_PROBE_SCRIPT}, podSpec.Containers[0].Command)
+}
+
+func (suite *KubeProbeTestSuite) TestNamespaceExists() {
+	//prepare
+	ctx := context.Background()
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: PROBE_NAMESPACE}})}
+
+	//act
+	exists, existsErr := kubeClient.NamespaceExists(ctx, PROBE_NAMESPACE)
+	missing, missingErr := kubeClient.NamespaceExists(ctx, "missing")
+
+	// assert
+	suite.NoError(existsErr)
+	suite.NoError(missingErr)
+	suite.True(exists)
+	suite.False(missing)
+}
+
+func (suite *KubeProbeTestSuite) TestGetKernelProbeResultNotCompleted() {
+	//prepare
+	ctx := context.Background()
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(probeJob(batchv1.JobStatus{Active: 1}))}
+
+	//act
+	_, err := kubeClient.GetKernelProbeResult(ctx, probeJob(batchv1.JobStatus{}))
+
+	// assert
+	suite.ErrorIs(err, k8s.ErrKernelProbeNotCompleted)
+}
+
+func (suite *KubeProbeTestSuite) TestGetKernelProbeResultFailed() {
+	//prepare
+	ctx := context.Background()
+
+	status := batchv1.JobStatus{
+		Failed: 1,
+		Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: v1.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"},
+		},
+	}
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(probeJob(status))}
+
+	//act
+	_, err := kubeClient.GetKernelProbeResult(ctx, probeJob(batchv1.JobStatus{}))
+
+	// assert
+	suite.EqualError(err, "kernel probe on node node failed: DeadlineExceeded (Job was active longer than specified deadline)")
+}
+
+func probeJob(status batchv1.JobStatus) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "groundcover-kernel-probe-abcde", Namespace: PROBE_NAMESPACE},
+		Spec: batchv1.JobSpec{
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{NodeName: "node"}},
+		},
+		Status: status,
+	}
+}
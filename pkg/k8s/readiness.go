@@ -0,0 +1,339 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	DEPLOYMENT_KIND  = "Deployment"
+	STATEFULSET_KIND = "StatefulSet"
+	DAEMONSET_KIND   = "DaemonSet"
+
+	WORKLOAD_STATUS_FORMAT   = "%s/%s %d/%d"
+	POD_FAILURE_FORMAT       = "pod %s: %s"
+	CONTAINER_FAILURE_FORMAT = "pod %s container %s: %s"
+	UNSCHEDULABLE_REASON     = "Unschedulable"
+)
+
+var (
+	ErrWorkloadFailed = errors.New("workload failed")
+
+	WorkloadKinds = []string{DEPLOYMENT_KIND, STATEFULSET_KIND, DAEMONSET_KIND}
+
+	// container waiting reasons which won't resolve without a change to the release or the cluster
+	containerFailureReasons = []string{
+		"ImagePullBackOff",
+		"ErrImagePull",
+		"InvalidImageName",
+		"CrashLoopBackOff",
+		"CreateContainerConfigError",
+		"CreateContainerError",
+		"RunContainerError",
+	}
+)
+
+type Workload struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type WorkloadStatus struct {
+	Workload
+	Desired       int32  `json:"desired"`
+	Ready         int32  `json:"ready"`
+	Updated       int32  `json:"updated"`
+	IsReady       bool   `json:"isReady"`
+	IsFailed      bool   `json:"isFailed,omitempty"`
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+func (status *WorkloadStatus) String() string {
+	return fmt.Sprintf(WORKLOAD_STATUS_FORMAT, status.Kind, status.Name, status.Ready, status.Desired)
+}
+
+// WatchWorkloadsReadiness watches the given workloads and their pods, calling onChange with the statuses of all workloads on every change,
+// until all workloads are rolled out, a container of a workload is failing (ErrWorkloadFailed) or the context is done
+func (kubeClient *Client) WatchWorkloadsReadiness(ctx context.Context, namespace string, workloads []Workload, onChange func([]*WorkloadStatus)) error {
+	var err error
+
+	factory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0, informers.WithNamespace(namespace))
+	deploymentsInformer := factory.Apps().V1().Deployments()
+	statefulSetsInformer := factory.Apps().V1().StatefulSets()
+	daemonSetsInformer := factory.Apps().V1().DaemonSets()
+	podsInformer := factory.Core().V1().Pods()
+
+	changes := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	}
+
+	for _, informer := range []cache.SharedIndexInformer{
+		deploymentsInformer.Informer(),
+		statefulSetsInformer.Informer(),
+		daemonSetsInformer.Informer(),
+		podsInformer.Informer(),
+	} {
+		if _, err = informer.AddEventHandler(handler); err != nil {
+			return err
+		}
+	}
+
+	stopCh := make(chan struct{})
+	defer factory.Shutdown()
+	defer close(stopCh)
+
+	factory.Start(stopCh)
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to watch %v: %w", informerType, ctx.Err())
+		}
+	}
+
+	for {
+		var pods []*v1.Pod
+		if pods, err = podsInformer.Lister().Pods(namespace).List(labels.Everything()); err != nil {
+			return err
+		}
+
+		statuses := make([]*WorkloadStatus, 0, len(workloads))
+		for _, workload := range workloads {
+			var status *WorkloadStatus
+
+			switch workload.Kind {
+			case DEPLOYMENT_KIND:
+				deployment, _ := deploymentsInformer.Lister().Deployments(namespace).Get(workload.Name)
+				status = DeploymentStatus(workload, deployment, pods)
+			case STATEFULSET_KIND:
+				statefulSet, _ := statefulSetsInformer.Lister().StatefulSets(namespace).Get(workload.Name)
+				status = StatefulSetStatus(workload, statefulSet, pods)
+			case DAEMONSET_KIND:
+				daemonSet, _ := daemonSetsInformer.Lister().DaemonSets(namespace).Get(workload.Name)
+				status = DaemonSetStatus(workload, daemonSet, pods)
+			default:
+				return fmt.Errorf("unsupported workload kind %s", workload.Kind)
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		onChange(statuses)
+
+		if IsAnyWorkloadFailed(statuses) {
+			return ErrWorkloadFailed
+		}
+
+		if IsAllWorkloadsRolledOut(statuses) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changes:
+		}
+	}
+}
+
+func IsAllWorkloadsReady(statuses []*WorkloadStatus) bool {
+	for _, status := range statuses {
+		if !status.IsReady {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsAllWorkloadsRolledOut returns whether all the deployments and statefulsets are ready. Daemonsets are left out,
+// their pods may stay pending on nodes without enough free resources, and their coverage is validated by counting the running sensors
+func IsAllWorkloadsRolledOut(statuses []*WorkloadStatus) bool {
+	for _, status := range statuses {
+		if status.Kind != DAEMONSET_KIND && !status.IsReady {
+			return false
+		}
+	}
+
+	return true
+}
+
+func IsAnyWorkloadFailed(statuses []*WorkloadStatus) bool {
+	for _, status := range statuses {
+		if status.IsFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FirstFailureReason returns the failure reason of the first failing workload, if any
+func FirstFailureReason(statuses []*WorkloadStatus) string {
+	for _, status := range statuses {
+		if status.FailureReason != "" {
+			return fmt.Sprintf("%s/%s %s", status.Kind, status.Name, status.FailureReason)
+		}
+	}
+
+	return ""
+}
+
+func DeploymentStatus(workload Workload, deployment *appsv1.Deployment, pods []*v1.Pod) *WorkloadStatus {
+	status := &WorkloadStatus{Workload: workload}
+	if deployment == nil {
+		return status
+	}
+
+	status.Desired = 1
+	if deployment.Spec.Replicas != nil {
+		status.Desired = *deployment.Spec.Replicas
+	}
+
+	status.Ready = deployment.Status.AvailableReplicas
+	status.Updated = deployment.Status.UpdatedReplicas
+	status.IsReady = deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas >= status.Desired &&
+		deployment.Status.Replicas == deployment.Status.UpdatedReplicas &&
+		deployment.Status.AvailableReplicas >= status.Desired
+
+	if !status.IsReady {
+		status.FailureReason, status.IsFailed = podsFailureReason(deployment.Spec.Selector, pods, true)
+	}
+
+	return status
+}
+
+func StatefulSetStatus(workload Workload, statefulSet *appsv1.StatefulSet, pods []*v1.Pod) *WorkloadStatus {
+	status := &WorkloadStatus{Workload: workload}
+	if statefulSet == nil {
+		return status
+	}
+
+	status.Desired = 1
+	if statefulSet.Spec.Replicas != nil {
+		status.Desired = *statefulSet.Spec.Replicas
+	}
+
+	status.Ready = statefulSet.Status.ReadyReplicas
+	status.Updated = statefulSet.Status.UpdatedReplicas
+	status.IsReady = statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+		statefulSet.Status.UpdatedReplicas >= status.Desired &&
+		statefulSet.Status.ReadyReplicas >= status.Desired
+
+	if !status.IsReady {
+		status.FailureReason, status.IsFailed = podsFailureReason(statefulSet.Spec.Selector, pods, true)
+	}
+
+	return status
+}
+
+func DaemonSetStatus(workload Workload, daemonSet *appsv1.DaemonSet, pods []*v1.Pod) *WorkloadStatus {
+	status := &WorkloadStatus{Workload: workload}
+	if daemonSet == nil {
+		return status
+	}
+
+	status.Desired = daemonSet.Status.DesiredNumberScheduled
+	status.Ready = daemonSet.Status.NumberAvailable
+	status.Updated = daemonSet.Status.UpdatedNumberScheduled
+	status.IsReady = daemonSet.Status.ObservedGeneration >= daemonSet.Generation &&
+		daemonSet.Status.UpdatedNumberScheduled >= status.Desired &&
+		daemonSet.Status.NumberAvailable >= status.Desired
+
+	if !status.IsReady {
+		// sensors are expected to stay pending on nodes without enough free resources, so only failing containers are reported
+		status.FailureReason, status.IsFailed = podsFailureReason(daemonSet.Spec.Selector, pods, false)
+	}
+
+	return status
+}
+
+// podsFailureReason returns the first failure reason of the selected pods, and whether it is a failing container which won't recover by waiting.
+// Failing containers take precedence over unschedulable pods, which are only reported when reportUnschedulable is set
+func podsFailureReason(labelSelector *metav1.LabelSelector, pods []*v1.Pod, reportUnschedulable bool) (string, bool) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return "", false
+	}
+
+	unschedulableReason := ""
+	for _, pod := range pods {
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		if reason := containerFailureReason(pod); reason != "" {
+			return reason, true
+		}
+
+		if unschedulableReason == "" && reportUnschedulable {
+			unschedulableReason = unschedulablePodReason(pod)
+		}
+	}
+
+	return unschedulableReason, false
+}
+
+// PodFailureReason returns why a pod won't become ready without a change to the release or the cluster, if it is failing
+func PodFailureReason(pod *v1.Pod) string {
+	if reason := containerFailureReason(pod); reason != "" {
+		return reason
+	}
+
+	return unschedulablePodReason(pod)
+}
+
+func containerFailureReason(pod *v1.Pod) string {
+	containerStatuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, containerStatus := range containerStatuses {
+		waiting := containerStatus.State.Waiting
+		if waiting == nil || !slices.Contains(containerFailureReasons, waiting.Reason) {
+			continue
+		}
+
+		return fmt.Sprintf(CONTAINER_FAILURE_FORMAT, pod.Name, containerStatus.Name, joinReason(waiting.Reason, waiting.Message))
+	}
+
+	return ""
+}
+
+func unschedulablePodReason(pod *v1.Pod) string {
+	if pod.Status.Phase != v1.PodPending {
+		return ""
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason == UNSCHEDULABLE_REASON {
+			return fmt.Sprintf(POD_FAILURE_FORMAT, pod.Name, joinReason(condition.Reason, condition.Message))
+		}
+	}
+
+	return ""
+}
+
+func joinReason(reason, message string) string {
+	if message == "" {
+		return reason
+	}
+
+	return fmt.Sprintf("%s (%s)", reason, strings.TrimSpace(message))
+}
@@ -0,0 +1,278 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const readinessNamespace = "groundcover"
+
+type KubeReadinessTestSuite struct {
+	suite.Suite
+}
+
+func TestKubeReadinessTestSuite(t *testing.T) {
+	suite.Run(t, &KubeReadinessTestSuite{})
+}
+
+func sensorDaemonSet(desired, available int32) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sensor",
+			Namespace: readinessNamespace,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "sensor"}},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: desired,
+			UpdatedNumberScheduled: desired,
+			NumberAvailable:        available,
+		},
+	}
+}
+
+func portalDeployment(replicas, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "portal",
+			Namespace: readinessNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "portal"}},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          replicas,
+			UpdatedReplicas:   replicas,
+			AvailableReplicas: available,
+		},
+	}
+}
+
+func (suite *KubeReadinessTestSuite) TestDaemonSetStatusImagePullFailure() {
+	//prepare
+	workload := k8s.Workload{Kind: k8s.DAEMONSET_KIND, Name: "sensor"}
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"app": "portal"}},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "portal", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-abc", Labels: map[string]string{"app": "sensor"}},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name: "sensor",
+						State: v1.ContainerState{
+							Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	//act
+	status := k8s.DaemonSetStatus(workload, sensorDaemonSet(2, 1), pods)
+
+	//assert
+	expected := &k8s.WorkloadStatus{
+		Workload:      workload,
+		Desired:       2,
+		Ready:         1,
+		Updated:       2,
+		IsReady:       false,
+		IsFailed:      true,
+		FailureReason: "pod sensor-abc container sensor: ImagePullBackOff (Back-off pulling image)",
+	}
+
+	suite.Equal(expected, status)
+	suite.Equal("DaemonSet/sensor 1/2", status.String())
+	suite.Equal("DaemonSet/sensor pod sensor-abc container sensor: ImagePullBackOff (Back-off pulling image)", k8s.FirstFailureReason([]*k8s.WorkloadStatus{status}))
+}
+
+func (suite *KubeReadinessTestSuite) TestDeploymentStatusUnschedulable() {
+	//prepare
+	workload := k8s.Workload{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"}
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "portal-abc", Labels: map[string]string{"app": "portal"}},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				Conditions: []v1.PodCondition{
+					{
+						Type:    v1.PodScheduled,
+						Status:  v1.ConditionFalse,
+						Reason:  k8s.UNSCHEDULABLE_REASON,
+						Message: "0/3 nodes are available: 3 Insufficient memory.",
+					},
+				},
+			},
+		},
+	}
+
+	//act
+	status := k8s.DeploymentStatus(workload, portalDeployment(1, 0), pods)
+
+	//assert
+	suite.False(status.IsReady)
+	suite.False(status.IsFailed)
+	suite.Equal("pod portal-abc: Unschedulable (0/3 nodes are available: 3 Insufficient memory.)", status.FailureReason)
+}
+
+func (suite *KubeReadinessTestSuite) TestDaemonSetStatusUnschedulableIgnored() {
+	//prepare
+	workload := k8s.Workload{Kind: k8s.DAEMONSET_KIND, Name: "sensor"}
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sensor-abc", Labels: map[string]string{"app": "sensor"}},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: k8s.UNSCHEDULABLE_REASON, Message: "0/3 nodes are available: 1 Insufficient cpu."},
+				},
+			},
+		},
+	}
+
+	//act
+	status := k8s.DaemonSetStatus(workload, sensorDaemonSet(2, 1), pods)
+
+	//assert
+	suite.False(status.IsReady)
+	suite.False(status.IsFailed)
+	suite.Empty(status.FailureReason)
+}
+
+func (suite *KubeReadinessTestSuite) TestStatefulSetStatusMissing() {
+	//prepare
+	workload := k8s.Workload{Kind: k8s.STATEFULSET_KIND, Name: "clickhouse"}
+
+	//act
+	status := k8s.StatefulSetStatus(workload, nil, nil)
+
+	//assert
+	suite.Equal(&k8s.WorkloadStatus{Workload: workload}, status)
+}
+
+func (suite *KubeReadinessTestSuite) TestWatchWorkloadsReadinessReady() {
+	//prepare
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_CONTEXT_TIMEOUT)
+	defer cancel()
+
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(sensorDaemonSet(3, 3), portalDeployment(1, 1))}
+
+	workloads := []k8s.Workload{
+		{Kind: k8s.DAEMONSET_KIND, Name: "sensor"},
+		{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"},
+	}
+
+	var statuses []*k8s.WorkloadStatus
+	onChange := func(workloadsStatuses []*k8s.WorkloadStatus) {
+		statuses = workloadsStatuses
+	}
+
+	//act
+	err := kubeClient.WatchWorkloadsReadiness(ctx, readinessNamespace, workloads, onChange)
+
+	//assert
+	suite.NoError(err)
+	suite.Len(statuses, 2)
+	suite.True(k8s.IsAllWorkloadsReady(statuses))
+}
+
+func (suite *KubeReadinessTestSuite) TestWatchWorkloadsReadinessPendingSensors() {
+	//prepare
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_CONTEXT_TIMEOUT)
+	defer cancel()
+
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(sensorDaemonSet(3, 2), portalDeployment(1, 1))}
+
+	workloads := []k8s.Workload{
+		{Kind: k8s.DAEMONSET_KIND, Name: "sensor"},
+		{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"},
+	}
+
+	var statuses []*k8s.WorkloadStatus
+	onChange := func(workloadsStatuses []*k8s.WorkloadStatus) {
+		statuses = workloadsStatuses
+	}
+
+	//act
+	err := kubeClient.WatchWorkloadsReadiness(ctx, readinessNamespace, workloads, onChange)
+
+	//assert
+	suite.NoError(err)
+	suite.False(k8s.IsAllWorkloadsReady(statuses))
+	suite.True(k8s.IsAllWorkloadsRolledOut(statuses))
+}
+
+func (suite *KubeReadinessTestSuite) TestWatchWorkloadsReadinessFailed() {
+	//prepare
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_CONTEXT_TIMEOUT)
+	defer cancel()
+
+	crashingPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "portal-abc", Namespace: readinessNamespace, Labels: map[string]string{"app": "portal"}},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "portal", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			},
+		},
+	}
+
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(portalDeployment(1, 0), crashingPod)}
+
+	workloads := []k8s.Workload{
+		{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"},
+	}
+
+	var statuses []*k8s.WorkloadStatus
+	onChange := func(workloadsStatuses []*k8s.WorkloadStatus) {
+		statuses = workloadsStatuses
+	}
+
+	//act
+	err := kubeClient.WatchWorkloadsReadiness(ctx, readinessNamespace, workloads, onChange)
+
+	//assert
+	suite.ErrorIs(err, k8s.ErrWorkloadFailed)
+	suite.Equal("Deployment/portal pod portal-abc container portal: CrashLoopBackOff", k8s.FirstFailureReason(statuses))
+}
+
+func (suite *KubeReadinessTestSuite) TestWatchWorkloadsReadinessTimeout() {
+	//prepare
+	ctx, cancel := context.WithCancel(context.Background())
+
+	kubeClient := &k8s.Client{Interface: fake.NewSimpleClientset(portalDeployment(3, 1))}
+
+	workloads := []k8s.Workload{
+		{Kind: k8s.DEPLOYMENT_KIND, Name: "portal"},
+	}
+
+	var statuses []*k8s.WorkloadStatus
+	onChange := func(workloadsStatuses []*k8s.WorkloadStatus) {
+		statuses = workloadsStatuses
+		cancel()
+	}
+
+	//act
+	err := kubeClient.WatchWorkloadsReadiness(ctx, readinessNamespace, workloads, onChange)
+
+	//assert
+	suite.ErrorIs(err, context.Canceled)
+	suite.Equal("Deployment/portal 1/3", statuses[0].String())
+}
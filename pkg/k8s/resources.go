@@ -0,0 +1,154 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	AGENT_CPU_FIT_REPORT_MESSAGE_FORMAT    = "Sufficient free node CPU for the agent requests %s (%d/%d Nodes)"
+	AGENT_MEMORY_FIT_REPORT_MESSAGE_FORMAT = "Sufficient free node memory for the agent requests %s (%d/%d Nodes)"
+)
+
+// CalcNodesFreeResources sets the free cpu and memory of each node, its allocatable resources minus the requests of the pods scheduled on it.
+// Pods matching ignoredPods are left out, e.g. the agent pods which are replaced by an upgrade
+func (kubeClient *Client) CalcNodesFreeResources(ctx context.Context, nodesSummaries []*NodeSummary, ignoredPods labels.Selector) error {
+	var err error
+
+	var podList *v1.PodList
+	if podList, err = kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{}); err != nil {
+		return err
+	}
+
+	requestedResources := make(map[string]v1.ResourceList)
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		if ignoredPods != nil && ignoredPods.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		nodeRequests, exists := requestedResources[pod.Spec.NodeName]
+		if !exists {
+			nodeRequests = v1.ResourceList{}
+			requestedResources[pod.Spec.NodeName] = nodeRequests
+		}
+
+		addResources(nodeRequests, podRequests(pod))
+	}
+
+	for _, nodeSummary := range nodesSummaries {
+		nodeRequests := requestedResources[nodeSummary.Name]
+		nodeSummary.FreeCPU = freeResource(nodeSummary.CPU, nodeRequests.Cpu())
+		nodeSummary.FreeMemory = freeResource(nodeSummary.Memory, nodeRequests.Memory())
+	}
+
+	return nil
+}
+
+// podRequests returns the pod effective requests, the larger of its containers requests sum and of each init container requests, plus its overhead
+func podRequests(pod v1.Pod) v1.ResourceList {
+	requests := v1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+
+	for _, initContainer := range pod.Spec.InitContainers {
+		for name, quantity := range initContainer.Resources.Requests {
+			if current, exists := requests[name]; !exists || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	addResources(requests, pod.Spec.Overhead)
+
+	return requests
+}
+
+func addResources(resources v1.ResourceList, added v1.ResourceList) {
+	for name, quantity := range added {
+		current := resources[name]
+		current.Add(quantity)
+		resources[name] = current
+	}
+}
+
+func freeResource(allocatable, requested *resource.Quantity) *resource.Quantity {
+	if allocatable == nil {
+		return nil
+	}
+
+	free := allocatable.DeepCopy()
+	free.Sub(*requested)
+
+	return &free
+}
+
+// ValidateAgentResources checks the given nodes have enough free cpu and memory for the agent requests and returns the ones which don't,
+// nodes without free resources are skipped
+func (nodesReport *NodesReport) ValidateAgentResources(nodes []*NodeSummary, requests v1.ResourceList) []*NodeSummary {
+	cpuRequest, hasCpuRequest := requests[v1.ResourceCPU]
+	memoryRequest, hasMemoryRequest := requests[v1.ResourceMemory]
+
+	nodesReport.AgentCPUFits = &Requirement{}
+	nodesReport.AgentMemoryFits = &Requirement{}
+
+	var validatedNodesCount int
+	var insufficientNodes []*NodeSummary
+	for _, node := range nodes {
+		if node.FreeCPU == nil || node.FreeMemory == nil {
+			continue
+		}
+
+		validatedNodesCount++
+		isSufficient := true
+
+		if hasCpuRequest && node.FreeCPU.Cmp(cpuRequest) < 0 {
+			isSufficient = false
+			nodesReport.AgentCPUFits.ErrorMessages = append(
+				nodesReport.AgentCPUFits.ErrorMessages,
+				fmt.Sprintf("node: %s - %s free cpu", node.Name, node.FreeCPU),
+			)
+		}
+
+		if hasMemoryRequest && node.FreeMemory.Cmp(memoryRequest) < 0 {
+			isSufficient = false
+			nodesReport.AgentMemoryFits.ErrorMessages = append(
+				nodesReport.AgentMemoryFits.ErrorMessages,
+				fmt.Sprintf("node: %s - %s free memory", node.Name, node.FreeMemory),
+			)
+		}
+
+		if !isSufficient {
+			insufficientNodes = append(insufficientNodes, node)
+		}
+	}
+
+	nodesReport.AgentCPUFits.IsCompatible = len(nodesReport.AgentCPUFits.ErrorMessages) == 0
+	nodesReport.AgentCPUFits.IsNonCompatible = validatedNodesCount > 0 && len(nodesReport.AgentCPUFits.ErrorMessages) == validatedNodesCount
+	nodesReport.AgentCPUFits.Message = fmt.Sprintf(
+		AGENT_CPU_FIT_REPORT_MESSAGE_FORMAT,
+		&cpuRequest,
+		validatedNodesCount-len(nodesReport.AgentCPUFits.ErrorMessages),
+		validatedNodesCount,
+	)
+
+	nodesReport.AgentMemoryFits.IsCompatible = len(nodesReport.AgentMemoryFits.ErrorMessages) == 0
+	nodesReport.AgentMemoryFits.IsNonCompatible = validatedNodesCount > 0 && len(nodesReport.AgentMemoryFits.ErrorMessages) == validatedNodesCount
+	nodesReport.AgentMemoryFits.Message = fmt.Sprintf(
+		AGENT_MEMORY_FIT_REPORT_MESSAGE_FORMAT,
+		&memoryRequest,
+		validatedNodesCount-len(nodesReport.AgentMemoryFits.ErrorMessages),
+		validatedNodesCount,
+	)
+
+	return insufficientNodes
+}
@@ -0,0 +1,123 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type KubeResourcesTestSuite struct {
+	suite.Suite
+	KubeClient k8s.Client
+}
+
+func (suite *KubeResourcesTestSuite) SetupTest() {
+	suite.KubeClient = k8s.Client{
+		Interface: fake.NewSimpleClientset(
+			resourcesPod("app", "dense", v1.PodRunning, "1500m", "3Gi", map[string]string{"app": "api"}),
+			resourcesPod("init", "dense", v1.PodRunning, "", "", nil),
+			resourcesPod("completed", "dense", v1.PodSucceeded, "2", "4Gi", nil),
+			resourcesPod("sensor", "dense", v1.PodRunning, "500m", "512Mi", map[string]string{"app": "sensor"}),
+			resourcesPod("pending", "", v1.PodPending, "2", "4Gi", nil),
+		),
+	}
+}
+
+func TestKubeResourcesTestSuite(t *testing.T) {
+	suite.Run(t, &KubeResourcesTestSuite{})
+}
+
+func resourcesPod(name, nodeName string, phase v1.PodPhase, cpu, memory string, podLabels map[string]string) *v1.Pod {
+	requests := v1.ResourceList{}
+	if cpu != "" {
+		requests[v1.ResourceCPU] = resource.MustParse(cpu)
+		requests[v1.ResourceMemory] = resource.MustParse(memory)
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels},
+		Spec: v1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []v1.Container{{Name: "main", Resources: v1.ResourceRequirements{Requests: requests}}},
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+
+	if name == "init" {
+		pod.Spec.InitContainers = []v1.Container{
+			{
+				Name: "init",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+				},
+			},
+		}
+	}
+
+	return pod
+}
+
+func quantity(value string) *resource.Quantity {
+	parsed := resource.MustParse(value)
+	return &parsed
+}
+
+func (suite *KubeResourcesTestSuite) TestCalcNodesFreeResourcesSuccess() {
+	// prepare
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_CONTEXT_TIMEOUT)
+	defer cancel()
+
+	dense := &k8s.NodeSummary{Name: "dense", CPU: quantity("2"), Memory: quantity("4Gi")}
+	empty := &k8s.NodeSummary{Name: "empty", CPU: quantity("2"), Memory: quantity("4Gi")}
+
+	ignoredPods, err := labels.Parse("app=sensor")
+	suite.NoError(err)
+
+	// act
+	err = suite.KubeClient.CalcNodesFreeResources(ctx, []*k8s.NodeSummary{dense, empty}, ignoredPods)
+
+	// assert
+	suite.NoError(err)
+	suite.Equal(int64(400), dense.FreeCPU.MilliValue())
+	suite.Equal(quantity("1Gi").Value(), dense.FreeMemory.Value())
+	suite.Equal(int64(2000), empty.FreeCPU.MilliValue())
+	suite.Equal(quantity("4Gi").Value(), empty.FreeMemory.Value())
+}
+
+func (suite *KubeResourcesTestSuite) TestValidateAgentResourcesInsufficient() {
+	// prepare
+	dense := &k8s.NodeSummary{Name: "dense", FreeCPU: quantity("400m"), FreeMemory: quantity("1Gi")}
+	spacious := &k8s.NodeSummary{Name: "spacious", FreeCPU: quantity("2"), FreeMemory: quantity("4Gi")}
+	unknown := &k8s.NodeSummary{Name: "unknown"}
+
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("500m"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+
+	nodesReport := &k8s.NodesReport{}
+
+	// act
+	insufficientNodes := nodesReport.ValidateAgentResources([]*k8s.NodeSummary{dense, spacious, unknown}, requests)
+
+	// assert
+	suite.Equal([]*k8s.NodeSummary{dense}, insufficientNodes)
+
+	suite.Equal(&k8s.Requirement{
+		IsCompatible:  false,
+		Message:       "Sufficient free node CPU for the agent requests 500m (1/2 Nodes)",
+		ErrorMessages: []string{"node: dense - 400m free cpu"},
+	}, nodesReport.AgentCPUFits)
+
+	suite.Equal(&k8s.Requirement{
+		IsCompatible: true,
+		Message:      "Sufficient free node memory for the agent requests 1Gi (2/2 Nodes)",
+	}, nodesReport.AgentMemoryFits)
+}
@@ -3,10 +3,13 @@ package k8s
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/blang/semver/v4"
 	v1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
 )
 
 const (
@@ -56,6 +59,26 @@ func (clusterRequirements ClusterRequirements) validateStorage(ctx context.Conte
 	return requirement
 }
 
+func (clusterRequirements ClusterRequirements) validateStorageProvisioner(storageClass *v1.StorageClass) Requirement {
+	var requirement Requirement
+	requirement.Message = CLUSTER_STORAGE_PROVISIONER_ALLOWED
+
+	switch {
+	case storageClass == nil:
+		requirement.ErrorMessages = append(requirement.ErrorMessages, ErrNoDefaultStorageClass.Error())
+	case !slices.Contains(clusterRequirements.StorageProvisioners, storageClass.Provisioner):
+		requirement.ErrorMessages = append(
+			requirement.ErrorMessages,
+			fmt.Sprintf("storage class %s provisioner %s isn't one of: %s", storageClass.Name, storageClass.Provisioner, strings.Join(clusterRequirements.StorageProvisioners, ", ")),
+		)
+	}
+
+	requirement.IsCompatible = len(requirement.ErrorMessages) == 0
+	requirement.IsNonCompatible = len(requirement.ErrorMessages) > 0
+
+	return requirement
+}
+
 func (kubeClient *Client) GetDefaultStorageClass(ctx context.Context) (*v1.StorageClass, error) {
 	storageClassList, err := kubeClient.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
 	if err != nil {
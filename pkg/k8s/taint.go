@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"golang.org/x/exp/maps"
@@ -105,6 +106,91 @@ func (manager TolerationManager) GetTolerableNodes(allowedTaints []string) ([]*N
 	return tolerableNodes, nil
 }
 
+func (manager TolerationManager) GetTolerableNodesByTolerations(tolerations []v1.Toleration) []*NodeSummary {
+	var tolerableNodes []*NodeSummary
+
+	for _, taintedNode := range manager.TaintedNodes {
+		if isToleratingTaints(tolerations, taintedNode.Taints) {
+			tolerableNodes = append(tolerableNodes, taintedNode.NodeSummary)
+		}
+	}
+
+	return tolerableNodes
+}
+
+func isToleratingTaints(tolerations []v1.Toleration, taints []v1.Taint) bool {
+	for _, taint := range taints {
+		if isBuiltinTaint(taint) {
+			continue
+		}
+
+		tolerated := slices.ContainsFunc(tolerations, func(toleration v1.Toleration) bool {
+			return toleration.ToleratesTaint(&taint)
+		})
+
+		if !tolerated {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetTaintsToleratedBy returns the taints, in GetTaints format, tolerated by any of the given tolerations
+func (manager TolerationManager) GetTaintsToleratedBy(tolerations []v1.Toleration) ([]string, error) {
+	var err error
+
+	var taints []string
+	if taints, err = manager.GetTaints(); err != nil {
+		return nil, err
+	}
+
+	toleratedTaints := make([]string, 0, len(taints))
+	for _, taintMarshaled := range taints {
+		var taint v1.Taint
+		if err = json.Unmarshal([]byte(taintMarshaled), &taint); err != nil {
+			return nil, err
+		}
+
+		if isToleratingTaints(tolerations, []v1.Taint{taint}) {
+			toleratedTaints = append(toleratedTaints, taintMarshaled)
+		}
+	}
+
+	return toleratedTaints, nil
+}
+
+// ParseToleration parses a key[=value][:effect] taint spec into a toleration,
+// a missing value tolerates any value and a missing effect tolerates any effect
+func ParseToleration(spec string) (v1.Toleration, error) {
+	toleration := v1.Toleration{
+		Operator: v1.TolerationOpExists,
+	}
+
+	keyValue, effect, hasEffect := strings.Cut(spec, ":")
+	if hasEffect {
+		switch v1.TaintEffect(effect) {
+		case v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+			toleration.Effect = v1.TaintEffect(effect)
+		default:
+			return toleration, fmt.Errorf("invalid taint effect %q in %q", effect, spec)
+		}
+	}
+
+	key, value, hasValue := strings.Cut(keyValue, "=")
+	if key == "" {
+		return toleration, fmt.Errorf("invalid taint %q, expected key[=value][:effect]", spec)
+	}
+
+	toleration.Key = key
+	if hasValue {
+		toleration.Operator = v1.TolerationOpEqual
+		toleration.Value = value
+	}
+
+	return toleration, nil
+}
+
 func (validator TolerationManager) marshalTaint(taint v1.Taint) (string, error) {
 	var err error
 
@@ -131,3 +131,96 @@ func (suite *KubeTaintTestSuite) TestGetTolerableNodesSuccess() {
 
 	suite.Equal(expected, nodes)
 }
+
+func (suite *KubeTaintTestSuite) TestGetTolerableNodesByTolerationsSuccess() {
+	// prepare
+	tolerationManager := &k8s.TolerationManager{
+		TaintedNodes: suite.TaintedNodes,
+	}
+
+	// act
+	tolerations := []v1.Toleration{
+		{
+			Key:      "test",
+			Operator: v1.TolerationOpEqual,
+			Value:    "test",
+			Effect:   v1.TaintEffectNoSchedule,
+		},
+		{
+			Key:      "good",
+			Operator: v1.TolerationOpExists,
+		},
+	}
+
+	nodes := tolerationManager.GetTolerableNodesByTolerations(tolerations)
+
+	// assert
+	expected := []*k8s.NodeSummary{
+		suite.TaintedNodes[0].NodeSummary,
+	}
+
+	suite.Equal(expected, nodes)
+}
+
+func (suite *KubeTaintTestSuite) TestGetTolerableNodesByTolerationsTolerateAll() {
+	// prepare
+	tolerationManager := &k8s.TolerationManager{
+		TaintedNodes: suite.TaintedNodes,
+	}
+
+	// act
+	nodes := tolerationManager.GetTolerableNodesByTolerations([]v1.Toleration{{Operator: v1.TolerationOpExists}})
+
+	// assert
+	suite.Len(nodes, len(suite.TaintedNodes))
+}
+
+func (suite *KubeTaintTestSuite) TestGetTaintsToleratedBySuccess() {
+	// prepare
+	tolerationManager := &k8s.TolerationManager{
+		TaintedNodes: suite.TaintedNodes,
+	}
+
+	tolerations := []v1.Toleration{
+		{
+			Key:      "test",
+			Operator: v1.TolerationOpExists,
+		},
+		{
+			Key:      "bad",
+			Operator: v1.TolerationOpEqual,
+			Value:    "other",
+			Effect:   v1.TaintEffectNoSchedule,
+		},
+	}
+
+	// act
+	taints, err := tolerationManager.GetTaintsToleratedBy(tolerations)
+	suite.NoError(err)
+
+	// assert
+	suite.Equal([]string{"{\"key\":\"test\",\"value\":\"test\",\"effect\":\"NoSchedule\"}"}, taints)
+}
+
+func (suite *KubeTaintTestSuite) TestParseTolerationSuccess() {
+	// act
+	keyValueEffect, err := k8s.ParseToleration("dedicated=gpu:NoSchedule")
+	suite.NoError(err)
+
+	keyOnly, err := k8s.ParseToleration("dedicated")
+	suite.NoError(err)
+
+	// assert
+	suite.Equal(v1.Toleration{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu", Effect: v1.TaintEffectNoSchedule}, keyValueEffect)
+	suite.Equal(v1.Toleration{Key: "dedicated", Operator: v1.TolerationOpExists}, keyOnly)
+}
+
+func (suite *KubeTaintTestSuite) TestParseTolerationInvalid() {
+	// act
+	_, invalidEffectErr := k8s.ParseToleration("dedicated=gpu:Never")
+	_, missingKeyErr := k8s.ParseToleration("=gpu")
+
+	// assert
+	suite.EqualError(invalidEffectErr, "invalid taint effect \"Never\" in \"dedicated=gpu:Never\"")
+	suite.EqualError(missingKeyErr, "invalid taint \"=gpu\", expected key[=value][:effect]")
+}
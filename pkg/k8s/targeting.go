@@ -0,0 +1,362 @@
+package k8s
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	NODE_NAME_FIELD        = "metadata.name"
+	OS_LABEL               = "kubernetes.io/os"
+	ARCH_LABEL             = "kubernetes.io/arch"
+	EKS_COMPUTE_TYPE_LABEL = "eks.amazonaws.com/compute-type"
+	FARGATE_PROVIDER       = "fargate"
+)
+
+// NodeTargeting selects the nodes the agent is deployed on, nodes must match Selector (when set),
+// mustn't match ExcludeSelector (when set) and mustn't be one of ExcludedNodes
+type NodeTargeting struct {
+	Selector        labels.Selector
+	ExcludeSelector labels.Selector
+	ExcludedNodes   []string
+}
+
+func (targeting *NodeTargeting) IsEmpty() bool {
+	return targeting.Selector == nil && targeting.ExcludeSelector == nil && len(targeting.ExcludedNodes) == 0
+}
+
+func (targeting *NodeTargeting) Matches(node *NodeSummary) bool {
+	nodeLabels := labels.Set(node.Labels)
+
+	if targeting.Selector != nil && !targeting.Selector.Matches(nodeLabels) {
+		return false
+	}
+
+	if targeting.ExcludeSelector != nil && targeting.ExcludeSelector.Matches(nodeLabels) {
+		return false
+	}
+
+	return !slices.Contains(targeting.ExcludedNodes, node.Name)
+}
+
+func (targeting *NodeTargeting) FilterNodes(nodes []*NodeSummary) []*NodeSummary {
+	var targetedNodes []*NodeSummary
+
+	for _, node := range nodes {
+		if targeting.Matches(node) {
+			targetedNodes = append(targetedNodes, node)
+		}
+	}
+
+	return targetedNodes
+}
+
+func (targeting *NodeTargeting) FilterTaintedNodes(nodes []*IncompatibleNode) []*IncompatibleNode {
+	var targetedNodes []*IncompatibleNode
+
+	for _, node := range nodes {
+		if targeting.Matches(node.NodeSummary) {
+			targetedNodes = append(targetedNodes, node)
+		}
+	}
+
+	return targetedNodes
+}
+
+// ExcludeUnsupportedNodes excludes the nodes with an unsupported operating system, architecture or provider and returns them,
+// along with the reasons they aren't supported.
+// Nodes are excluded by the well known labels, so new nodes of the same pools are excluded as well, and by name otherwise.
+// A label rule is only used when it excludes unsupported nodes without excluding any of the supported ones
+func (targeting *NodeTargeting) ExcludeUnsupportedNodes(nodeRequirements *NodeMinimumRequirements, nodesReport *NodesReport) ([]*IncompatibleNode, error) {
+	var err error
+
+	var unsupportedNodes []*IncompatibleNode
+	for _, node := range nodesReport.IncompatibleNodes {
+		if reasons := nodeRequirements.UnsupportedPlatformReasons(node.NodeSummary); len(reasons) > 0 {
+			unsupportedNodes = append(unsupportedNodes, &IncompatibleNode{NodeSummary: node.NodeSummary, RequirementErrors: reasons})
+		}
+	}
+
+	if len(unsupportedNodes) == 0 {
+		return nil, nil
+	}
+
+	supportedNodes := nodesReport.SupportedNodes()
+
+	var rules []*labels.Requirement
+	if rules, err = nodeRequirements.platformRules(); err != nil {
+		return nil, err
+	}
+
+	var requirements []labels.Requirement
+	for _, rule := range rules {
+		if matchesAllNodes(*rule, supportedNodes) && !matchesAllNodes(*rule, nodesSummaries(unsupportedNodes)) {
+			requirements = append(requirements, *rule)
+		}
+	}
+
+	selector := labels.NewSelector().Add(requirements...)
+	for _, node := range unsupportedNodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			targeting.ExcludedNodes = append(targeting.ExcludedNodes, node.Name)
+		}
+	}
+
+	if len(requirements) > 0 {
+		if targeting.Selector == nil {
+			targeting.Selector = selector
+		} else {
+			targeting.Selector = targeting.Selector.Add(requirements...)
+		}
+	}
+
+	return unsupportedNodes, nil
+}
+
+// UnsupportedPlatformReasons returns why the node provider, architecture or operating system isn't supported, if at all
+func (nodeRequirements *NodeMinimumRequirements) UnsupportedPlatformReasons(node *NodeSummary) []string {
+	var reasons []string
+
+	for _, err := range []error{
+		nodeRequirements.validateNodeProvider(node),
+		nodeRequirements.validateNodeArchitecture(node),
+		nodeRequirements.validateNodeOperatingSystem(node),
+	} {
+		if err != nil {
+			reasons = append(reasons, err.Error())
+		}
+	}
+
+	return reasons
+}
+
+// platformRules returns the label requirements matching the nodes with a supported platform
+func (nodeRequirements *NodeMinimumRequirements) platformRules() ([]*labels.Requirement, error) {
+	var err error
+
+	var rules []*labels.Requirement
+
+	var operatingSystemRule *labels.Requirement
+	if operatingSystemRule, err = labels.NewRequirement(OS_LABEL, selection.In, nodeRequirements.AllowedOperatingSystems); err != nil {
+		return nil, err
+	}
+	rules = append(rules, operatingSystemRule)
+
+	var architectureRule *labels.Requirement
+	if architectureRule, err = labels.NewRequirement(ARCH_LABEL, selection.In, nodeRequirements.AllowedArchitectures); err != nil {
+		return nil, err
+	}
+	rules = append(rules, architectureRule)
+
+	if slices.Contains(nodeRequirements.BlockedProviders, FARGATE_PROVIDER) {
+		var fargateRule *labels.Requirement
+		if fargateRule, err = labels.NewRequirement(EKS_COMPUTE_TYPE_LABEL, selection.NotIn, []string{FARGATE_PROVIDER}); err != nil {
+			return nil, err
+		}
+		rules = append(rules, fargateRule)
+	}
+
+	return rules, nil
+}
+
+func matchesAllNodes(requirement labels.Requirement, nodes []*NodeSummary) bool {
+	for _, node := range nodes {
+		if !requirement.Matches(labels.Set(node.Labels)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func nodesSummaries(nodes []*IncompatibleNode) []*NodeSummary {
+	summaries := make([]*NodeSummary, 0, len(nodes))
+	for _, node := range nodes {
+		summaries = append(summaries, node.NodeSummary)
+	}
+
+	return summaries
+}
+
+// Affinity returns the required node affinity scheduling only on the targeted nodes, or nil when all nodes are targeted.
+// Node selector terms are ORed, so each negated exclude requirement gets its own term along with the selector requirements
+func (targeting *NodeTargeting) Affinity() (*v1.Affinity, error) {
+	var err error
+
+	if targeting.IsEmpty() {
+		return nil, nil
+	}
+
+	var expressions []v1.NodeSelectorRequirement
+	if targeting.Selector != nil {
+		if expressions, err = selectorExpressions(targeting.Selector, false); err != nil {
+			return nil, err
+		}
+	}
+
+	// node field requirements accept a single value, requirements of a term are ANDed so each excluded node gets its own
+	var fields []v1.NodeSelectorRequirement
+	for _, excludedNode := range targeting.ExcludedNodes {
+		fields = append(fields, v1.NodeSelectorRequirement{
+			Key:      NODE_NAME_FIELD,
+			Operator: v1.NodeSelectorOpNotIn,
+			Values:   []string{excludedNode},
+		})
+	}
+
+	terms := []v1.NodeSelectorTerm{{MatchExpressions: expressions, MatchFields: fields}}
+
+	if targeting.ExcludeSelector != nil {
+		var excludeExpressions []v1.NodeSelectorRequirement
+		if excludeExpressions, err = selectorExpressions(targeting.ExcludeSelector, true); err != nil {
+			return nil, err
+		}
+
+		if len(excludeExpressions) == 0 {
+			return nil, fmt.Errorf("node selector %q excludes all nodes", targeting.ExcludeSelector.String())
+		}
+
+		terms = make([]v1.NodeSelectorTerm, 0, len(excludeExpressions))
+		for _, excludeExpression := range excludeExpressions {
+			termExpressions := append(append([]v1.NodeSelectorRequirement{}, expressions...), excludeExpression)
+			terms = append(terms, v1.NodeSelectorTerm{MatchExpressions: termExpressions, MatchFields: fields})
+		}
+	}
+
+	affinity := &v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: terms,
+			},
+		},
+	}
+
+	return affinity, nil
+}
+
+// selectorExpressions converts the selector requirements to node selector requirements, negating each one if requested
+func selectorExpressions(selector labels.Selector, negate bool) ([]v1.NodeSelectorRequirement, error) {
+	requirements, _ := selector.Requirements()
+
+	expressions := make([]v1.NodeSelectorRequirement, 0, len(requirements))
+	for _, requirement := range requirements {
+		expression := v1.NodeSelectorRequirement{
+			Key:    requirement.Key(),
+			Values: requirement.Values().List(),
+		}
+
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			expression.Operator = v1.NodeSelectorOpIn
+		case selection.NotEquals, selection.NotIn:
+			expression.Operator = v1.NodeSelectorOpNotIn
+		case selection.Exists:
+			expression.Operator = v1.NodeSelectorOpExists
+		case selection.DoesNotExist:
+			expression.Operator = v1.NodeSelectorOpDoesNotExist
+		case selection.GreaterThan:
+			expression.Operator = v1.NodeSelectorOpGt
+		case selection.LessThan:
+			expression.Operator = v1.NodeSelectorOpLt
+		}
+
+		if negate {
+			expression.Operator = negatedOperators[expression.Operator]
+		}
+
+		if expression.Operator == "" {
+			return nil, fmt.Errorf("unsupported operator %q in node selector %q", requirement.Operator(), selector.String())
+		}
+
+		if expression.Operator == v1.NodeSelectorOpExists || expression.Operator == v1.NodeSelectorOpDoesNotExist {
+			expression.Values = nil
+		}
+
+		expressions = append(expressions, expression)
+	}
+
+	return expressions, nil
+}
+
+var negatedOperators = map[v1.NodeSelectorOperator]v1.NodeSelectorOperator{
+	v1.NodeSelectorOpIn:           v1.NodeSelectorOpNotIn,
+	v1.NodeSelectorOpNotIn:        v1.NodeSelectorOpIn,
+	v1.NodeSelectorOpExists:       v1.NodeSelectorOpDoesNotExist,
+	v1.NodeSelectorOpDoesNotExist: v1.NodeSelectorOpExists,
+}
+
+// MatchesNodePlacement returns whether a pod with the given node selector and affinity can be scheduled on the node
+func MatchesNodePlacement(node *NodeSummary, nodeSelector map[string]string, affinity *v1.Affinity) (bool, error) {
+	var err error
+
+	nodeLabels := labels.Set(node.Labels)
+	if !labels.SelectorFromSet(nodeSelector).Matches(nodeLabels) {
+		return false, nil
+	}
+
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true, nil
+	}
+
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		var matches bool
+		if matches, err = matchesNodeSelectorTerm(node, term); err != nil {
+			return false, err
+		}
+
+		if matches {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchesNodeSelectorTerm(node *NodeSummary, term v1.NodeSelectorTerm) (bool, error) {
+	var err error
+
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false, nil
+	}
+
+	for _, expression := range term.MatchExpressions {
+		var requirement *labels.Requirement
+		if requirement, err = labels.NewRequirement(expression.Key, nodeSelectorOperators[expression.Operator], expression.Values); err != nil {
+			return false, err
+		}
+
+		if !requirement.Matches(labels.Set(node.Labels)) {
+			return false, nil
+		}
+	}
+
+	for _, field := range term.MatchFields {
+		if field.Key != NODE_NAME_FIELD {
+			return false, fmt.Errorf("unsupported node selector field %q", field.Key)
+		}
+
+		var requirement *labels.Requirement
+		if requirement, err = labels.NewRequirement(field.Key, nodeSelectorOperators[field.Operator], field.Values); err != nil {
+			return false, err
+		}
+
+		if !requirement.Matches(labels.Set{NODE_NAME_FIELD: node.Name}) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+var nodeSelectorOperators = map[v1.NodeSelectorOperator]selection.Operator{
+	v1.NodeSelectorOpIn:           selection.In,
+	v1.NodeSelectorOpNotIn:        selection.NotIn,
+	v1.NodeSelectorOpExists:       selection.Exists,
+	v1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	v1.NodeSelectorOpGt:           selection.GreaterThan,
+	v1.NodeSelectorOpLt:           selection.LessThan,
+}
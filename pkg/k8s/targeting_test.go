@@ -0,0 +1,306 @@
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/k8s"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type KubeTargetingTestSuite struct {
+	suite.Suite
+	Nodes []*k8s.NodeSummary
+}
+
+func (suite *KubeTargetingTestSuite) SetupSuite() {
+	suite.Nodes = []*k8s.NodeSummary{
+		{
+			Name:   "gpu",
+			Labels: map[string]string{"pool": "gpu", "kubernetes.io/os": "linux"},
+		},
+		{
+			Name:   "batch",
+			Labels: map[string]string{"pool": "batch", "spot": "true", "kubernetes.io/os": "linux"},
+		},
+		{
+			Name:   "general",
+			Labels: map[string]string{"pool": "general", "kubernetes.io/os": "linux"},
+		},
+		{
+			Name:   "windows",
+			Labels: map[string]string{"kubernetes.io/os": "windows"},
+		},
+	}
+}
+
+func TestKubeTargetingTestSuite(t *testing.T) {
+	suite.Run(t, &KubeTargetingTestSuite{})
+}
+
+func (suite *KubeTargetingTestSuite) TestFilterNodesSuccess() {
+	//prepare
+	targeting := &k8s.NodeTargeting{
+		Selector:        labels.SelectorFromSet(labels.Set{"kubernetes.io/os": "linux"}),
+		ExcludeSelector: labels.SelectorFromSet(labels.Set{"pool": "gpu"}),
+		ExcludedNodes:   []string{"batch"},
+	}
+
+	//act
+	nodes := targeting.FilterNodes(suite.Nodes)
+
+	// assert
+	suite.Equal([]*k8s.NodeSummary{suite.Nodes[2]}, nodes)
+}
+
+func (suite *KubeTargetingTestSuite) TestFilterTaintedNodesSuccess() {
+	//prepare
+	targeting := &k8s.NodeTargeting{
+		ExcludeSelector: labels.SelectorFromSet(labels.Set{"spot": "true"}),
+	}
+
+	taintedNodes := []*k8s.IncompatibleNode{
+		{NodeSummary: suite.Nodes[0]},
+		{NodeSummary: suite.Nodes[1]},
+	}
+
+	//act
+	nodes := targeting.FilterTaintedNodes(taintedNodes)
+
+	// assert
+	suite.Equal([]*k8s.IncompatibleNode{taintedNodes[0]}, nodes)
+}
+
+func (suite *KubeTargetingTestSuite) TestAffinityEmpty() {
+	//act
+	affinity, err := (&k8s.NodeTargeting{}).Affinity()
+
+	// assert
+	suite.NoError(err)
+	suite.Nil(affinity)
+}
+
+func (suite *KubeTargetingTestSuite) TestAffinitySuccess() {
+	//prepare
+	selector, err := labels.Parse("kubernetes.io/os=linux")
+	suite.NoError(err)
+
+	excludeSelector, err := labels.Parse("pool in (gpu,batch),!legacy")
+	suite.NoError(err)
+
+	targeting := &k8s.NodeTargeting{
+		Selector:        selector,
+		ExcludeSelector: excludeSelector,
+		ExcludedNodes:   []string{"general"},
+	}
+
+	//act
+	affinity, err := targeting.Affinity()
+	suite.NoError(err)
+
+	// assert
+	osExpression := v1.NodeSelectorRequirement{Key: "kubernetes.io/os", Operator: v1.NodeSelectorOpIn, Values: []string{"linux"}}
+	nameField := []v1.NodeSelectorRequirement{{Key: k8s.NODE_NAME_FIELD, Operator: v1.NodeSelectorOpNotIn, Values: []string{"general"}}}
+
+	expected := []v1.NodeSelectorTerm{
+		{
+			MatchExpressions: []v1.NodeSelectorRequirement{osExpression, {Key: "legacy", Operator: v1.NodeSelectorOpExists}},
+			MatchFields:      nameField,
+		},
+		{
+			MatchExpressions: []v1.NodeSelectorRequirement{osExpression, {Key: "pool", Operator: v1.NodeSelectorOpNotIn, Values: []string{"batch", "gpu"}}},
+			MatchFields:      nameField,
+		},
+	}
+
+	suite.Equal(expected, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
+
+func (suite *KubeTargetingTestSuite) TestAffinityExcludedNodesSingleValueFields() {
+	//prepare
+	targeting := &k8s.NodeTargeting{
+		ExcludedNodes: []string{"gpu", "batch", "windows"},
+	}
+
+	//act
+	affinity, err := targeting.Affinity()
+	suite.NoError(err)
+
+	// assert
+	expected := []v1.NodeSelectorTerm{
+		{
+			MatchFields: []v1.NodeSelectorRequirement{
+				{Key: k8s.NODE_NAME_FIELD, Operator: v1.NodeSelectorOpNotIn, Values: []string{"gpu"}},
+				{Key: k8s.NODE_NAME_FIELD, Operator: v1.NodeSelectorOpNotIn, Values: []string{"batch"}},
+				{Key: k8s.NODE_NAME_FIELD, Operator: v1.NodeSelectorOpNotIn, Values: []string{"windows"}},
+			},
+		},
+	}
+
+	suite.Equal(expected, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	var matchingNodes []*k8s.NodeSummary
+	for _, node := range suite.Nodes {
+		matches, err := k8s.MatchesNodePlacement(node, nil, affinity)
+		suite.NoError(err)
+
+		if matches {
+			matchingNodes = append(matchingNodes, node)
+		}
+	}
+
+	suite.Equal([]*k8s.NodeSummary{suite.Nodes[2]}, matchingNodes)
+}
+
+func (suite *KubeTargetingTestSuite) TestAffinityMatchesFilterNodes() {
+	//prepare
+	selector, err := labels.Parse("kubernetes.io/os=linux")
+	suite.NoError(err)
+
+	excludeSelector, err := labels.Parse("pool=gpu,spot!=true")
+	suite.NoError(err)
+
+	targeting := &k8s.NodeTargeting{
+		Selector:        selector,
+		ExcludeSelector: excludeSelector,
+		ExcludedNodes:   []string{"batch", "windows"},
+	}
+
+	affinity, err := targeting.Affinity()
+	suite.NoError(err)
+
+	//act
+	var matchingNodes []*k8s.NodeSummary
+	for _, node := range suite.Nodes {
+		matches, err := k8s.MatchesNodePlacement(node, nil, affinity)
+		suite.NoError(err)
+
+		if matches {
+			matchingNodes = append(matchingNodes, node)
+		}
+	}
+
+	// assert
+	suite.Equal(targeting.FilterNodes(suite.Nodes), matchingNodes)
+}
+
+func (suite *KubeTargetingTestSuite) TestAffinityUnsupported() {
+	//prepare
+	excludeSelector, err := labels.Parse("cores>4")
+	suite.NoError(err)
+
+	targeting := &k8s.NodeTargeting{
+		ExcludeSelector: excludeSelector,
+	}
+
+	//act
+	_, err = targeting.Affinity()
+
+	// assert
+	suite.EqualError(err, "unsupported operator \"gt\" in node selector \"cores>4\"")
+}
+
+func (suite *KubeTargetingTestSuite) TestMatchesNodePlacementNodeSelector() {
+	//act
+	linuxMatches, err := k8s.MatchesNodePlacement(suite.Nodes[0], map[string]string{"kubernetes.io/os": "linux"}, nil)
+	suite.NoError(err)
+
+	windowsMatches, err := k8s.MatchesNodePlacement(suite.Nodes[3], map[string]string{"kubernetes.io/os": "linux"}, nil)
+	suite.NoError(err)
+
+	// assert
+	suite.True(linuxMatches)
+	suite.False(windowsMatches)
+}
+
+func (suite *KubeTargetingTestSuite) TestExcludeUnsupportedNodesByLabels() {
+	//prepare
+	linux := &k8s.NodeSummary{
+		Name:            "linux",
+		Architecture:    "amd64",
+		OperatingSystem: "linux",
+		Kernel:          "5.4.0",
+		Labels:          map[string]string{k8s.OS_LABEL: "linux", k8s.ARCH_LABEL: "amd64"},
+	}
+
+	windows := &k8s.NodeSummary{
+		Name:            "windows",
+		Architecture:    "amd64",
+		OperatingSystem: "windows",
+		Kernel:          "10.0.17763.4377",
+		Labels:          map[string]string{k8s.OS_LABEL: "windows", k8s.ARCH_LABEL: "amd64"},
+	}
+
+	nodesReport := k8s.DefaultNodeRequirements.GenerateNodeReport([]*k8s.NodeSummary{linux, windows})
+
+	targeting := &k8s.NodeTargeting{}
+
+	//act
+	unsupportedNodes, err := targeting.ExcludeUnsupportedNodes(k8s.DefaultNodeRequirements, nodesReport)
+	suite.NoError(err)
+
+	// assert
+	suite.Len(unsupportedNodes, 1)
+	suite.Equal("windows", unsupportedNodes[0].Name)
+	suite.Empty(targeting.ExcludedNodes)
+	suite.Equal("kubernetes.io/os in (linux)", targeting.Selector.String())
+}
+
+func (suite *KubeTargetingTestSuite) TestExcludeUnsupportedNodesByName() {
+	//prepare
+	linux := &k8s.NodeSummary{
+		Name:            "linux",
+		Architecture:    "amd64",
+		OperatingSystem: "linux",
+		Kernel:          "5.4.0",
+	}
+
+	windows := &k8s.NodeSummary{
+		Name:            "windows",
+		Architecture:    "amd64",
+		OperatingSystem: "windows",
+		Kernel:          "10.0.17763.4377",
+		Labels:          map[string]string{k8s.OS_LABEL: "windows"},
+	}
+
+	fargate := &k8s.NodeSummary{
+		Name:            "fargate",
+		Provider:        "aws://eu-west-3/fargate-i-53df4efedd",
+		Architecture:    "amd64",
+		OperatingSystem: "linux",
+		Kernel:          "5.4.0",
+		Labels:          map[string]string{k8s.EKS_COMPUTE_TYPE_LABEL: "fargate"},
+	}
+
+	nodesReport := k8s.DefaultNodeRequirements.GenerateNodeReport([]*k8s.NodeSummary{linux, windows, fargate})
+
+	targeting := &k8s.NodeTargeting{}
+
+	//act
+	unsupportedNodes, err := targeting.ExcludeUnsupportedNodes(k8s.DefaultNodeRequirements, nodesReport)
+	suite.NoError(err)
+
+	// assert
+	suite.Len(unsupportedNodes, 2)
+	suite.Equal([]string{"windows"}, targeting.ExcludedNodes)
+	suite.Equal("eks.amazonaws.com/compute-type notin (fargate)", targeting.Selector.String())
+	suite.Equal([]*k8s.NodeSummary{linux}, targeting.FilterNodes([]*k8s.NodeSummary{linux, windows, fargate}))
+}
+
+func (suite *KubeTargetingTestSuite) TestExcludeUnsupportedNodesNone() {
+	//prepare
+	nodesReport := &k8s.NodesReport{
+		CompatibleNodes: suite.Nodes,
+	}
+
+	targeting := &k8s.NodeTargeting{}
+
+	//act
+	unsupportedNodes, err := targeting.ExcludeUnsupportedNodes(k8s.DefaultNodeRequirements, nodesReport)
+
+	// assert
+	suite.NoError(err)
+	suite.Empty(unsupportedNodes)
+	suite.True(targeting.IsEmpty())
+}
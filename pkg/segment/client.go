@@ -5,11 +5,18 @@ import (
 	"log"
 
 	"github.com/segmentio/analytics-go/v3"
+	"groundcover.com/pkg/telemetry"
+)
+
+const (
+	PROPERTIES_REDACTION_ROOT = "segment.properties"
+	TRAITS_REDACTION_ROOT     = "segment.traits"
 )
 
 var (
-	client   analytics.Client
-	WriteKey string = "FPPzr8mdiYq9Ry2YOEVFN751DvSdwwUZ"
+	client       analytics.Client
+	telemetryLog *telemetry.Log
+	WriteKey     string = "FPPzr8mdiYq9Ry2YOEVFN751DvSdwwUZ"
 )
 
 func GetConfig(appName, version string) analytics.Config {
@@ -36,6 +43,55 @@ func Init(config analytics.Config) error {
 	return nil
 }
 
+// SetTelemetryLog logs every message enqueued from now on, including the ones dropped when the client isn't initialized
+func SetTelemetryLog(log *telemetry.Log) {
+	telemetryLog = log
+}
+
 func Close() error {
+	if client == nil {
+		return nil
+	}
+
 	return client.Close()
 }
+
+// enqueue sends the message when the client is initialized, telemetry is disabled otherwise
+func enqueue(message analytics.Message) error {
+	message = redactMessage(message)
+
+	isEnabled := client != nil
+	telemetryLog.Write(telemetry.SEGMENT_DESTINATION, isEnabled, message)
+
+	if !isEnabled {
+		return nil
+	}
+
+	return client.Enqueue(message)
+}
+
+// redactMessage redacts the event properties and the user and group traits before they are logged or sent
+func redactMessage(message analytics.Message) analytics.Message {
+	switch typedMessage := message.(type) {
+	case analytics.Track:
+		typedMessage.Properties = redactMap(PROPERTIES_REDACTION_ROOT, typedMessage.Properties)
+		return typedMessage
+	case analytics.Identify:
+		typedMessage.Traits = redactMap(TRAITS_REDACTION_ROOT, typedMessage.Traits)
+		return typedMessage
+	case analytics.Group:
+		typedMessage.Traits = redactMap(TRAITS_REDACTION_ROOT, typedMessage.Traits)
+		return typedMessage
+	default:
+		return message
+	}
+}
+
+func redactMap(root string, values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	redactedValues, _ := telemetry.Redact(root, values).(map[string]interface{})
+	return redactedValues
+}
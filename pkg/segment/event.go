@@ -97,5 +97,5 @@ func (event *EventHandler) enqueueWithStatus(status string) error {
 	event.Properties.Set(SCOPE_PROPERTY_NAME, scope)
 	event.Properties.Set(SESSION_ID_PROPERTY_NAME, sessionId)
 	event.Event = fmt.Sprintf(EVENT_WITH_STATUS_FORMAT, event.name, status)
-	return client.Enqueue(event.Track)
+	return enqueue(event.Track)
 }
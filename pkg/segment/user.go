@@ -28,11 +28,11 @@ func NewUser(email string, org string) error {
 		Traits:  analytics.NewTraits().SetEmail(email).SetName(tenantUniqueId),
 	}
 
-	if err = client.Enqueue(user); err != nil {
+	if err = enqueue(user); err != nil {
 		return err
 	}
 
-	if err = client.Enqueue(orgGroup); err != nil {
+	if err = enqueue(orgGroup); err != nil {
 		return err
 	}
 
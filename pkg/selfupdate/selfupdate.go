@@ -27,7 +27,13 @@ const (
 )
 
 var (
-	devVersion = semver.MustParse("0.0.0-dev")
+	devVersion         = semver.MustParse("0.0.0-dev")
+	ApplyPollingPolicy = ui.PollingPolicy{
+		Phase:    "update",
+		Interval: APPLY_POLLING_INTERVAL,
+		Timeout:  APPLY_POLLING_TIMEOUT,
+		Retries:  APPLY_POLLING_RETRIES,
+	}
 )
 
 type SelfUpdater struct {
@@ -112,7 +118,7 @@ func (selfUpdater *SelfUpdater) Apply(ctx context.Context) error {
 	spinner.Start()
 	defer spinner.WriteStop()
 
-	err = spinner.Poll(ctx, selfUpdater.apply, APPLY_POLLING_INTERVAL, APPLY_POLLING_TIMEOUT, APPLY_POLLING_RETRIES)
+	err = spinner.PollWithPolicy(ctx, selfUpdater.apply, ApplyPollingPolicy)
 
 	if err == nil {
 		return nil
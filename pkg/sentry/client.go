@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/getsentry/sentry-go"
+	"groundcover.com/pkg/telemetry"
 )
 
 const (
@@ -21,6 +22,7 @@ const (
 	NODES_COUNT_TAG              = "nodes.count"
 	EXPECTED_NODES_COUNT_TAG     = "nodes.expected_count"
 	RUNNING_SENSORS_TAG          = "nodes.running_sensors"
+	BREADCRUMBS_REDACTION_ROOT   = "breadcrumbs"
 	FLUSH_TIMEOUT                = time.Second * 2
 )
 
@@ -35,6 +37,45 @@ func GetSentryClientOptions(appName, environment, version string) sentry.ClientO
 	}
 }
 
+// WithTelemetry logs every event before it is sent, when telemetry is disabled the events are only logged and then dropped
+func WithTelemetry(options sentry.ClientOptions, isEnabled bool, telemetryLog *telemetry.Log) sentry.ClientOptions {
+	if !isEnabled {
+		options.Dsn = ""
+	}
+
+	options.BeforeSend = func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+		redactEvent(event)
+		telemetryLog.Write(telemetry.SENTRY_DESTINATION, isEnabled, event)
+
+		if !isEnabled {
+			return nil
+		}
+
+		return event
+	}
+
+	return options
+}
+
+// redactEvent redacts the captured messages, errors, breadcrumbs and tags, the contexts are already redacted when set on the scope
+func redactEvent(event *sentry.Event) {
+	event.Message = telemetry.RedactText(event.Message)
+	for index := range event.Exception {
+		event.Exception[index].Value = telemetry.RedactText(event.Exception[index].Value)
+	}
+
+	for _, breadcrumb := range event.Breadcrumbs {
+		breadcrumb.Message = telemetry.RedactText(breadcrumb.Message)
+		if breadcrumb.Data != nil {
+			breadcrumb.Data, _ = telemetry.Redact(BREADCRUMBS_REDACTION_ROOT, breadcrumb.Data).(map[string]interface{})
+		}
+	}
+
+	for key, value := range event.Tags {
+		event.Tags[key] = telemetry.RedactText(value)
+	}
+}
+
 func SetTagOnCurrentScope(key, value string) {
 	sentry.CurrentHub().Scope().SetTag(key, value)
 }
@@ -1,6 +1,8 @@
 package sentry_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/telemetry"
 )
 
 type SentryClientTestSuite struct {
@@ -80,3 +83,78 @@ func (suite *SentryClientTestSuite) TestSetOnCurrentScopeSuccess() {
 	suite.Equal(transaction, event.Transaction)
 	suite.Equal(map[string]string{tagName: tagValue}, event.Tags)
 }
+
+func (suite *SentryClientTestSuite) TestWithTelemetryDisabledDropsEvents() {
+	//prepare
+	transport := &TransportMock{}
+	logBuffer := &bytes.Buffer{}
+
+	clientOptions := sentry_utils.WithTelemetry(sentry_utils.GetSentryClientOptions("cli", "prod", "1.0.0"), false, telemetry.NewLog(logBuffer))
+	clientOptions.Transport = transport
+	client, _ := sentry.NewClient(clientOptions)
+
+	//act
+	client.CaptureMessage("disabled", nil, sentry.NewScope())
+
+	// assert
+	var record telemetry.Record
+	suite.NoError(json.Unmarshal(logBuffer.Bytes(), &record))
+
+	suite.Empty(clientOptions.Dsn)
+	suite.Empty(transport.Events())
+	suite.Equal(telemetry.SENTRY_DESTINATION, record.Destination)
+	suite.False(record.Sent)
+	suite.Equal("disabled", record.Payload.(map[string]interface{})["message"])
+}
+
+func (suite *SentryClientTestSuite) TestWithTelemetryEnabledLogsSentEvents() {
+	//prepare
+	transport := &TransportMock{}
+	logBuffer := &bytes.Buffer{}
+
+	clientOptions := sentry_utils.WithTelemetry(sentry_utils.GetSentryClientOptions("cli", "prod", "1.0.0"), true, telemetry.NewLog(logBuffer))
+	clientOptions.Transport = transport
+	client, _ := sentry.NewClient(clientOptions)
+
+	//act
+	client.CaptureMessage("enabled", nil, sentry.NewScope())
+
+	// assert
+	var record telemetry.Record
+	suite.NoError(json.Unmarshal(logBuffer.Bytes(), &record))
+
+	suite.Equal(sentry_utils.Dsn, clientOptions.Dsn)
+	suite.Len(transport.Events(), 1)
+	suite.True(record.Sent)
+	suite.Equal("enabled", record.Payload.(map[string]interface{})["message"])
+}
+
+func (suite *SentryClientTestSuite) TestWithTelemetryRedactsBreadcrumbsAndTags() {
+	//prepare
+	transport := &TransportMock{}
+	logBuffer := &bytes.Buffer{}
+
+	clientOptions := sentry_utils.WithTelemetry(sentry_utils.GetSentryClientOptions("cli", "prod", "1.0.0"), true, telemetry.NewLog(logBuffer))
+	clientOptions.Transport = transport
+	client, _ := sentry.NewClient(clientOptions)
+
+	scope := sentry.NewScope()
+	scope.SetTag("command", "deploy --api-key secret-key")
+	scope.AddBreadcrumb(&sentry.Breadcrumb{
+		Message: "Authorization: Bearer secret-token",
+		Data:    map[string]interface{}{"password": "secret-password", "name": "groundcover"},
+	}, 10)
+
+	//act
+	client.CaptureMessage("enabled", nil, scope)
+
+	// assert
+	events := transport.Events()
+	suite.Len(events, 1)
+
+	event := events[0]
+	suite.Equal("deploy --api-key "+telemetry.REDACTED_VALUE, event.Tags["command"])
+	suite.Len(event.Breadcrumbs, 1)
+	suite.Equal("Authorization: Bearer "+telemetry.REDACTED_VALUE, event.Breadcrumbs[0].Message)
+	suite.Equal(map[string]interface{}{"password": telemetry.REDACTED_VALUE, "name": "groundcover"}, event.Breadcrumbs[0].Data)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/getsentry/sentry-go"
 	"groundcover.com/pkg/helm"
 	"groundcover.com/pkg/k8s"
+	"groundcover.com/pkg/telemetry"
 	"groundcover.com/pkg/ui"
 )
 
@@ -22,6 +23,11 @@ type SentryContext interface {
 	SetOnCurrentScope()
 }
 
+// setContextOnCurrentScope sets the redacted json representation of the context, so credentials never reach the scope
+func setContextOnCurrentScope(name string, context interface{}) {
+	sentry.CurrentHub().Scope().SetContext(name, telemetry.Redact(name, context))
+}
+
 type CommandContext struct {
 	Name string     `json:",omitempty"`
 	Took string     `json:",omitempty"`
@@ -37,7 +43,7 @@ func NewCommandContext(start time.Time) *CommandContext {
 }
 
 func (context CommandContext) SetOnCurrentScope() {
-	sentry.CurrentHub().Scope().SetContext(COMMAND_CONTEXT_NAME, &context)
+	setContextOnCurrentScope(COMMAND_CONTEXT_NAME, &context)
 }
 
 type KubeContext struct {
@@ -85,7 +91,7 @@ func (context *KubeContext) SetNodesSamples(nodesReport *k8s.NodesReport) {
 }
 
 func (context KubeContext) SetOnCurrentScope() {
-	sentry.CurrentHub().Scope().SetContext(KUBE_CONTEXT_NAME, &context)
+	setContextOnCurrentScope(KUBE_CONTEXT_NAME, &context)
 }
 
 type HelmContext struct {
@@ -113,7 +119,7 @@ func NewHelmContext(releaseName, chartName, repoUrl string) *HelmContext {
 }
 
 func (context HelmContext) SetOnCurrentScope() {
-	sentry.CurrentHub().Scope().SetContext(HELM_CONTEXT_NAME, &context)
+	setContextOnCurrentScope(HELM_CONTEXT_NAME, &context)
 }
 
 type SelfUpdateContext struct {
@@ -129,5 +135,5 @@ func NewSelfUpdateContext(currentVersion, latestVersion semver.Version) *SelfUpd
 }
 
 func (context SelfUpdateContext) SetOnCurrentScope() {
-	sentry.CurrentHub().Scope().SetContext(SELF_UPDATE_CONTEXT_NAME, &context)
+	setContextOnCurrentScope(SELF_UPDATE_CONTEXT_NAME, &context)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"groundcover.com/pkg/k8s"
 	sentry_utils "groundcover.com/pkg/sentry"
+	"groundcover.com/pkg/telemetry"
 	"groundcover.com/pkg/ui"
 	v1 "k8s.io/api/core/v1"
 )
@@ -39,6 +40,16 @@ func TestSentryContextSuite(t *testing.T) {
 	suite.Run(t, &SentryContextTestSuite{})
 }
 
+// contextValue is the json representation of the context, which is what is set on the scope after redaction
+func contextValue(context interface{}) interface{} {
+	var value interface{}
+
+	data, _ := json.Marshal(context)
+	json.Unmarshal(data, &value)
+
+	return value
+}
+
 func (suite *SentryContextTestSuite) TestKubeContexJsonOmitEmpty() {
 	//prepare
 	sentryContext := &sentry_utils.KubeContext{}
@@ -69,7 +80,7 @@ func (suite *SentryContextTestSuite) TestKubeContextSetOnCurrentScopeSuccess() {
 
 	// assert
 	expect := map[string]interface{}{
-		"kubernetes": &sentry_utils.KubeContext{
+		"kubernetes": contextValue(&sentry_utils.KubeContext{
 			NodesCount:                nodesCount,
 			Kubeconfig:                kubeconfig,
 			Kubecontext:               kubecontext,
@@ -77,7 +88,7 @@ func (suite *SentryContextTestSuite) TestKubeContextSetOnCurrentScopeSuccess() {
 			CompatibleNodeSamples:     nil,
 			IncompatibleNodeSamples:   nil,
 			ClusterReport:             nil,
-		},
+		}),
 	}
 
 	event := suite.Transport.lastEvent
@@ -179,7 +190,7 @@ func (suite *SentryContextTestSuite) TestHelmContextSetOnCurrentScopeSuccess() {
 
 	// assert
 	expect := map[string]interface{}{
-		"helm": &sentry_utils.HelmContext{
+		"helm": contextValue(&sentry_utils.HelmContext{
 			Upgrade:              true,
 			RepoUrl:              repoUrl,
 			ChartName:            chartName,
@@ -189,7 +200,7 @@ func (suite *SentryContextTestSuite) TestHelmContextSetOnCurrentScopeSuccess() {
 			ResourcesPresets:     resourcesPresets,
 			RunningSensors:       runningSensors,
 			PreviousChartVersion: previousChartVersion,
-		},
+		}),
 	}
 
 	event := suite.Transport.lastEvent
@@ -211,10 +222,10 @@ func (suite *SentryContextTestSuite) TestSelfUpdateContextSetOnCurrentScopeSucce
 
 	// assert
 	expect := map[string]interface{}{
-		"cli-update": &sentry_utils.SelfUpdateContext{
+		"cli-update": contextValue(&sentry_utils.SelfUpdateContext{
 			CurrentVersion: currentVersion,
 			LatestVersion:  lastestVersion,
-		},
+		}),
 	}
 
 	event := suite.Transport.lastEvent
@@ -235,11 +246,11 @@ func (suite *SentryContextTestSuite) TestCommandContextSetOnCurrentScopeSuccess(
 
 	// assert
 	expect := map[string]interface{}{
-		"command": &sentry_utils.CommandContext{
+		"command": contextValue(&sentry_utils.CommandContext{
 			Name: "test",
 			Took: "0s",
 			Log:  ui.NewWriter(),
-		},
+		}),
 	}
 
 	event := suite.Transport.lastEvent
@@ -247,3 +258,31 @@ func (suite *SentryContextTestSuite) TestCommandContextSetOnCurrentScopeSuccess(
 
 	suite.Equal(expect, event.Contexts)
 }
+
+func (suite *SentryContextTestSuite) TestHelmContextSetOnCurrentScopeRedactsValuesOverride() {
+	//prepare
+	sentryContext := sentry_utils.NewHelmContext("groundcover", "groundcover", "https://helm.groundcover.com")
+	sentryContext.ValuesOverride = map[string]interface{}{
+		"global": map[string]interface{}{
+			"groundcover_token": uuid.New().String(),
+			"logs":              map[string]interface{}{"host": "logs.internal"},
+		},
+	}
+
+	//act
+	sentryContext.SetOnCurrentScope()
+	sentry.CaptureMessage("helm context")
+
+	// assert
+	expect := map[string]interface{}{
+		"global": map[string]interface{}{
+			"groundcover_token": telemetry.REDACTED_VALUE,
+			"logs":              map[string]interface{}{"host": "logs.internal"},
+		},
+	}
+
+	event := suite.Transport.lastEvent
+	sentry.CurrentHub().Scope().RemoveContext(sentry_utils.HELM_CONTEXT_NAME)
+
+	suite.Equal(expect, event.Contexts[sentry_utils.HELM_CONTEXT_NAME].(map[string]interface{})["ValuesOverride"])
+}
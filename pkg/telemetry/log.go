@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	SENTRY_DESTINATION  = "sentry"
+	SEGMENT_DESTINATION = "segment"
+	LOG_FILE_MODE       = 0600
+)
+
+// Record is a single telemetry payload, with whether it was actually sent or only logged because telemetry is disabled
+type Record struct {
+	Time        time.Time   `json:"time"`
+	Destination string      `json:"destination"`
+	Sent        bool        `json:"sent"`
+	Payload     interface{} `json:"payload"`
+}
+
+// Log writes every telemetry payload as a json line, a nil log discards them
+type Log struct {
+	lock    sync.Mutex
+	out     io.Writer
+	encoder *json.Encoder
+}
+
+func NewLog(out io.Writer) *Log {
+	return &Log{
+		out:     out,
+		encoder: json.NewEncoder(out),
+	}
+}
+
+func OpenLog(path string) (*Log, error) {
+	var err error
+
+	var file *os.File
+	if file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, LOG_FILE_MODE); err != nil {
+		return nil, err
+	}
+
+	return NewLog(file), nil
+}
+
+func (log *Log) Write(destination string, sent bool, payload interface{}) error {
+	if log == nil {
+		return nil
+	}
+
+	log.lock.Lock()
+	defer log.lock.Unlock()
+
+	return log.encoder.Encode(Record{
+		Time:        time.Now(),
+		Destination: destination,
+		Sent:        sent,
+		Payload:     payload,
+	})
+}
+
+func (log *Log) Close() error {
+	if log == nil {
+		return nil
+	}
+
+	if closer, ok := log.out.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
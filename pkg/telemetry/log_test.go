@@ -0,0 +1,65 @@
+package telemetry_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/telemetry"
+)
+
+type TelemetryLogTestSuite struct {
+	suite.Suite
+}
+
+func TestTelemetryLogTestSuite(t *testing.T) {
+	suite.Run(t, &TelemetryLogTestSuite{})
+}
+
+func (suite *TelemetryLogTestSuite) TestWriteJsonLines() {
+	// prepare
+	path := filepath.Join(suite.T().TempDir(), "telemetry.log")
+
+	log, err := telemetry.OpenLog(path)
+	suite.Require().NoError(err)
+
+	// act
+	suite.NoError(log.Write(telemetry.SEGMENT_DESTINATION, true, map[string]string{"event": "deploy_start"}))
+	suite.NoError(log.Write(telemetry.SENTRY_DESTINATION, false, map[string]string{"message": "deploy executed successfully"}))
+	suite.NoError(log.Close())
+
+	// assert
+	file, err := os.Open(path)
+	suite.Require().NoError(err)
+	defer file.Close()
+
+	var records []telemetry.Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record telemetry.Record
+		suite.NoError(json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+
+	suite.Len(records, 2)
+	suite.Equal(telemetry.SEGMENT_DESTINATION, records[0].Destination)
+	suite.True(records[0].Sent)
+	suite.Equal(map[string]interface{}{"event": "deploy_start"}, records[0].Payload)
+	suite.Equal(telemetry.SENTRY_DESTINATION, records[1].Destination)
+	suite.False(records[1].Sent)
+}
+
+func (suite *TelemetryLogTestSuite) TestNilLogDiscards() {
+	// prepare
+	var log *telemetry.Log
+
+	// act
+	err := log.Write(telemetry.SEGMENT_DESTINATION, false, "payload")
+
+	// assert
+	suite.NoError(err)
+	suite.NoError(log.Close())
+}
@@ -0,0 +1,167 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	REDACTED_VALUE     = "<redacted>"
+	KEY_PATH_SEPARATOR = "."
+	KEY_PATH_WILDCARD  = "*"
+)
+
+var (
+	// values of keys matching these, e.g. global.groundcover_token, are credentials
+	SensitiveKeyRegex = regexp.MustCompile(`(?i)(token|password|secret|api_?key|credentials)`)
+
+	// token like values in free text, such as the cli transcript and error messages
+	builtinTextRules = []textRule{
+		{regex: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), replacement: REDACTED_VALUE},
+		{regex: regexp.MustCompile(`(?i)(\b(?:bearer|basic)\s+)[A-Za-z0-9._~+/=-]+`), replacement: "${1}" + REDACTED_VALUE},
+		{regex: regexp.MustCompile(`(?i)((?:token|password|secret|api[_-]?key|credentials)[A-Za-z0-9_-]*["']?\s*[:=]\s*["']?)[^\s"',}]+`), replacement: "${1}" + REDACTED_VALUE},
+		{regex: regexp.MustCompile(`(?i)(--[a-z-]*(?:token|password|secret|api-key)[a-z-]*\s+)[^\s-]\S*`), replacement: "${1}" + REDACTED_VALUE},
+	}
+
+	redactor = &Redactor{textRules: builtinTextRules}
+)
+
+type textRule struct {
+	regex       *regexp.Regexp
+	replacement string
+}
+
+// Redactor replaces credentials in telemetry payloads: values of token like keys, values of the configured key paths,
+// and token like or configured patterns in strings
+type Redactor struct {
+	keyPaths  [][]string
+	textRules []textRule
+}
+
+// NewRedactor extends the builtin rules with dotted key paths, where * matches any key, and regular expressions whose matches are redacted
+func NewRedactor(keyPaths []string, patterns []string) (*Redactor, error) {
+	var err error
+
+	textRules := append([]textRule{}, builtinTextRules...)
+	for _, pattern := range patterns {
+		var regex *regexp.Regexp
+		if regex, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		textRules = append(textRules, textRule{regex: regex, replacement: REDACTED_VALUE})
+	}
+
+	var splitKeyPaths [][]string
+	for _, keyPath := range keyPaths {
+		if keyPath = strings.TrimSpace(keyPath); keyPath == "" {
+			continue
+		}
+		splitKeyPaths = append(splitKeyPaths, strings.Split(keyPath, KEY_PATH_SEPARATOR))
+	}
+
+	return &Redactor{keyPaths: splitKeyPaths, textRules: textRules}, nil
+}
+
+// SetRedactor replaces the redactor applied to every telemetry payload
+func SetRedactor(newRedactor *Redactor) {
+	redactor = newRedactor
+}
+
+func Redact(root string, value interface{}) interface{} {
+	return redactor.Redact(root, value)
+}
+
+func RedactText(text string) string {
+	return redactor.RedactText(text)
+}
+
+// IsConfiguredKeyPath returns whether the dotted key path matches one of the configured key paths
+func IsConfiguredKeyPath(keyPath string) bool {
+	return redactor.isConfiguredKeyPath(strings.Split(keyPath, KEY_PATH_SEPARATOR))
+}
+
+// Redact returns the json representation of the value with its credentials replaced, key paths are matched starting from the dotted root.
+// A value which can't be represented as json is redacted entirely
+func (redactor *Redactor) Redact(root string, value interface{}) interface{} {
+	var err error
+
+	var data []byte
+	if data, err = json.Marshal(value); err != nil {
+		return REDACTED_VALUE
+	}
+
+	var redactedValue interface{}
+	if err = json.Unmarshal(data, &redactedValue); err != nil {
+		return REDACTED_VALUE
+	}
+
+	return redactor.redactValue(strings.Split(root, KEY_PATH_SEPARATOR), redactedValue)
+}
+
+func (redactor *Redactor) RedactText(text string) string {
+	for _, rule := range redactor.textRules {
+		text = rule.regex.ReplaceAllString(text, rule.replacement)
+	}
+
+	return text
+}
+
+func (redactor *Redactor) redactValue(path []string, value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			childPath := append(path[:len(path):len(path)], key)
+
+			if child != nil && redactor.isRedactedKey(childPath, child) {
+				typedValue[key] = REDACTED_VALUE
+				continue
+			}
+
+			typedValue[key] = redactor.redactValue(childPath, child)
+		}
+	case []interface{}:
+		for index, child := range typedValue {
+			typedValue[index] = redactor.redactValue(path, child)
+		}
+	case string:
+		return redactor.RedactText(typedValue)
+	}
+
+	return value
+}
+
+// isRedactedKey redacts the configured key paths entirely, token like keys are only redacted when they aren't tables
+func (redactor *Redactor) isRedactedKey(path []string, value interface{}) bool {
+	if redactor.isConfiguredKeyPath(path) {
+		return true
+	}
+
+	_, isTable := value.(map[string]interface{})
+	return !isTable && SensitiveKeyRegex.MatchString(path[len(path)-1])
+}
+
+func (redactor *Redactor) isConfiguredKeyPath(path []string) bool {
+	for _, keyPath := range redactor.keyPaths {
+		if matchKeyPath(keyPath, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchKeyPath(keyPath, path []string) bool {
+	if len(keyPath) != len(path) {
+		return false
+	}
+
+	for index, key := range keyPath {
+		if key != KEY_PATH_WILDCARD && key != path[index] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	TIMEOUT_FLAG             = "timeout"
+	PHASE_TIMEOUT_KEY_FORMAT = "%s-timeout"
+	PHASE_RETRIES_KEY_FORMAT = "%s-retries"
+	ENV_PREFIX               = "GROUNDCOVER"
+)
+
+// PollingPolicy holds the default interval, timeout and retries of a polling phase,
+// the defaults can be overridden by the global timeout or by the phase specific keys
+type PollingPolicy struct {
+	Phase    string
+	Interval time.Duration
+	Timeout  time.Duration
+	Retries  int
+}
+
+func (policy PollingPolicy) TimeoutKey() string {
+	return fmt.Sprintf(PHASE_TIMEOUT_KEY_FORMAT, policy.Phase)
+}
+
+func (policy PollingPolicy) RetriesKey() string {
+	return fmt.Sprintf(PHASE_RETRIES_KEY_FORMAT, policy.Phase)
+}
+
+// IsPolled returns whether the phase polls at an interval, watched phases only have a timeout and no retries
+func (policy PollingPolicy) IsPolled() bool {
+	return policy.Interval > 0
+}
+
+// Resolve applies the configured overrides, phase specific keys take precedence over the global timeout
+func (policy PollingPolicy) Resolve() PollingPolicy {
+	resolved := policy
+
+	timeout := viper.GetDuration(policy.TimeoutKey())
+	if timeout <= 0 {
+		timeout = viper.GetDuration(TIMEOUT_FLAG)
+	}
+
+	if timeout > 0 && timeout != policy.Timeout {
+		resolved.Timeout = timeout
+
+		// retries are scaled with the timeout, so a longer timeout isn't cut short by the retries limit
+		if policy.Timeout > 0 {
+			resolved.Retries = int(math.Ceil(float64(policy.Retries) * float64(timeout) / float64(policy.Timeout)))
+		}
+	}
+
+	if retries := viper.GetInt(policy.RetriesKey()); retries > 0 && policy.IsPolled() {
+		resolved.Retries = retries
+	}
+
+	return resolved
+}
+
+// BindPollingPolicyEnv binds the global and phase specific keys to GROUNDCOVER_ prefixed environment variables
+func BindPollingPolicyEnv(policies ...PollingPolicy) {
+	keys := []string{TIMEOUT_FLAG}
+	for _, policy := range policies {
+		keys = append(keys, policy.TimeoutKey())
+		if policy.IsPolled() {
+			keys = append(keys, policy.RetriesKey())
+		}
+	}
+
+	for _, key := range keys {
+		viper.BindEnv(key, EnvName(key))
+	}
+}
+
+// EnvName returns the GROUNDCOVER_ prefixed environment variable of a key
+func EnvName(key string) string {
+	return fmt.Sprintf("%s_%s", ENV_PREFIX, strings.ToUpper(strings.ReplaceAll(key, "-", "_")))
+}
+
+func (s *Spinner) PollWithPolicy(ctx context.Context, function func() error, policy PollingPolicy) error {
+	resolved := policy.Resolve()
+	return s.Poll(ctx, function, resolved.Interval, resolved.Timeout, resolved.Retries)
+}
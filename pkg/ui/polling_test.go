@@ -0,0 +1,91 @@
+package ui_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/ui"
+)
+
+var testPollingPolicy = ui.PollingPolicy{
+	Phase:    "pvc",
+	Interval: time.Second * 15,
+	Timeout:  time.Minute * 10,
+	Retries:  40,
+}
+
+type PollingPolicyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PollingPolicyTestSuite) TearDownTest() {
+	viper.Reset()
+}
+
+func TestPollingPolicySuite(t *testing.T) {
+	suite.Run(t, &PollingPolicyTestSuite{})
+}
+
+func (suite *PollingPolicyTestSuite) TestResolveDefaults() {
+	//act
+	resolved := testPollingPolicy.Resolve()
+
+	// assert
+	suite.Equal(testPollingPolicy, resolved)
+}
+
+func (suite *PollingPolicyTestSuite) TestResolveGlobalTimeoutScalesRetries() {
+	//prepare
+	viper.Set(ui.TIMEOUT_FLAG, "30m")
+
+	//act
+	resolved := testPollingPolicy.Resolve()
+
+	// assert
+	suite.Equal(time.Minute*30, resolved.Timeout)
+	suite.Equal(120, resolved.Retries)
+	suite.Equal(testPollingPolicy.Interval, resolved.Interval)
+}
+
+func (suite *PollingPolicyTestSuite) TestResolvePhaseOverridesGlobal() {
+	//prepare
+	viper.Set(ui.TIMEOUT_FLAG, time.Minute*30)
+	viper.Set("pvc-timeout", time.Minute*20)
+	viper.Set("pvc-retries", 5)
+
+	//act
+	resolved := testPollingPolicy.Resolve()
+
+	// assert
+	suite.Equal(time.Minute*20, resolved.Timeout)
+	suite.Equal(5, resolved.Retries)
+}
+
+func (suite *PollingPolicyTestSuite) TestResolveFromEnv() {
+	//prepare
+	suite.T().Setenv("GROUNDCOVER_PVC_TIMEOUT", "1h")
+	ui.BindPollingPolicyEnv(testPollingPolicy)
+
+	//act
+	resolved := testPollingPolicy.Resolve()
+
+	// assert
+	suite.Equal(time.Hour, resolved.Timeout)
+	suite.Equal(240, resolved.Retries)
+}
+
+func (suite *PollingPolicyTestSuite) TestResolveWatchedPhaseIgnoresRetries() {
+	//prepare
+	watchedPolicy := ui.PollingPolicy{Phase: "workloads", Timeout: time.Minute * 10}
+	viper.Set("workloads-retries", 5)
+
+	//act
+	resolved := watchedPolicy.Resolve()
+
+	// assert
+	suite.False(watchedPolicy.IsPolled())
+	suite.True(testPollingPolicy.IsPolled())
+	suite.Equal(watchedPolicy, resolved)
+}
@@ -42,6 +42,7 @@ type Spinner struct {
 
 func newSpinner(writer *Writer, message string) *Spinner {
 	cfg := yacspin.Config{
+		Writer:            writer.out,
 		Frequency:         100 * time.Millisecond,
 		Colors:            []string{"fgBlue"},
 		CharSet:           yacspin.CharSets[spinnerCharset],
@@ -4,19 +4,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
 	"github.com/spf13/viper"
+	"k8s.io/utils/strings/slices"
 )
 
 const (
-	ASSUME_YES_FLAG = "yes"
+	ASSUME_YES_FLAG      = "yes"
+	NON_INTERACTIVE_FLAG = "non-interactive"
 )
 
+var ErrNonInteractivePrompt = errors.New("can't prompt in non-interactive mode")
+
 type Writer struct {
+	out    io.Writer
 	writen []string
 }
 
@@ -37,6 +44,18 @@ func NewWriter() *Writer {
 var GlobalWriter = NewWriter()
 var QuietWriter = NewWriter()
 
+func (w *Writer) SetOutput(out io.Writer) {
+	w.out = out
+}
+
+func (w *Writer) output() io.Writer {
+	if w.out == nil {
+		return os.Stdout
+	}
+
+	return w.out
+}
+
 func (w *Writer) MarshalJSON() ([]byte, error) {
 	return json.Marshal((w.Dump()))
 }
@@ -47,29 +66,29 @@ func (w *Writer) Writeln(message string) {
 
 func (w *Writer) Println(message string) {
 	w.addMessage(message)
-	fmt.Println(message)
+	fmt.Fprintln(w.output(), message)
 }
 
 func (w *Writer) PrintlnWithPrefixln(message string) {
 	w.addMessage(message)
-	fmt.Printf("\n%s\n", message)
+	fmt.Fprintf(w.output(), "\n%s\n", message)
 }
 
 func (w *Writer) PrintflnWithPrefixln(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	w.addMessage(message)
-	fmt.Printf("\n%s\n", message)
+	fmt.Fprintf(w.output(), "\n%s\n", message)
 }
 
 func (w *Writer) Printf(format string, args ...interface{}) {
 	formatted := fmt.Sprintf(format, args...)
 	w.addMessage(formatted)
-	fmt.Print(formatted)
+	fmt.Fprint(w.output(), formatted)
 }
 
 func (w *Writer) PrintUrl(message string, url string) {
 	w.addMessage(fmt.Sprintf("%s%s", message, url))
-	fmt.Printf("%s%s\n", message, w.UrlLink(url))
+	fmt.Fprintf(w.output(), "%s%s\n", message, w.UrlLink(url))
 }
 
 func (w *Writer) Errorf(format string, args ...interface{}) error {
@@ -80,37 +99,37 @@ func (w *Writer) Errorf(format string, args ...interface{}) error {
 
 func (w *Writer) PrintSuccessMessage(message string) {
 	w.addMessage(fmt.Sprintf("%s %s", writenStatusOk, message))
-	fmt.Printf("%s %s", greenStatusOk, message)
+	fmt.Fprintf(w.output(), "%s %s", greenStatusOk, message)
 }
 
 func (w *Writer) PrintSuccessMessageln(message string) {
 	w.addMessage(fmt.Sprintf("%s %s", writenStatusOk, message))
-	fmt.Printf("%s %s\n", greenStatusOk, message)
+	fmt.Fprintf(w.output(), "%s %s\n", greenStatusOk, message)
 }
 
 func (w *Writer) PrintErrorMessage(message string) {
 	w.addMessage(fmt.Sprintf("%s %s", writenStatusErr, message))
-	fmt.Printf("%s %s", redStatusErr, message)
+	fmt.Fprintf(w.output(), "%s %s", redStatusErr, message)
 }
 
 func (w *Writer) PrintErrorMessageln(message string) {
 	w.addMessage(fmt.Sprintf("%s %s", writenStatusErr, message))
-	fmt.Printf("%s %s\n", redStatusErr, message)
+	fmt.Fprintf(w.output(), "%s %s\n", redStatusErr, message)
 }
 
 func (w *Writer) PrintWarningMessage(message string) {
 	w.addMessage(fmt.Sprintf("%s %s", writenStatusWarn, message))
-	fmt.Printf("%s %s", statusWarning, message)
+	fmt.Fprintf(w.output(), "%s %s", statusWarning, message)
 }
 
 func (w *Writer) PrintWarningMessageln(message string) {
 	w.addMessage(fmt.Sprintf("%s %s", writenStatusWarn, message))
-	fmt.Printf("%s %s\n", statusWarning, message)
+	fmt.Fprintf(w.output(), "%s %s\n", statusWarning, message)
 }
 
 func (w *Writer) PrintNoticeMessage(message string) {
 	w.addMessage(message)
-	fmt.Printf("🚨 %s", message)
+	fmt.Fprintf(w.output(), "🚨 %s", message)
 }
 
 func (w *Writer) UrlLink(url string) string {
@@ -122,8 +141,18 @@ func (w *Writer) NewSpinner(message string) *Spinner {
 	return newSpinner(w, message)
 }
 
+// IsNonInteractive reports whether prompts must be answered by flags, yes/no prompts are assumed yes
+func IsNonInteractive() bool {
+	return viper.GetBool(NON_INTERACTIVE_FLAG)
+}
+
+// MissingAnswerError names the flag answering a prompt which can't be shown in non-interactive mode
+func MissingAnswerError(message, flag string) error {
+	return fmt.Errorf("%w, %q requires the --%s flag", ErrNonInteractivePrompt, strings.TrimSuffix(message, ":"), flag)
+}
+
 func (w *Writer) YesNoPrompt(message string, defaultValue bool) bool {
-	if viper.GetBool(ASSUME_YES_FLAG) {
+	if viper.GetBool(ASSUME_YES_FLAG) || IsNonInteractive() {
 		return true
 	}
 
@@ -172,6 +201,25 @@ func (w *Writer) SelectPrompt(message string, options []string) string {
 	return response
 }
 
+// SelectFlagPrompt answers the prompt with the flag value when set, otherwise prompts for it,
+// failing in non-interactive mode
+func (w *Writer) SelectFlagPrompt(message string, options []string, flag string) (string, error) {
+	if value := viper.GetString(flag); value != "" {
+		if !slices.Contains(options, value) {
+			return "", fmt.Errorf("invalid --%s %q, available options: %s", flag, value, strings.Join(options, ", "))
+		}
+
+		w.addMessage(fmt.Sprintf("%s %v", message, value))
+		return value, nil
+	}
+
+	if IsNonInteractive() {
+		return "", MissingAnswerError(message, flag)
+	}
+
+	return w.SelectPrompt(message, options), nil
+}
+
 func (w *Writer) timeFormat(message string) string {
 	timeFormatted := time.Now().Format(time.RFC3339)
 
@@ -0,0 +1,69 @@
+package ui_test
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"groundcover.com/pkg/ui"
+)
+
+const testSelectFlag = "backend"
+
+type WriterTestSuite struct {
+	suite.Suite
+}
+
+func (suite *WriterTestSuite) TearDownTest() {
+	viper.Reset()
+}
+
+func TestWriterSuite(t *testing.T) {
+	suite.Run(t, &WriterTestSuite{})
+}
+
+func (suite *WriterTestSuite) TestSelectFlagPromptFromFlag() {
+	//prepare
+	viper.Set(testSelectFlag, "prod")
+
+	//act
+	selected, err := ui.NewWriter().SelectFlagPrompt("Select backend:", []string{"dev", "prod"}, testSelectFlag)
+
+	// assert
+	suite.NoError(err)
+	suite.Equal("prod", selected)
+}
+
+func (suite *WriterTestSuite) TestSelectFlagPromptInvalidFlag() {
+	//prepare
+	viper.Set(testSelectFlag, "staging")
+
+	//act
+	_, err := ui.NewWriter().SelectFlagPrompt("Select backend:", []string{"dev", "prod"}, testSelectFlag)
+
+	// assert
+	suite.EqualError(err, "invalid --backend \"staging\", available options: dev, prod")
+}
+
+func (suite *WriterTestSuite) TestSelectFlagPromptNonInteractive() {
+	//prepare
+	viper.Set(ui.NON_INTERACTIVE_FLAG, true)
+
+	//act
+	_, err := ui.NewWriter().SelectFlagPrompt("Select backend:", []string{"dev", "prod"}, testSelectFlag)
+
+	// assert
+	suite.ErrorIs(err, ui.ErrNonInteractivePrompt)
+	suite.EqualError(err, "can't prompt in non-interactive mode, \"Select backend\" requires the --backend flag")
+}
+
+func (suite *WriterTestSuite) TestYesNoPromptNonInteractive() {
+	//prepare
+	viper.Set(ui.NON_INTERACTIVE_FLAG, true)
+
+	//act
+	answer := ui.NewWriter().YesNoPrompt("Continue?", false)
+
+	// assert
+	suite.True(answer)
+}